@@ -11,6 +11,7 @@ import (
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -76,10 +77,20 @@ func (x *AuthRequest) GetPassword() string {
 }
 
 type AuthResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Короткоживущий access-токен
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// Refresh-токен для /api/auth/refresh; одноразовый, при обмене выдаётся новый
+	RefreshToken string `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	// Срок действия token, RFC3339
+	ExpiresAt string `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Если у пользователя включена 2FA, вход по паролю возвращает вместо токенов
+	// challenge_token: он вместе с кодом обменивается на токены в /api/auth/2fa
+	ChallengeToken string `protobuf:"bytes,4,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	// Срок действия challenge_token, RFC3339
+	ChallengeExpiresAt string `protobuf:"bytes,5,opt,name=challenge_expires_at,json=challengeExpiresAt,proto3" json:"challenge_expires_at,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *AuthResponse) Reset() {
@@ -119,27 +130,55 @@ func (x *AuthResponse) GetToken() string {
 	return ""
 }
 
-type PurchaseRequest struct {
+func (x *AuthResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *AuthResponse) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *AuthResponse) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+func (x *AuthResponse) GetChallengeExpiresAt() string {
+	if x != nil {
+		return x.ChallengeExpiresAt
+	}
+	return ""
+}
+
+type RefreshTokenRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MerchName     string                 `protobuf:"bytes,2,opt,name=merch_name,json=merchName,proto3" json:"merch_name,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PurchaseRequest) Reset() {
-	*x = PurchaseRequest{}
+func (x *RefreshTokenRequest) Reset() {
+	*x = RefreshTokenRequest{}
 	mi := &file_merch_service_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PurchaseRequest) String() string {
+func (x *RefreshTokenRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PurchaseRequest) ProtoMessage() {}
+func (*RefreshTokenRequest) ProtoMessage() {}
 
-func (x *PurchaseRequest) ProtoReflect() protoreflect.Message {
+func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_merch_service_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -151,40 +190,38 @@ func (x *PurchaseRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PurchaseRequest.ProtoReflect.Descriptor instead.
-func (*PurchaseRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RefreshTokenRequest.ProtoReflect.Descriptor instead.
+func (*RefreshTokenRequest) Descriptor() ([]byte, []int) {
 	return file_merch_service_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *PurchaseRequest) GetMerchName() string {
+func (x *RefreshTokenRequest) GetRefreshToken() string {
 	if x != nil {
-		return x.MerchName
+		return x.RefreshToken
 	}
 	return ""
 }
 
-type PurchaseResponse struct {
+type LogoutRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PurchaseResponse) Reset() {
-	*x = PurchaseResponse{}
+func (x *LogoutRequest) Reset() {
+	*x = LogoutRequest{}
 	mi := &file_merch_service_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PurchaseResponse) String() string {
+func (x *LogoutRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PurchaseResponse) ProtoMessage() {}
+func (*LogoutRequest) ProtoMessage() {}
 
-func (x *PurchaseResponse) ProtoReflect() protoreflect.Message {
+func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_merch_service_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -196,47 +233,32 @@ func (x *PurchaseResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PurchaseResponse.ProtoReflect.Descriptor instead.
-func (*PurchaseResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
+func (*LogoutRequest) Descriptor() ([]byte, []int) {
 	return file_merch_service_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *PurchaseResponse) GetSuccess() bool {
-	if x != nil {
-		return x.Success
-	}
-	return false
-}
-
-func (x *PurchaseResponse) GetMessage() string {
-	if x != nil {
-		return x.Message
-	}
-	return ""
-}
-
-type TransferRequest struct {
+type LogoutResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ToUser        int32                  `protobuf:"varint,2,opt,name=to_user,json=toUser,proto3" json:"to_user,omitempty"`
-	Amount        int32                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TransferRequest) Reset() {
-	*x = TransferRequest{}
+func (x *LogoutResponse) Reset() {
+	*x = LogoutResponse{}
 	mi := &file_merch_service_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TransferRequest) String() string {
+func (x *LogoutResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TransferRequest) ProtoMessage() {}
+func (*LogoutResponse) ProtoMessage() {}
 
-func (x *TransferRequest) ProtoReflect() protoreflect.Message {
+func (x *LogoutResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_merch_service_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -248,47 +270,40 @@ func (x *TransferRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TransferRequest.ProtoReflect.Descriptor instead.
-func (*TransferRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use LogoutResponse.ProtoReflect.Descriptor instead.
+func (*LogoutResponse) Descriptor() ([]byte, []int) {
 	return file_merch_service_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *TransferRequest) GetToUser() int32 {
-	if x != nil {
-		return x.ToUser
-	}
-	return 0
-}
-
-func (x *TransferRequest) GetAmount() int32 {
+func (x *LogoutResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Amount
+		return x.Success
 	}
-	return 0
+	return false
 }
 
-type TransferResponse struct {
+type ChangePasswordRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	OldPassword   string                 `protobuf:"bytes,1,opt,name=old_password,json=oldPassword,proto3" json:"old_password,omitempty"`
+	NewPassword   string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TransferResponse) Reset() {
-	*x = TransferResponse{}
+func (x *ChangePasswordRequest) Reset() {
+	*x = ChangePasswordRequest{}
 	mi := &file_merch_service_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TransferResponse) String() string {
+func (x *ChangePasswordRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TransferResponse) ProtoMessage() {}
+func (*ChangePasswordRequest) ProtoMessage() {}
 
-func (x *TransferResponse) ProtoReflect() protoreflect.Message {
+func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_merch_service_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -300,45 +315,48 @@ func (x *TransferResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TransferResponse.ProtoReflect.Descriptor instead.
-func (*TransferResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
+func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
 	return file_merch_service_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *TransferResponse) GetSuccess() bool {
+func (x *ChangePasswordRequest) GetOldPassword() string {
 	if x != nil {
-		return x.Success
+		return x.OldPassword
 	}
-	return false
+	return ""
 }
 
-func (x *TransferResponse) GetMessage() string {
+func (x *ChangePasswordRequest) GetNewPassword() string {
 	if x != nil {
-		return x.Message
+		return x.NewPassword
 	}
 	return ""
 }
 
-type GetInfoRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+type VerifyTwoFactorRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChallengeToken string                 `protobuf:"bytes,1,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	// Код из приложения-аутентификатора или код восстановления
+	Code          string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetInfoRequest) Reset() {
-	*x = GetInfoRequest{}
+func (x *VerifyTwoFactorRequest) Reset() {
+	*x = VerifyTwoFactorRequest{}
 	mi := &file_merch_service_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetInfoRequest) String() string {
+func (x *VerifyTwoFactorRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetInfoRequest) ProtoMessage() {}
+func (*VerifyTwoFactorRequest) ProtoMessage() {}
 
-func (x *GetInfoRequest) ProtoReflect() protoreflect.Message {
+func (x *VerifyTwoFactorRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_merch_service_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -350,35 +368,45 @@ func (x *GetInfoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetInfoRequest.ProtoReflect.Descriptor instead.
-func (*GetInfoRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use VerifyTwoFactorRequest.ProtoReflect.Descriptor instead.
+func (*VerifyTwoFactorRequest) Descriptor() ([]byte, []int) {
 	return file_merch_service_proto_rawDescGZIP(), []int{6}
 }
 
-type Purchase struct {
+func (x *VerifyTwoFactorRequest) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+func (x *VerifyTwoFactorRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type EnrollTOTPRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	MerchName     string                 `protobuf:"bytes,2,opt,name=merch_name,json=merchName,proto3" json:"merch_name,omitempty"`
-	Price         int32                  `protobuf:"varint,3,opt,name=price,proto3" json:"price,omitempty"`
-	PurchaseDate  string                 `protobuf:"bytes,4,opt,name=purchase_date,json=purchaseDate,proto3" json:"purchase_date,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Purchase) Reset() {
-	*x = Purchase{}
+func (x *EnrollTOTPRequest) Reset() {
+	*x = EnrollTOTPRequest{}
 	mi := &file_merch_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Purchase) String() string {
+func (x *EnrollTOTPRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Purchase) ProtoMessage() {}
+func (*EnrollTOTPRequest) ProtoMessage() {}
 
-func (x *Purchase) ProtoReflect() protoreflect.Message {
+func (x *EnrollTOTPRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_merch_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -390,64 +418,35 @@ func (x *Purchase) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Purchase.ProtoReflect.Descriptor instead.
-func (*Purchase) Descriptor() ([]byte, []int) {
+// Deprecated: Use EnrollTOTPRequest.ProtoReflect.Descriptor instead.
+func (*EnrollTOTPRequest) Descriptor() ([]byte, []int) {
 	return file_merch_service_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *Purchase) GetId() int32 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
-
-func (x *Purchase) GetMerchName() string {
-	if x != nil {
-		return x.MerchName
-	}
-	return ""
-}
-
-func (x *Purchase) GetPrice() int32 {
-	if x != nil {
-		return x.Price
-	}
-	return 0
-}
-
-func (x *Purchase) GetPurchaseDate() string {
-	if x != nil {
-		return x.PurchaseDate
-	}
-	return ""
-}
-
-type Transaction struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	SenderId      int32                  `protobuf:"varint,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
-	ReceiverId    int32                  `protobuf:"varint,3,opt,name=receiver_id,json=receiverId,proto3" json:"receiver_id,omitempty"`
-	Amount        int32                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type EnrollTOTPResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Секрет в base32 для ручного ввода
+	Secret string `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	// otpauth:// для QR-кода
+	ProvisioningUri string `protobuf:"bytes,2,opt,name=provisioning_uri,json=provisioningUri,proto3" json:"provisioning_uri,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *Transaction) Reset() {
-	*x = Transaction{}
+func (x *EnrollTOTPResponse) Reset() {
+	*x = EnrollTOTPResponse{}
 	mi := &file_merch_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Transaction) String() string {
+func (x *EnrollTOTPResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Transaction) ProtoMessage() {}
+func (*EnrollTOTPResponse) ProtoMessage() {}
 
-func (x *Transaction) ProtoReflect() protoreflect.Message {
+func (x *EnrollTOTPResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_merch_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -459,71 +458,46 @@ func (x *Transaction) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
-func (*Transaction) Descriptor() ([]byte, []int) {
+// Deprecated: Use EnrollTOTPResponse.ProtoReflect.Descriptor instead.
+func (*EnrollTOTPResponse) Descriptor() ([]byte, []int) {
 	return file_merch_service_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Transaction) GetId() int32 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
-
-func (x *Transaction) GetSenderId() int32 {
-	if x != nil {
-		return x.SenderId
-	}
-	return 0
-}
-
-func (x *Transaction) GetReceiverId() int32 {
-	if x != nil {
-		return x.ReceiverId
-	}
-	return 0
-}
-
-func (x *Transaction) GetAmount() int32 {
+func (x *EnrollTOTPResponse) GetSecret() string {
 	if x != nil {
-		return x.Amount
+		return x.Secret
 	}
-	return 0
+	return ""
 }
 
-func (x *Transaction) GetCreatedAt() string {
+func (x *EnrollTOTPResponse) GetProvisioningUri() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.ProvisioningUri
 	}
 	return ""
 }
 
-type UserInfo struct {
+type ConfirmTOTPRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
-	Balance       int32                  `protobuf:"varint,3,opt,name=balance,proto3" json:"balance,omitempty"`
-	Purchases     []*Purchase            `protobuf:"bytes,4,rep,name=purchases,proto3" json:"purchases,omitempty"`
-	Transactions  []*Transaction         `protobuf:"bytes,5,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserInfo) Reset() {
-	*x = UserInfo{}
+func (x *ConfirmTOTPRequest) Reset() {
+	*x = ConfirmTOTPRequest{}
 	mi := &file_merch_service_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserInfo) String() string {
+func (x *ConfirmTOTPRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserInfo) ProtoMessage() {}
+func (*ConfirmTOTPRequest) ProtoMessage() {}
 
-func (x *UserInfo) ProtoReflect() protoreflect.Message {
+func (x *ConfirmTOTPRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_merch_service_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -535,68 +509,88 @@ func (x *UserInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserInfo.ProtoReflect.Descriptor instead.
-func (*UserInfo) Descriptor() ([]byte, []int) {
+// Deprecated: Use ConfirmTOTPRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmTOTPRequest) Descriptor() ([]byte, []int) {
 	return file_merch_service_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *UserInfo) GetUserId() int32 {
+func (x *ConfirmTOTPRequest) GetCode() string {
 	if x != nil {
-		return x.UserId
+		return x.Code
 	}
-	return 0
+	return ""
 }
 
-func (x *UserInfo) GetUsername() string {
-	if x != nil {
-		return x.Username
-	}
-	return ""
+type ConfirmTOTPResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Одноразовые коды восстановления; показываются только один раз
+	RecoveryCodes []string `protobuf:"bytes,1,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserInfo) GetBalance() int32 {
-	if x != nil {
-		return x.Balance
-	}
-	return 0
+func (x *ConfirmTOTPResponse) Reset() {
+	*x = ConfirmTOTPResponse{}
+	mi := &file_merch_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserInfo) GetPurchases() []*Purchase {
+func (x *ConfirmTOTPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmTOTPResponse) ProtoMessage() {}
+
+func (x *ConfirmTOTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[10]
 	if x != nil {
-		return x.Purchases
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *UserInfo) GetTransactions() []*Transaction {
+// Deprecated: Use ConfirmTOTPResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmTOTPResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ConfirmTOTPResponse) GetRecoveryCodes() []string {
 	if x != nil {
-		return x.Transactions
+		return x.RecoveryCodes
 	}
 	return nil
 }
 
-type GetInfoResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Info          *UserInfo              `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+type DisableTOTPRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Код из приложения или код восстановления
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// Текущий пароль; не нужен пользователям без пароля (вход через OIDC)
+	Password      string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetInfoResponse) Reset() {
-	*x = GetInfoResponse{}
-	mi := &file_merch_service_proto_msgTypes[10]
+func (x *DisableTOTPRequest) Reset() {
+	*x = DisableTOTPRequest{}
+	mi := &file_merch_service_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetInfoResponse) String() string {
+func (x *DisableTOTPRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetInfoResponse) ProtoMessage() {}
+func (*DisableTOTPRequest) ProtoMessage() {}
 
-func (x *GetInfoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_merch_service_proto_msgTypes[10]
+func (x *DisableTOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -607,47 +601,4535 @@ func (x *GetInfoResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetInfoResponse.ProtoReflect.Descriptor instead.
+// Deprecated: Use DisableTOTPRequest.ProtoReflect.Descriptor instead.
+func (*DisableTOTPRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DisableTOTPRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *DisableTOTPRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type DisableTOTPResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableTOTPResponse) Reset() {
+	*x = DisableTOTPResponse{}
+	mi := &file_merch_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableTOTPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableTOTPResponse) ProtoMessage() {}
+
+func (x *DisableTOTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableTOTPResponse.ProtoReflect.Descriptor instead.
+func (*DisableTOTPResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DisableTOTPResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// Session — вход на одном устройстве. Время в RFC3339.
+type Session struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserAgent string                 `protobuf:"bytes,2,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	Ip        string                 `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	CreatedAt string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Последняя выдача токенов (вход или refresh)
+	LastSeenAt string `protobuf:"bytes,5,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	ExpiresAt  string `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Сессия, которой выдан токен текущего запроса
+	Current       bool `protobuf:"varint,7,opt,name=current,proto3" json:"current,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_merch_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Session) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Session) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *Session) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Session) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Session) GetLastSeenAt() string {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return ""
+}
+
+func (x *Session) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *Session) GetCurrent() bool {
+	if x != nil {
+		return x.Current
+	}
+	return false
+}
+
+type ListSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	mi := &file_merch_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsRequest) ProtoMessage() {}
+
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{14}
+}
+
+type ListSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	mi := &file_merch_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListSessionsResponse) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+type RevokeSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionRequest) Reset() {
+	*x = RevokeSessionRequest{}
+	mi := &file_merch_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionRequest) ProtoMessage() {}
+
+func (x *RevokeSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionRequest.ProtoReflect.Descriptor instead.
+func (*RevokeSessionRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RevokeSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type RevokeSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionResponse) Reset() {
+	*x = RevokeSessionResponse{}
+	mi := &file_merch_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionResponse) ProtoMessage() {}
+
+func (x *RevokeSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionResponse.ProtoReflect.Descriptor instead.
+func (*RevokeSessionResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RevokeSessionResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type RevokeAllSessionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Не завершать сессию, из которой сделан запрос
+	KeepCurrent   bool `protobuf:"varint,1,opt,name=keep_current,json=keepCurrent,proto3" json:"keep_current,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAllSessionsRequest) Reset() {
+	*x = RevokeAllSessionsRequest{}
+	mi := &file_merch_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAllSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAllSessionsRequest) ProtoMessage() {}
+
+func (x *RevokeAllSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAllSessionsRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAllSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RevokeAllSessionsRequest) GetKeepCurrent() bool {
+	if x != nil {
+		return x.KeepCurrent
+	}
+	return false
+}
+
+type RevokeAllSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revoked       int32                  `protobuf:"varint,1,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAllSessionsResponse) Reset() {
+	*x = RevokeAllSessionsResponse{}
+	mi := &file_merch_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAllSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAllSessionsResponse) ProtoMessage() {}
+
+func (x *RevokeAllSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAllSessionsResponse.ProtoReflect.Descriptor instead.
+func (*RevokeAllSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RevokeAllSessionsResponse) GetRevoked() int32 {
+	if x != nil {
+		return x.Revoked
+	}
+	return 0
+}
+
+type RegisterRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 3-32 символа: латиница, цифры, '.', '_', '-'
+	Username      string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password      string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterRequest) Reset() {
+	*x = RegisterRequest{}
+	mi := &file_merch_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRequest) ProtoMessage() {}
+
+func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RegisterRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type LoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginRequest) Reset() {
+	*x = LoginRequest{}
+	mi := &file_merch_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginRequest) ProtoMessage() {}
+
+func (x *LoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
+func (*LoginRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *LoginRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type BeginOIDCLoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginOIDCLoginRequest) Reset() {
+	*x = BeginOIDCLoginRequest{}
+	mi := &file_merch_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginOIDCLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginOIDCLoginRequest) ProtoMessage() {}
+
+func (x *BeginOIDCLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginOIDCLoginRequest.ProtoReflect.Descriptor instead.
+func (*BeginOIDCLoginRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{22}
+}
+
+type BeginOIDCLoginResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Непрозрачный идентификатор входа: передаётся обратно в LoginWithOIDC
+	LoginHandle string `protobuf:"bytes,1,opt,name=login_handle,json=loginHandle,proto3" json:"login_handle,omitempty"`
+	// nonce для запроса авторизации к провайдеру
+	Nonce         string `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	ExpiresAt     string `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BeginOIDCLoginResponse) Reset() {
+	*x = BeginOIDCLoginResponse{}
+	mi := &file_merch_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BeginOIDCLoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BeginOIDCLoginResponse) ProtoMessage() {}
+
+func (x *BeginOIDCLoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BeginOIDCLoginResponse.ProtoReflect.Descriptor instead.
+func (*BeginOIDCLoginResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *BeginOIDCLoginResponse) GetLoginHandle() string {
+	if x != nil {
+		return x.LoginHandle
+	}
+	return ""
+}
+
+func (x *BeginOIDCLoginResponse) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *BeginOIDCLoginResponse) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+type LoginWithOIDCRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id_token, полученный от OIDC-провайдера по authorization code
+	IdToken string `protobuf:"bytes,1,opt,name=id_token,json=idToken,proto3" json:"id_token,omitempty"`
+	// login_handle из BeginOIDCLogin; nonce сервер берёт у себя и гасит
+	LoginHandle   string `protobuf:"bytes,3,opt,name=login_handle,json=loginHandle,proto3" json:"login_handle,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginWithOIDCRequest) Reset() {
+	*x = LoginWithOIDCRequest{}
+	mi := &file_merch_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginWithOIDCRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginWithOIDCRequest) ProtoMessage() {}
+
+func (x *LoginWithOIDCRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginWithOIDCRequest.ProtoReflect.Descriptor instead.
+func (*LoginWithOIDCRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *LoginWithOIDCRequest) GetIdToken() string {
+	if x != nil {
+		return x.IdToken
+	}
+	return ""
+}
+
+func (x *LoginWithOIDCRequest) GetLoginHandle() string {
+	if x != nil {
+		return x.LoginHandle
+	}
+	return ""
+}
+
+type PurchaseRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	MerchName string                 `protobuf:"bytes,2,opt,name=merch_name,json=merchName,proto3" json:"merch_name,omitempty"`
+	// Код валюты, пусто — coin. Тратить можно только валюты со spendable
+	Currency      string `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurchaseRequest) Reset() {
+	*x = PurchaseRequest{}
+	mi := &file_merch_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurchaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurchaseRequest) ProtoMessage() {}
+
+func (x *PurchaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurchaseRequest.ProtoReflect.Descriptor instead.
+func (*PurchaseRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PurchaseRequest) GetMerchName() string {
+	if x != nil {
+		return x.MerchName
+	}
+	return ""
+}
+
+func (x *PurchaseRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type PurchaseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurchaseResponse) Reset() {
+	*x = PurchaseResponse{}
+	mi := &file_merch_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurchaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurchaseResponse) ProtoMessage() {}
+
+func (x *PurchaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurchaseResponse.ProtoReflect.Descriptor instead.
+func (*PurchaseResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PurchaseResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PurchaseResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type TransferRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ToUser int32                  `protobuf:"varint,2,opt,name=to_user,json=toUser,proto3" json:"to_user,omitempty"`
+	Amount int32                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Код валюты, пусто — coin
+	Currency      string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferRequest) Reset() {
+	*x = TransferRequest{}
+	mi := &file_merch_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferRequest) ProtoMessage() {}
+
+func (x *TransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferRequest.ProtoReflect.Descriptor instead.
+func (*TransferRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *TransferRequest) GetToUser() int32 {
+	if x != nil {
+		return x.ToUser
+	}
+	return 0
+}
+
+func (x *TransferRequest) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *TransferRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type TransferResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferResponse) Reset() {
+	*x = TransferResponse{}
+	mi := &file_merch_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferResponse) ProtoMessage() {}
+
+func (x *TransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferResponse.ProtoReflect.Descriptor instead.
+func (*TransferResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *TransferResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *TransferResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type TransferItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ToUser        int32                  `protobuf:"varint,1,opt,name=to_user,json=toUser,proto3" json:"to_user,omitempty"`
+	Amount        int32                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferItem) Reset() {
+	*x = TransferItem{}
+	mi := &file_merch_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferItem) ProtoMessage() {}
+
+func (x *TransferItem) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferItem.ProtoReflect.Descriptor instead.
+func (*TransferItem) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *TransferItem) GetToUser() int32 {
+	if x != nil {
+		return x.ToUser
+	}
+	return 0
+}
+
+func (x *TransferItem) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type TransferBatchRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Transfers []*TransferItem        `protobuf:"bytes,1,rep,name=transfers,proto3" json:"transfers,omitempty"`
+	// Код валюты, пусто — coin
+	Currency      string `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferBatchRequest) Reset() {
+	*x = TransferBatchRequest{}
+	mi := &file_merch_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferBatchRequest) ProtoMessage() {}
+
+func (x *TransferBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferBatchRequest.ProtoReflect.Descriptor instead.
+func (*TransferBatchRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *TransferBatchRequest) GetTransfers() []*TransferItem {
+	if x != nil {
+		return x.Transfers
+	}
+	return nil
+}
+
+func (x *TransferBatchRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type TransferBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	TotalAmount   int32                  `protobuf:"varint,3,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferBatchResponse) Reset() {
+	*x = TransferBatchResponse{}
+	mi := &file_merch_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferBatchResponse) ProtoMessage() {}
+
+func (x *TransferBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferBatchResponse.ProtoReflect.Descriptor instead.
+func (*TransferBatchResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *TransferBatchResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *TransferBatchResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TransferBatchResponse) GetTotalAmount() int32 {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return 0
+}
+
+type GetInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInfoRequest) Reset() {
+	*x = GetInfoRequest{}
+	mi := &file_merch_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInfoRequest) ProtoMessage() {}
+
+func (x *GetInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetInfoRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{32}
+}
+
+type Purchase struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	MerchName     string                 `protobuf:"bytes,2,opt,name=merch_name,json=merchName,proto3" json:"merch_name,omitempty"`
+	Price         int32                  `protobuf:"varint,3,opt,name=price,proto3" json:"price,omitempty"`
+	PurchaseDate  string                 `protobuf:"bytes,4,opt,name=purchase_date,json=purchaseDate,proto3" json:"purchase_date,omitempty"`
+	Currency      string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Purchase) Reset() {
+	*x = Purchase{}
+	mi := &file_merch_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Purchase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Purchase) ProtoMessage() {}
+
+func (x *Purchase) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Purchase.ProtoReflect.Descriptor instead.
+func (*Purchase) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *Purchase) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Purchase) GetMerchName() string {
+	if x != nil {
+		return x.MerchName
+	}
+	return ""
+}
+
+func (x *Purchase) GetPrice() int32 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Purchase) GetPurchaseDate() string {
+	if x != nil {
+		return x.PurchaseDate
+	}
+	return ""
+}
+
+func (x *Purchase) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Transaction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	SenderId      int32                  `protobuf:"varint,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	ReceiverId    int32                  `protobuf:"varint,3,opt,name=receiver_id,json=receiverId,proto3" json:"receiver_id,omitempty"`
+	Amount        int32                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ReversalOf    int32                  `protobuf:"varint,6,opt,name=reversal_of,json=reversalOf,proto3" json:"reversal_of,omitempty"`
+	Currency      string                 `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Transaction) Reset() {
+	*x = Transaction{}
+	mi := &file_merch_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transaction) ProtoMessage() {}
+
+func (x *Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *Transaction) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Transaction) GetSenderId() int32 {
+	if x != nil {
+		return x.SenderId
+	}
+	return 0
+}
+
+func (x *Transaction) GetReceiverId() int32 {
+	if x != nil {
+		return x.ReceiverId
+	}
+	return 0
+}
+
+func (x *Transaction) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Transaction) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Transaction) GetReversalOf() int32 {
+	if x != nil {
+		return x.ReversalOf
+	}
+	return 0
+}
+
+func (x *Transaction) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type InventoryItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MerchName     string                 `protobuf:"bytes,1,opt,name=merch_name,json=merchName,proto3" json:"merch_name,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InventoryItem) Reset() {
+	*x = InventoryItem{}
+	mi := &file_merch_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InventoryItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventoryItem) ProtoMessage() {}
+
+func (x *InventoryItem) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventoryItem.ProtoReflect.Descriptor instead.
+func (*InventoryItem) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *InventoryItem) GetMerchName() string {
+	if x != nil {
+		return x.MerchName
+	}
+	return ""
+}
+
+func (x *InventoryItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type CoinMovement struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Amount        int32                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CoinMovement) Reset() {
+	*x = CoinMovement{}
+	mi := &file_merch_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CoinMovement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoinMovement) ProtoMessage() {}
+
+func (x *CoinMovement) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoinMovement.ProtoReflect.Descriptor instead.
+func (*CoinMovement) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *CoinMovement) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CoinMovement) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CoinMovement) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type CurrencyBalance struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Currency string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	Balance  int32                  `protobuf:"varint,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	// false — валюту можно только дарить, но не тратить в магазине
+	Spendable     bool `protobuf:"varint,3,opt,name=spendable,proto3" json:"spendable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CurrencyBalance) Reset() {
+	*x = CurrencyBalance{}
+	mi := &file_merch_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CurrencyBalance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CurrencyBalance) ProtoMessage() {}
+
+func (x *CurrencyBalance) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CurrencyBalance.ProtoReflect.Descriptor instead.
+func (*CurrencyBalance) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *CurrencyBalance) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CurrencyBalance) GetBalance() int32 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *CurrencyBalance) GetSpendable() bool {
+	if x != nil {
+		return x.Spendable
+	}
+	return false
+}
+
+type CoinHistory struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Кто и сколько передал пользователю
+	Received []*CoinMovement `protobuf:"bytes,1,rep,name=received,proto3" json:"received,omitempty"`
+	// Кому и сколько передал пользователь
+	Sent          []*CoinMovement `protobuf:"bytes,2,rep,name=sent,proto3" json:"sent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CoinHistory) Reset() {
+	*x = CoinHistory{}
+	mi := &file_merch_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CoinHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoinHistory) ProtoMessage() {}
+
+func (x *CoinHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoinHistory.ProtoReflect.Descriptor instead.
+func (*CoinHistory) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *CoinHistory) GetReceived() []*CoinMovement {
+	if x != nil {
+		return x.Received
+	}
+	return nil
+}
+
+func (x *CoinHistory) GetSent() []*CoinMovement {
+	if x != nil {
+		return x.Sent
+	}
+	return nil
+}
+
+type UserInfo struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	UserId       int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username     string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Balance      int32                  `protobuf:"varint,3,opt,name=balance,proto3" json:"balance,omitempty"`
+	Purchases    []*Purchase            `protobuf:"bytes,4,rep,name=purchases,proto3" json:"purchases,omitempty"`
+	Transactions []*Transaction         `protobuf:"bytes,5,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	Inventory    []*InventoryItem       `protobuf:"bytes,6,rep,name=inventory,proto3" json:"inventory,omitempty"`
+	CoinHistory  *CoinHistory           `protobuf:"bytes,7,opt,name=coin_history,json=coinHistory,proto3" json:"coin_history,omitempty"`
+	// Балансы по всем валютам; balance — баланс в coin
+	Balances []*CurrencyBalance `protobuf:"bytes,8,rep,name=balances,proto3" json:"balances,omitempty"`
+	// Остаток месячного лимита на подарки: эти монеты можно только передать коллегам
+	GiftAllowance int32 `protobuf:"varint,9,opt,name=gift_allowance,json=giftAllowance,proto3" json:"gift_allowance,omitempty"`
+	// Когда лимит будет сброшен, RFC3339
+	GiftAllowanceResetsAt string `protobuf:"bytes,10,opt,name=gift_allowance_resets_at,json=giftAllowanceResetsAt,proto3" json:"gift_allowance_resets_at,omitempty"`
+	// user | store_admin | finance | support
+	Roles         []string `protobuf:"bytes,11,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserInfo) Reset() {
+	*x = UserInfo{}
+	mi := &file_merch_service_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserInfo) ProtoMessage() {}
+
+func (x *UserInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserInfo.ProtoReflect.Descriptor instead.
+func (*UserInfo) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *UserInfo) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *UserInfo) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *UserInfo) GetBalance() int32 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *UserInfo) GetPurchases() []*Purchase {
+	if x != nil {
+		return x.Purchases
+	}
+	return nil
+}
+
+func (x *UserInfo) GetTransactions() []*Transaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+func (x *UserInfo) GetInventory() []*InventoryItem {
+	if x != nil {
+		return x.Inventory
+	}
+	return nil
+}
+
+func (x *UserInfo) GetCoinHistory() *CoinHistory {
+	if x != nil {
+		return x.CoinHistory
+	}
+	return nil
+}
+
+func (x *UserInfo) GetBalances() []*CurrencyBalance {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+func (x *UserInfo) GetGiftAllowance() int32 {
+	if x != nil {
+		return x.GiftAllowance
+	}
+	return 0
+}
+
+func (x *UserInfo) GetGiftAllowanceResetsAt() string {
+	if x != nil {
+		return x.GiftAllowanceResetsAt
+	}
+	return ""
+}
+
+func (x *UserInfo) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type GetInfoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Info          *UserInfo              `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInfoResponse) Reset() {
+	*x = GetInfoResponse{}
+	mi := &file_merch_service_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInfoResponse) ProtoMessage() {}
+
+func (x *GetInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInfoResponse.ProtoReflect.Descriptor instead.
 func (*GetInfoResponse) Descriptor() ([]byte, []int) {
-	return file_merch_service_proto_rawDescGZIP(), []int{10}
+	return file_merch_service_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetInfoResponse) GetInfo() *UserInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+type ListTransactionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Курсор из next_cursor предыдущей страницы
+	Cursor string `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit  int32  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Границы периода в RFC3339: from включительно, to не включительно
+	From string `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To   string `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	// sent | received, пусто — оба направления
+	Direction      string `protobuf:"bytes,5,opt,name=direction,proto3" json:"direction,omitempty"`
+	CounterpartyId int32  `protobuf:"varint,6,opt,name=counterparty_id,json=counterpartyId,proto3" json:"counterparty_id,omitempty"`
+	MinAmount      int32  `protobuf:"varint,7,opt,name=min_amount,json=minAmount,proto3" json:"min_amount,omitempty"`
+	MaxAmount      int32  `protobuf:"varint,8,opt,name=max_amount,json=maxAmount,proto3" json:"max_amount,omitempty"`
+	Currency       string `protobuf:"bytes,9,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListTransactionsRequest) Reset() {
+	*x = ListTransactionsRequest{}
+	mi := &file_merch_service_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsRequest) ProtoMessage() {}
+
+func (x *ListTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*ListTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ListTransactionsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListTransactionsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListTransactionsRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ListTransactionsRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ListTransactionsRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *ListTransactionsRequest) GetCounterpartyId() int32 {
+	if x != nil {
+		return x.CounterpartyId
+	}
+	return 0
+}
+
+func (x *ListTransactionsRequest) GetMinAmount() int32 {
+	if x != nil {
+		return x.MinAmount
+	}
+	return 0
+}
+
+func (x *ListTransactionsRequest) GetMaxAmount() int32 {
+	if x != nil {
+		return x.MaxAmount
+	}
+	return 0
+}
+
+func (x *ListTransactionsRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type ListTransactionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transactions  []*Transaction         `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransactionsResponse) Reset() {
+	*x = ListTransactionsResponse{}
+	mi := &file_merch_service_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsResponse) ProtoMessage() {}
+
+func (x *ListTransactionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsResponse.ProtoReflect.Descriptor instead.
+func (*ListTransactionsResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ListTransactionsResponse) GetTransactions() []*Transaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+func (x *ListTransactionsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ListPurchasesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cursor        string                 `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	From          string                 `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	MerchName     string                 `protobuf:"bytes,5,opt,name=merch_name,json=merchName,proto3" json:"merch_name,omitempty"`
+	MinPrice      int32                  `protobuf:"varint,6,opt,name=min_price,json=minPrice,proto3" json:"min_price,omitempty"`
+	MaxPrice      int32                  `protobuf:"varint,7,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPurchasesRequest) Reset() {
+	*x = ListPurchasesRequest{}
+	mi := &file_merch_service_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPurchasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPurchasesRequest) ProtoMessage() {}
+
+func (x *ListPurchasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPurchasesRequest.ProtoReflect.Descriptor instead.
+func (*ListPurchasesRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListPurchasesRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListPurchasesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListPurchasesRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ListPurchasesRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ListPurchasesRequest) GetMerchName() string {
+	if x != nil {
+		return x.MerchName
+	}
+	return ""
+}
+
+func (x *ListPurchasesRequest) GetMinPrice() int32 {
+	if x != nil {
+		return x.MinPrice
+	}
+	return 0
+}
+
+func (x *ListPurchasesRequest) GetMaxPrice() int32 {
+	if x != nil {
+		return x.MaxPrice
+	}
+	return 0
+}
+
+type ListPurchasesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Purchases     []*Purchase            `protobuf:"bytes,1,rep,name=purchases,proto3" json:"purchases,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPurchasesResponse) Reset() {
+	*x = ListPurchasesResponse{}
+	mi := &file_merch_service_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPurchasesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPurchasesResponse) ProtoMessage() {}
+
+func (x *ListPurchasesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPurchasesResponse.ProtoReflect.Descriptor instead.
+func (*ListPurchasesResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListPurchasesResponse) GetPurchases() []*Purchase {
+	if x != nil {
+		return x.Purchases
+	}
+	return nil
+}
+
+func (x *ListPurchasesResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ExportHistoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Границы периода в RFC3339, необязательные
+	From          string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportHistoryRequest) Reset() {
+	*x = ExportHistoryRequest{}
+	mi := &file_merch_service_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportHistoryRequest) ProtoMessage() {}
+
+func (x *ExportHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ExportHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ExportHistoryRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ExportHistoryRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+type HistoryRecord struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Record:
+	//
+	//	*HistoryRecord_Purchase
+	//	*HistoryRecord_Transaction
+	Record        isHistoryRecord_Record `protobuf_oneof:"record"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryRecord) Reset() {
+	*x = HistoryRecord{}
+	mi := &file_merch_service_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryRecord) ProtoMessage() {}
+
+func (x *HistoryRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryRecord.ProtoReflect.Descriptor instead.
+func (*HistoryRecord) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *HistoryRecord) GetRecord() isHistoryRecord_Record {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *HistoryRecord) GetPurchase() *Purchase {
+	if x != nil {
+		if x, ok := x.Record.(*HistoryRecord_Purchase); ok {
+			return x.Purchase
+		}
+	}
+	return nil
+}
+
+func (x *HistoryRecord) GetTransaction() *Transaction {
+	if x != nil {
+		if x, ok := x.Record.(*HistoryRecord_Transaction); ok {
+			return x.Transaction
+		}
+	}
+	return nil
+}
+
+type isHistoryRecord_Record interface {
+	isHistoryRecord_Record()
+}
+
+type HistoryRecord_Purchase struct {
+	Purchase *Purchase `protobuf:"bytes,1,opt,name=purchase,proto3,oneof"`
+}
+
+type HistoryRecord_Transaction struct {
+	Transaction *Transaction `protobuf:"bytes,2,opt,name=transaction,proto3,oneof"`
+}
+
+func (*HistoryRecord_Purchase) isHistoryRecord_Record() {}
+
+func (*HistoryRecord_Transaction) isHistoryRecord_Record() {}
+
+type GetStatementRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Календарный месяц в формате YYYY-MM
+	Period        string `protobuf:"bytes,1,opt,name=period,proto3" json:"period,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatementRequest) Reset() {
+	*x = GetStatementRequest{}
+	mi := &file_merch_service_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatementRequest) ProtoMessage() {}
+
+func (x *GetStatementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatementRequest.ProtoReflect.Descriptor instead.
+func (*GetStatementRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *GetStatementRequest) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+type StatementMovement struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// initial | purchase | transfer_in | transfer_out | grant | expiration
+	Category  string `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	Id        int32  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt string `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Со знаком: поступления положительные, списания отрицательные
+	Amount         int32  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Description    string `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	CounterpartyId int32  `protobuf:"varint,6,opt,name=counterparty_id,json=counterpartyId,proto3" json:"counterparty_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *StatementMovement) Reset() {
+	*x = StatementMovement{}
+	mi := &file_merch_service_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatementMovement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatementMovement) ProtoMessage() {}
+
+func (x *StatementMovement) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatementMovement.ProtoReflect.Descriptor instead.
+func (*StatementMovement) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *StatementMovement) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *StatementMovement) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *StatementMovement) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *StatementMovement) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *StatementMovement) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *StatementMovement) GetCounterpartyId() int32 {
+	if x != nil {
+		return x.CounterpartyId
+	}
+	return 0
+}
+
+type CategoryTotal struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Category      string                 `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	Amount        int32                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryTotal) Reset() {
+	*x = CategoryTotal{}
+	mi := &file_merch_service_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryTotal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryTotal) ProtoMessage() {}
+
+func (x *CategoryTotal) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryTotal.ProtoReflect.Descriptor instead.
+func (*CategoryTotal) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *CategoryTotal) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *CategoryTotal) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type Statement struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Period         string                 `protobuf:"bytes,1,opt,name=period,proto3" json:"period,omitempty"`
+	OpeningBalance int32                  `protobuf:"varint,2,opt,name=opening_balance,json=openingBalance,proto3" json:"opening_balance,omitempty"`
+	ClosingBalance int32                  `protobuf:"varint,3,opt,name=closing_balance,json=closingBalance,proto3" json:"closing_balance,omitempty"`
+	Movements      []*StatementMovement   `protobuf:"bytes,4,rep,name=movements,proto3" json:"movements,omitempty"`
+	Totals         []*CategoryTotal       `protobuf:"bytes,5,rep,name=totals,proto3" json:"totals,omitempty"`
+	GeneratedAt    string                 `protobuf:"bytes,6,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Statement) Reset() {
+	*x = Statement{}
+	mi := &file_merch_service_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Statement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Statement) ProtoMessage() {}
+
+func (x *Statement) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Statement.ProtoReflect.Descriptor instead.
+func (*Statement) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *Statement) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *Statement) GetOpeningBalance() int32 {
+	if x != nil {
+		return x.OpeningBalance
+	}
+	return 0
+}
+
+func (x *Statement) GetClosingBalance() int32 {
+	if x != nil {
+		return x.ClosingBalance
+	}
+	return 0
+}
+
+func (x *Statement) GetMovements() []*StatementMovement {
+	if x != nil {
+		return x.Movements
+	}
+	return nil
+}
+
+func (x *Statement) GetTotals() []*CategoryTotal {
+	if x != nil {
+		return x.Totals
+	}
+	return nil
+}
+
+func (x *Statement) GetGeneratedAt() string {
+	if x != nil {
+		return x.GeneratedAt
+	}
+	return ""
+}
+
+type GetStatementResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statement     *Statement             `protobuf:"bytes,1,opt,name=statement,proto3" json:"statement,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatementResponse) Reset() {
+	*x = GetStatementResponse{}
+	mi := &file_merch_service_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatementResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatementResponse) ProtoMessage() {}
+
+func (x *GetStatementResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatementResponse.ProtoReflect.Descriptor instead.
+func (*GetStatementResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *GetStatementResponse) GetStatement() *Statement {
+	if x != nil {
+		return x.Statement
+	}
+	return nil
+}
+
+type GetExpiringCoinsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExpiringCoinsRequest) Reset() {
+	*x = GetExpiringCoinsRequest{}
+	mi := &file_merch_service_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExpiringCoinsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExpiringCoinsRequest) ProtoMessage() {}
+
+func (x *GetExpiringCoinsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExpiringCoinsRequest.ProtoReflect.Descriptor instead.
+func (*GetExpiringCoinsRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{52}
+}
+
+type ExpiringCoins struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amount        int32                  `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	ReceivedAt    string                 `protobuf:"bytes,2,opt,name=received_at,json=receivedAt,proto3" json:"received_at,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExpiringCoins) Reset() {
+	*x = ExpiringCoins{}
+	mi := &file_merch_service_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExpiringCoins) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExpiringCoins) ProtoMessage() {}
+
+func (x *ExpiringCoins) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExpiringCoins.ProtoReflect.Descriptor instead.
+func (*ExpiringCoins) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ExpiringCoins) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ExpiringCoins) GetReceivedAt() string {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return ""
+}
+
+func (x *ExpiringCoins) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+type GetExpiringCoinsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         int32                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	Lots          []*ExpiringCoins       `protobuf:"bytes,2,rep,name=lots,proto3" json:"lots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExpiringCoinsResponse) Reset() {
+	*x = GetExpiringCoinsResponse{}
+	mi := &file_merch_service_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExpiringCoinsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExpiringCoinsResponse) ProtoMessage() {}
+
+func (x *GetExpiringCoinsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExpiringCoinsResponse.ProtoReflect.Descriptor instead.
+func (*GetExpiringCoinsResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *GetExpiringCoinsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetExpiringCoinsResponse) GetLots() []*ExpiringCoins {
+	if x != nil {
+		return x.Lots
+	}
+	return nil
+}
+
+type GrantCoinsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Amount        int32                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GrantCoinsRequest) Reset() {
+	*x = GrantCoinsRequest{}
+	mi := &file_merch_service_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrantCoinsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantCoinsRequest) ProtoMessage() {}
+
+func (x *GrantCoinsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantCoinsRequest.ProtoReflect.Descriptor instead.
+func (*GrantCoinsRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *GrantCoinsRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *GrantCoinsRequest) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *GrantCoinsRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type GrantCoinsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GrantId       int32                  `protobuf:"varint,1,opt,name=grant_id,json=grantId,proto3" json:"grant_id,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GrantCoinsResponse) Reset() {
+	*x = GrantCoinsResponse{}
+	mi := &file_merch_service_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrantCoinsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantCoinsResponse) ProtoMessage() {}
+
+func (x *GrantCoinsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantCoinsResponse.ProtoReflect.Descriptor instead.
+func (*GrantCoinsResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *GrantCoinsResponse) GetGrantId() int32 {
+	if x != nil {
+		return x.GrantId
+	}
+	return 0
+}
+
+func (x *GrantCoinsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type GrantCoinsBulkRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// CSV со строками username,amount,reason (заголовок необязателен)
+	Csv           string `protobuf:"bytes,1,opt,name=csv,proto3" json:"csv,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GrantCoinsBulkRequest) Reset() {
+	*x = GrantCoinsBulkRequest{}
+	mi := &file_merch_service_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrantCoinsBulkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantCoinsBulkRequest) ProtoMessage() {}
+
+func (x *GrantCoinsBulkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantCoinsBulkRequest.ProtoReflect.Descriptor instead.
+func (*GrantCoinsBulkRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *GrantCoinsBulkRequest) GetCsv() string {
+	if x != nil {
+		return x.Csv
+	}
+	return ""
+}
+
+type GrantCoinsBulkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BatchId       int32                  `protobuf:"varint,1,opt,name=batch_id,json=batchId,proto3" json:"batch_id,omitempty"`
+	Rows          int32                  `protobuf:"varint,2,opt,name=rows,proto3" json:"rows,omitempty"`
+	TotalAmount   int32                  `protobuf:"varint,3,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GrantCoinsBulkResponse) Reset() {
+	*x = GrantCoinsBulkResponse{}
+	mi := &file_merch_service_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrantCoinsBulkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantCoinsBulkResponse) ProtoMessage() {}
+
+func (x *GrantCoinsBulkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantCoinsBulkResponse.ProtoReflect.Descriptor instead.
+func (*GrantCoinsBulkResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *GrantCoinsBulkResponse) GetBatchId() int32 {
+	if x != nil {
+		return x.BatchId
+	}
+	return 0
+}
+
+func (x *GrantCoinsBulkResponse) GetRows() int32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *GrantCoinsBulkResponse) GetTotalAmount() int32 {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return 0
+}
+
+type ReverseTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId int32                  `protobuf:"varint,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReverseTransactionRequest) Reset() {
+	*x = ReverseTransactionRequest{}
+	mi := &file_merch_service_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReverseTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReverseTransactionRequest) ProtoMessage() {}
+
+func (x *ReverseTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReverseTransactionRequest.ProtoReflect.Descriptor instead.
+func (*ReverseTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ReverseTransactionRequest) GetTransactionId() int32 {
+	if x != nil {
+		return x.TransactionId
+	}
+	return 0
+}
+
+func (x *ReverseTransactionRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ReverseTransactionResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ReversalId      int32                  `protobuf:"varint,1,opt,name=reversal_id,json=reversalId,proto3" json:"reversal_id,omitempty"`
+	RequestedAmount int32                  `protobuf:"varint,2,opt,name=requested_amount,json=requestedAmount,proto3" json:"requested_amount,omitempty"`
+	ReversedAmount  int32                  `protobuf:"varint,3,opt,name=reversed_amount,json=reversedAmount,proto3" json:"reversed_amount,omitempty"`
+	Partial         bool                   `protobuf:"varint,4,opt,name=partial,proto3" json:"partial,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ReverseTransactionResponse) Reset() {
+	*x = ReverseTransactionResponse{}
+	mi := &file_merch_service_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReverseTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReverseTransactionResponse) ProtoMessage() {}
+
+func (x *ReverseTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReverseTransactionResponse.ProtoReflect.Descriptor instead.
+func (*ReverseTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ReverseTransactionResponse) GetReversalId() int32 {
+	if x != nil {
+		return x.ReversalId
+	}
+	return 0
+}
+
+func (x *ReverseTransactionResponse) GetRequestedAmount() int32 {
+	if x != nil {
+		return x.RequestedAmount
+	}
+	return 0
+}
+
+func (x *ReverseTransactionResponse) GetReversedAmount() int32 {
+	if x != nil {
+		return x.ReversedAmount
+	}
+	return 0
+}
+
+func (x *ReverseTransactionResponse) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
+type CoinRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	RequesterId   int32                  `protobuf:"varint,2,opt,name=requester_id,json=requesterId,proto3" json:"requester_id,omitempty"`
+	PayerId       int32                  `protobuf:"varint,3,opt,name=payer_id,json=payerId,proto3" json:"payer_id,omitempty"`
+	Amount        int32                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Note          string                 `protobuf:"bytes,5,opt,name=note,proto3" json:"note,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	TransactionId int32                  `protobuf:"varint,7,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,9,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	ResolvedAt    string                 `protobuf:"bytes,10,opt,name=resolved_at,json=resolvedAt,proto3" json:"resolved_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CoinRequest) Reset() {
+	*x = CoinRequest{}
+	mi := &file_merch_service_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CoinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoinRequest) ProtoMessage() {}
+
+func (x *CoinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoinRequest.ProtoReflect.Descriptor instead.
+func (*CoinRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *CoinRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CoinRequest) GetRequesterId() int32 {
+	if x != nil {
+		return x.RequesterId
+	}
+	return 0
+}
+
+func (x *CoinRequest) GetPayerId() int32 {
+	if x != nil {
+		return x.PayerId
+	}
+	return 0
+}
+
+func (x *CoinRequest) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CoinRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *CoinRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CoinRequest) GetTransactionId() int32 {
+	if x != nil {
+		return x.TransactionId
+	}
+	return 0
+}
+
+func (x *CoinRequest) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *CoinRequest) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *CoinRequest) GetResolvedAt() string {
+	if x != nil {
+		return x.ResolvedAt
+	}
+	return ""
+}
+
+type RequestCoinsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromUser      int32                  `protobuf:"varint,1,opt,name=from_user,json=fromUser,proto3" json:"from_user,omitempty"`
+	Amount        int32                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestCoinsRequest) Reset() {
+	*x = RequestCoinsRequest{}
+	mi := &file_merch_service_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestCoinsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestCoinsRequest) ProtoMessage() {}
+
+func (x *RequestCoinsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestCoinsRequest.ProtoReflect.Descriptor instead.
+func (*RequestCoinsRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *RequestCoinsRequest) GetFromUser() int32 {
+	if x != nil {
+		return x.FromUser
+	}
+	return 0
+}
+
+func (x *RequestCoinsRequest) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *RequestCoinsRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type RequestCoinsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Request       *CoinRequest           `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestCoinsResponse) Reset() {
+	*x = RequestCoinsResponse{}
+	mi := &file_merch_service_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestCoinsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestCoinsResponse) ProtoMessage() {}
+
+func (x *RequestCoinsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestCoinsResponse.ProtoReflect.Descriptor instead.
+func (*RequestCoinsResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *RequestCoinsResponse) GetRequest() *CoinRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+type ListCoinRequestsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// incoming — запросы ко мне, outgoing — мои запросы, пусто — все
+	Direction string `protobuf:"bytes,1,opt,name=direction,proto3" json:"direction,omitempty"`
+	// pending | approved | rejected | expired, пусто — все
+	Status        string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCoinRequestsRequest) Reset() {
+	*x = ListCoinRequestsRequest{}
+	mi := &file_merch_service_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCoinRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCoinRequestsRequest) ProtoMessage() {}
+
+func (x *ListCoinRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCoinRequestsRequest.ProtoReflect.Descriptor instead.
+func (*ListCoinRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *ListCoinRequestsRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *ListCoinRequestsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListCoinRequestsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Requests      []*CoinRequest         `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCoinRequestsResponse) Reset() {
+	*x = ListCoinRequestsResponse{}
+	mi := &file_merch_service_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCoinRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCoinRequestsResponse) ProtoMessage() {}
+
+func (x *ListCoinRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCoinRequestsResponse.ProtoReflect.Descriptor instead.
+func (*ListCoinRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *ListCoinRequestsResponse) GetRequests() []*CoinRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type ResolveCoinRequestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     int32                  `protobuf:"varint,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveCoinRequestRequest) Reset() {
+	*x = ResolveCoinRequestRequest{}
+	mi := &file_merch_service_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveCoinRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveCoinRequestRequest) ProtoMessage() {}
+
+func (x *ResolveCoinRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveCoinRequestRequest.ProtoReflect.Descriptor instead.
+func (*ResolveCoinRequestRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *ResolveCoinRequestRequest) GetRequestId() int32 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+type ResolveCoinRequestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Request       *CoinRequest           `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveCoinRequestResponse) Reset() {
+	*x = ResolveCoinRequestResponse{}
+	mi := &file_merch_service_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveCoinRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveCoinRequestResponse) ProtoMessage() {}
+
+func (x *ResolveCoinRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveCoinRequestResponse.ProtoReflect.Descriptor instead.
+func (*ResolveCoinRequestResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ResolveCoinRequestResponse) GetRequest() *CoinRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+type SetUserRolesRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Username string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	// Полный список ролей пользователя: user | store_admin | finance | support
+	Roles         []string `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserRolesRequest) Reset() {
+	*x = SetUserRolesRequest{}
+	mi := &file_merch_service_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserRolesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserRolesRequest) ProtoMessage() {}
+
+func (x *SetUserRolesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserRolesRequest.ProtoReflect.Descriptor instead.
+func (*SetUserRolesRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *SetUserRolesRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SetUserRolesRequest) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type SetUserRolesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Roles         []string               `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserRolesResponse) Reset() {
+	*x = SetUserRolesResponse{}
+	mi := &file_merch_service_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserRolesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserRolesResponse) ProtoMessage() {}
+
+func (x *SetUserRolesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserRolesResponse.ProtoReflect.Descriptor instead.
+func (*SetUserRolesResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *SetUserRolesResponse) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *SetUserRolesResponse) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type CreateServiceAccountRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Username string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	// Роли аккаунта, user добавляется всегда
+	Roles         []string `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateServiceAccountRequest) Reset() {
+	*x = CreateServiceAccountRequest{}
+	mi := &file_merch_service_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServiceAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServiceAccountRequest) ProtoMessage() {}
+
+func (x *CreateServiceAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServiceAccountRequest.ProtoReflect.Descriptor instead.
+func (*CreateServiceAccountRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *CreateServiceAccountRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateServiceAccountRequest) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type CreateServiceAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Roles         []string               `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateServiceAccountResponse) Reset() {
+	*x = CreateServiceAccountResponse{}
+	mi := &file_merch_service_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServiceAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServiceAccountResponse) ProtoMessage() {}
+
+func (x *CreateServiceAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServiceAccountResponse.ProtoReflect.Descriptor instead.
+func (*CreateServiceAccountResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *CreateServiceAccountResponse) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CreateServiceAccountResponse) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateServiceAccountResponse) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type APIKey struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Name     string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// Открытая часть ключа (msk_...), по ней ключ можно опознать
+	Prefix string `protobuf:"bytes,4,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// coins:transfer | coins:grant | transactions:reverse | merch:purchase | history:read | users:manage | audit:read
+	Scopes    []string `protobuf:"bytes,5,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	CreatedAt string   `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt string   `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// RFC3339, пусто — ключ ещё не использовался
+	LastUsedAt string `protobuf:"bytes,8,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	// RFC3339, пусто — ключ действует
+	RevokedAt     string `protobuf:"bytes,9,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *APIKey) Reset() {
+	*x = APIKey{}
+	mi := &file_merch_service_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *APIKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*APIKey) ProtoMessage() {}
+
+func (x *APIKey) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use APIKey.ProtoReflect.Descriptor instead.
+func (*APIKey) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *APIKey) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *APIKey) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *APIKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *APIKey) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *APIKey) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *APIKey) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *APIKey) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *APIKey) GetLastUsedAt() string {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return ""
+}
+
+func (x *APIKey) GetRevokedAt() string {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return ""
+}
+
+type CreateAPIKeyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Сервисный аккаунт
+	Username string   `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Name     string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes   []string `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// 0 — срок по умолчанию (api_keys.default_expiry_days)
+	ExpiresInDays int32 `protobuf:"varint,4,opt,name=expires_in_days,json=expiresInDays,proto3" json:"expires_in_days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyRequest) Reset() {
+	*x = CreateAPIKeyRequest{}
+	mi := &file_merch_service_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyRequest) ProtoMessage() {}
+
+func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *CreateAPIKeyRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyRequest) GetExpiresInDays() int32 {
+	if x != nil {
+		return x.ExpiresInDays
+	}
+	return 0
+}
+
+type CreateAPIKeyResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey *APIKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// Ключ целиком; показывается только один раз
+	Key           string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyResponse) Reset() {
+	*x = CreateAPIKeyResponse{}
+	mi := &file_merch_service_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyResponse) ProtoMessage() {}
+
+func (x *CreateAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *CreateAPIKeyResponse) GetApiKey() *APIKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyResponse) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type ListAPIKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysRequest) Reset() {
+	*x = ListAPIKeysRequest{}
+	mi := &file_merch_service_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysRequest) ProtoMessage() {}
+
+func (x *ListAPIKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *ListAPIKeysRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type ListAPIKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeys       []*APIKey              `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysResponse) Reset() {
+	*x = ListAPIKeysResponse{}
+	mi := &file_merch_service_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysResponse) ProtoMessage() {}
+
+func (x *ListAPIKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *ListAPIKeysResponse) GetApiKeys() []*APIKey {
+	if x != nil {
+		return x.ApiKeys
+	}
+	return nil
+}
+
+type RevokeAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         int32                  `protobuf:"varint,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyRequest) Reset() {
+	*x = RevokeAPIKeyRequest{}
+	mi := &file_merch_service_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyRequest) ProtoMessage() {}
+
+func (x *RevokeAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *RevokeAPIKeyRequest) GetKeyId() int32 {
+	if x != nil {
+		return x.KeyId
+	}
+	return 0
+}
+
+type RevokeAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyResponse) Reset() {
+	*x = RevokeAPIKeyResponse{}
+	mi := &file_merch_service_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyResponse) ProtoMessage() {}
+
+func (x *RevokeAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *RevokeAPIKeyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// AuditEvent — запись журнала аудита. Время в RFC3339.
+type AuditEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// login | login_failed | password_change | transfer | purchase | grant | reversal | role_change |
+	// service_account_create | api_key_create | api_key_revoke | login_unlock | session_revoke |
+	// totp_enable | totp_disable
+	EventType string `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// Кто совершил действие, 0 — неизвестно (например, неудачный вход)
+	ActorId int32 `protobuf:"varint,3,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	// Чья учётная запись или счёт затронуты
+	TargetId  int32  `protobuf:"varint,4,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`
+	Ip        string `protobuf:"bytes,5,opt,name=ip,proto3" json:"ip,omitempty"`
+	UserAgent string `protobuf:"bytes,6,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	RequestId string `protobuf:"bytes,7,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Currency  string `protobuf:"bytes,8,opt,name=currency,proto3" json:"currency,omitempty"`
+	Amount    int32  `protobuf:"varint,9,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Баланс счёта, с которого списаны или на который зачислены монеты; не задан у событий входа
+	BalanceBefore *int32            `protobuf:"varint,10,opt,name=balance_before,json=balanceBefore,proto3,oneof" json:"balance_before,omitempty"`
+	BalanceAfter  *int32            `protobuf:"varint,11,opt,name=balance_after,json=balanceAfter,proto3,oneof" json:"balance_after,omitempty"`
+	Details       map[string]string `protobuf:"bytes,12,rep,name=details,proto3" json:"details,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CreatedAt     string            `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_merch_service_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *AuditEvent) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetActorId() int32 {
+	if x != nil {
+		return x.ActorId
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetTargetId() int32 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetBalanceBefore() int32 {
+	if x != nil && x.BalanceBefore != nil {
+		return *x.BalanceBefore
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetBalanceAfter() int32 {
+	if x != nil && x.BalanceAfter != nil {
+		return *x.BalanceAfter
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetDetails() map[string]string {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+func (x *AuditEvent) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type QueryAuditLogRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Курсор из next_cursor предыдущей страницы
+	Cursor string `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit  int32  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Границы периода в RFC3339: from включительно, to не включительно
+	From      string `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To        string `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	EventType string `protobuf:"bytes,5,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	ActorId   int32  `protobuf:"varint,6,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	TargetId  int32  `protobuf:"varint,7,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`
+	// События, где пользователь был инициатором или целью
+	UserId        int32  `protobuf:"varint,8,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Ip            string `protobuf:"bytes,9,opt,name=ip,proto3" json:"ip,omitempty"`
+	RequestId     string `protobuf:"bytes,10,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryAuditLogRequest) Reset() {
+	*x = QueryAuditLogRequest{}
+	mi := &file_merch_service_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryAuditLogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryAuditLogRequest) ProtoMessage() {}
+
+func (x *QueryAuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryAuditLogRequest.ProtoReflect.Descriptor instead.
+func (*QueryAuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *QueryAuditLogRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *QueryAuditLogRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
 }
 
-func (x *GetInfoResponse) GetInfo() *UserInfo {
+func (x *QueryAuditLogRequest) GetActorId() int32 {
 	if x != nil {
-		return x.Info
+		return x.ActorId
+	}
+	return 0
+}
+
+func (x *QueryAuditLogRequest) GetTargetId() int32 {
+	if x != nil {
+		return x.TargetId
+	}
+	return 0
+}
+
+func (x *QueryAuditLogRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *QueryAuditLogRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type QueryAuditLogResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*AuditEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryAuditLogResponse) Reset() {
+	*x = QueryAuditLogResponse{}
+	mi := &file_merch_service_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryAuditLogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryAuditLogResponse) ProtoMessage() {}
+
+func (x *QueryAuditLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryAuditLogResponse.ProtoReflect.Descriptor instead.
+func (*QueryAuditLogResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *QueryAuditLogResponse) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *QueryAuditLogResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type UnlockLoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockLoginRequest) Reset() {
+	*x = UnlockLoginRequest{}
+	mi := &file_merch_service_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockLoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockLoginRequest) ProtoMessage() {}
+
+func (x *UnlockLoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockLoginRequest.ProtoReflect.Descriptor instead.
+func (*UnlockLoginRequest) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *UnlockLoginRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type UnlockLoginResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnlockLoginResponse) Reset() {
+	*x = UnlockLoginResponse{}
+	mi := &file_merch_service_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnlockLoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnlockLoginResponse) ProtoMessage() {}
+
+func (x *UnlockLoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnlockLoginResponse.ProtoReflect.Descriptor instead.
+func (*UnlockLoginResponse) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *UnlockLoginResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// AccessPolicy — правило доступа к методу, его проверяет JWT interceptor.
+// Метод без правила доступен любому пользователю с действующим токеном.
+type AccessPolicy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Метод вызывается без токена
+	Public bool `protobuf:"varint,1,opt,name=public,proto3" json:"public,omitempty"`
+	// Достаточно любой из перечисленных ролей
+	Roles []string `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	// Область, которая нужна API-ключу; без неё метод по API-ключу недоступен
+	Scope         string `protobuf:"bytes,3,opt,name=scope,proto3" json:"scope,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessPolicy) Reset() {
+	*x = AccessPolicy{}
+	mi := &file_merch_service_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessPolicy) ProtoMessage() {}
+
+func (x *AccessPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_merch_service_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessPolicy.ProtoReflect.Descriptor instead.
+func (*AccessPolicy) Descriptor() ([]byte, []int) {
+	return file_merch_service_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *AccessPolicy) GetPublic() bool {
+	if x != nil {
+		return x.Public
+	}
+	return false
+}
+
+func (x *AccessPolicy) GetRoles() []string {
+	if x != nil {
+		return x.Roles
 	}
 	return nil
 }
 
+func (x *AccessPolicy) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+var file_merch_service_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*AccessPolicy)(nil),
+		Field:         50001,
+		Name:          "merch.access_policy",
+		Tag:           "bytes,50001,opt,name=access_policy",
+		Filename:      "merch_service.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// optional merch.AccessPolicy access_policy = 50001;
+	E_AccessPolicy = &file_merch_service_proto_extTypes[0]
+)
+
 var File_merch_service_proto protoreflect.FileDescriptor
 
 const file_merch_service_proto_rawDesc = "" +
 	"\n" +
-	"\x13merch_service.proto\x12\x05merch\x1a\x1cgoogle/api/annotations.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"E\n" +
+	"\x13merch_service.proto\x12\x05merch\x1a\x1cgoogle/api/annotations.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\x1a google/protobuf/descriptor.proto\"E\n" +
 	"\vAuthRequest\x12\x1a\n" +
 	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"$\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"\xc3\x01\n" +
 	"\fAuthResponse\x12\x14\n" +
-	"\x05token\x18\x01 \x01(\tR\x05token\"0\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\tR\texpiresAt\x12'\n" +
+	"\x0fchallenge_token\x18\x04 \x01(\tR\x0echallengeToken\x120\n" +
+	"\x14challenge_expires_at\x18\x05 \x01(\tR\x12challengeExpiresAt\":\n" +
+	"\x13RefreshTokenRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"\x0f\n" +
+	"\rLogoutRequest\"*\n" +
+	"\x0eLogoutResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"]\n" +
+	"\x15ChangePasswordRequest\x12!\n" +
+	"\fold_password\x18\x01 \x01(\tR\voldPassword\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\"U\n" +
+	"\x16VerifyTwoFactorRequest\x12'\n" +
+	"\x0fchallenge_token\x18\x01 \x01(\tR\x0echallengeToken\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"\x13\n" +
+	"\x11EnrollTOTPRequest\"W\n" +
+	"\x12EnrollTOTPResponse\x12\x16\n" +
+	"\x06secret\x18\x01 \x01(\tR\x06secret\x12)\n" +
+	"\x10provisioning_uri\x18\x02 \x01(\tR\x0fprovisioningUri\"(\n" +
+	"\x12ConfirmTOTPRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"<\n" +
+	"\x13ConfirmTOTPResponse\x12%\n" +
+	"\x0erecovery_codes\x18\x01 \x03(\tR\rrecoveryCodes\"D\n" +
+	"\x12DisableTOTPRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"/\n" +
+	"\x13DisableTOTPResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xc2\x01\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x02 \x01(\tR\tuserAgent\x12\x0e\n" +
+	"\x02ip\x18\x03 \x01(\tR\x02ip\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12 \n" +
+	"\flast_seen_at\x18\x05 \x01(\tR\n" +
+	"lastSeenAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\tR\texpiresAt\x12\x18\n" +
+	"\acurrent\x18\a \x01(\bR\acurrent\"\x15\n" +
+	"\x13ListSessionsRequest\"B\n" +
+	"\x14ListSessionsResponse\x12*\n" +
+	"\bsessions\x18\x01 \x03(\v2\x0e.merch.SessionR\bsessions\"5\n" +
+	"\x14RevokeSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"1\n" +
+	"\x15RevokeSessionResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"=\n" +
+	"\x18RevokeAllSessionsRequest\x12!\n" +
+	"\fkeep_current\x18\x01 \x01(\bR\vkeepCurrent\"5\n" +
+	"\x19RevokeAllSessionsResponse\x12\x18\n" +
+	"\arevoked\x18\x01 \x01(\x05R\arevoked\"I\n" +
+	"\x0fRegisterRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"F\n" +
+	"\fLoginRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"\x17\n" +
+	"\x15BeginOIDCLoginRequest\"p\n" +
+	"\x16BeginOIDCLoginResponse\x12!\n" +
+	"\flogin_handle\x18\x01 \x01(\tR\vloginHandle\x12\x14\n" +
+	"\x05nonce\x18\x02 \x01(\tR\x05nonce\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\tR\texpiresAt\"Z\n" +
+	"\x14LoginWithOIDCRequest\x12\x19\n" +
+	"\bid_token\x18\x01 \x01(\tR\aidToken\x12!\n" +
+	"\flogin_handle\x18\x03 \x01(\tR\vloginHandleJ\x04\b\x02\x10\x03\"L\n" +
 	"\x0fPurchaseRequest\x12\x1d\n" +
 	"\n" +
-	"merch_name\x18\x02 \x01(\tR\tmerchName\"F\n" +
+	"merch_name\x18\x02 \x01(\tR\tmerchName\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"F\n" +
 	"\x10PurchaseResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"B\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"^\n" +
 	"\x0fTransferRequest\x12\x17\n" +
 	"\ato_user\x18\x02 \x01(\x05R\x06toUser\x12\x16\n" +
-	"\x06amount\x18\x03 \x01(\x05R\x06amount\"F\n" +
+	"\x06amount\x18\x03 \x01(\x05R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"F\n" +
 	"\x10TransferResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\x10\n" +
-	"\x0eGetInfoRequest\"t\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"?\n" +
+	"\fTransferItem\x12\x17\n" +
+	"\ato_user\x18\x01 \x01(\x05R\x06toUser\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x05R\x06amount\"e\n" +
+	"\x14TransferBatchRequest\x121\n" +
+	"\ttransfers\x18\x01 \x03(\v2\x13.merch.TransferItemR\ttransfers\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"n\n" +
+	"\x15TransferBatchResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12!\n" +
+	"\ftotal_amount\x18\x03 \x01(\x05R\vtotalAmount\"\x10\n" +
+	"\x0eGetInfoRequest\"\x90\x01\n" +
 	"\bPurchase\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1d\n" +
 	"\n" +
 	"merch_name\x18\x02 \x01(\tR\tmerchName\x12\x14\n" +
 	"\x05price\x18\x03 \x01(\x05R\x05price\x12#\n" +
-	"\rpurchase_date\x18\x04 \x01(\tR\fpurchaseDate\"\x92\x01\n" +
+	"\rpurchase_date\x18\x04 \x01(\tR\fpurchaseDate\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\"\xcf\x01\n" +
 	"\vTransaction\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1b\n" +
 	"\tsender_id\x18\x02 \x01(\x05R\bsenderId\x12\x1f\n" +
@@ -655,29 +5137,388 @@ const file_merch_service_proto_rawDesc = "" +
 	"receiverId\x12\x16\n" +
 	"\x06amount\x18\x04 \x01(\x05R\x06amount\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x05 \x01(\tR\tcreatedAt\"\xc0\x01\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x1f\n" +
+	"\vreversal_of\x18\x06 \x01(\x05R\n" +
+	"reversalOf\x12\x1a\n" +
+	"\bcurrency\x18\a \x01(\tR\bcurrency\"J\n" +
+	"\rInventoryItem\x12\x1d\n" +
+	"\n" +
+	"merch_name\x18\x01 \x01(\tR\tmerchName\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"^\n" +
+	"\fCoinMovement\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x05R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"e\n" +
+	"\x0fCurrencyBalance\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\x05R\abalance\x12\x1c\n" +
+	"\tspendable\x18\x03 \x01(\bR\tspendable\"g\n" +
+	"\vCoinHistory\x12/\n" +
+	"\breceived\x18\x01 \x03(\v2\x13.merch.CoinMovementR\breceived\x12'\n" +
+	"\x04sent\x18\x02 \x03(\v2\x13.merch.CoinMovementR\x04sent\"\xd5\x03\n" +
 	"\bUserInfo\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x1a\n" +
 	"\busername\x18\x02 \x01(\tR\busername\x12\x18\n" +
 	"\abalance\x18\x03 \x01(\x05R\abalance\x12-\n" +
 	"\tpurchases\x18\x04 \x03(\v2\x0f.merch.PurchaseR\tpurchases\x126\n" +
-	"\ftransactions\x18\x05 \x03(\v2\x12.merch.TransactionR\ftransactions\"6\n" +
+	"\ftransactions\x18\x05 \x03(\v2\x12.merch.TransactionR\ftransactions\x122\n" +
+	"\tinventory\x18\x06 \x03(\v2\x14.merch.InventoryItemR\tinventory\x125\n" +
+	"\fcoin_history\x18\a \x01(\v2\x12.merch.CoinHistoryR\vcoinHistory\x122\n" +
+	"\bbalances\x18\b \x03(\v2\x16.merch.CurrencyBalanceR\bbalances\x12%\n" +
+	"\x0egift_allowance\x18\t \x01(\x05R\rgiftAllowance\x127\n" +
+	"\x18gift_allowance_resets_at\x18\n" +
+	" \x01(\tR\x15giftAllowanceResetsAt\x12\x14\n" +
+	"\x05roles\x18\v \x03(\tR\x05roles\"6\n" +
 	"\x0fGetInfoResponse\x12#\n" +
-	"\x04info\x18\x01 \x01(\v2\x0f.merch.UserInfoR\x04info2\xb0\x03\n" +
-	"\fMerchService\x12M\n" +
-	"\fAuthenticate\x12\x12.merch.AuthRequest\x1a\x13.merch.AuthResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/api/auth\x12}\n" +
-	"\rPurchaseMerch\x12\x16.merch.PurchaseRequest\x1a\x17.merch.PurchaseResponse\";\x92A\x12b\x10\n" +
+	"\x04info\x18\x01 \x01(\v2\x0f.merch.UserInfoR\x04info\"\x8c\x02\n" +
+	"\x17ListTransactionsRequest\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\tR\x02to\x12\x1c\n" +
+	"\tdirection\x18\x05 \x01(\tR\tdirection\x12'\n" +
+	"\x0fcounterparty_id\x18\x06 \x01(\x05R\x0ecounterpartyId\x12\x1d\n" +
+	"\n" +
+	"min_amount\x18\a \x01(\x05R\tminAmount\x12\x1d\n" +
+	"\n" +
+	"max_amount\x18\b \x01(\x05R\tmaxAmount\x12\x1a\n" +
+	"\bcurrency\x18\t \x01(\tR\bcurrency\"s\n" +
+	"\x18ListTransactionsResponse\x126\n" +
+	"\ftransactions\x18\x01 \x03(\v2\x12.merch.TransactionR\ftransactions\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\xc1\x01\n" +
+	"\x14ListPurchasesRequest\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\tR\x02to\x12\x1d\n" +
+	"\n" +
+	"merch_name\x18\x05 \x01(\tR\tmerchName\x12\x1b\n" +
+	"\tmin_price\x18\x06 \x01(\x05R\bminPrice\x12\x1b\n" +
+	"\tmax_price\x18\a \x01(\x05R\bmaxPrice\"g\n" +
+	"\x15ListPurchasesResponse\x12-\n" +
+	"\tpurchases\x18\x01 \x03(\v2\x0f.merch.PurchaseR\tpurchases\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\":\n" +
+	"\x14ExportHistoryRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\"\x80\x01\n" +
+	"\rHistoryRecord\x12-\n" +
+	"\bpurchase\x18\x01 \x01(\v2\x0f.merch.PurchaseH\x00R\bpurchase\x126\n" +
+	"\vtransaction\x18\x02 \x01(\v2\x12.merch.TransactionH\x00R\vtransactionB\b\n" +
+	"\x06record\"-\n" +
+	"\x13GetStatementRequest\x12\x16\n" +
+	"\x06period\x18\x01 \x01(\tR\x06period\"\xc1\x01\n" +
+	"\x11StatementMovement\x12\x1a\n" +
+	"\bcategory\x18\x01 \x01(\tR\bcategory\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x05R\x02id\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x05R\x06amount\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12'\n" +
+	"\x0fcounterparty_id\x18\x06 \x01(\x05R\x0ecounterpartyId\"C\n" +
+	"\rCategoryTotal\x12\x1a\n" +
+	"\bcategory\x18\x01 \x01(\tR\bcategory\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x05R\x06amount\"\xfe\x01\n" +
+	"\tStatement\x12\x16\n" +
+	"\x06period\x18\x01 \x01(\tR\x06period\x12'\n" +
+	"\x0fopening_balance\x18\x02 \x01(\x05R\x0eopeningBalance\x12'\n" +
+	"\x0fclosing_balance\x18\x03 \x01(\x05R\x0eclosingBalance\x126\n" +
+	"\tmovements\x18\x04 \x03(\v2\x18.merch.StatementMovementR\tmovements\x12,\n" +
+	"\x06totals\x18\x05 \x03(\v2\x14.merch.CategoryTotalR\x06totals\x12!\n" +
+	"\fgenerated_at\x18\x06 \x01(\tR\vgeneratedAt\"F\n" +
+	"\x14GetStatementResponse\x12.\n" +
+	"\tstatement\x18\x01 \x01(\v2\x10.merch.StatementR\tstatement\"\x19\n" +
+	"\x17GetExpiringCoinsRequest\"g\n" +
+	"\rExpiringCoins\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x05R\x06amount\x12\x1f\n" +
+	"\vreceived_at\x18\x02 \x01(\tR\n" +
+	"receivedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\tR\texpiresAt\"Z\n" +
+	"\x18GetExpiringCoinsResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x05R\x05total\x12(\n" +
+	"\x04lots\x18\x02 \x03(\v2\x14.merch.ExpiringCoinsR\x04lots\"_\n" +
+	"\x11GrantCoinsRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x05R\x06amount\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"I\n" +
+	"\x12GrantCoinsResponse\x12\x19\n" +
+	"\bgrant_id\x18\x01 \x01(\x05R\agrantId\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\")\n" +
+	"\x15GrantCoinsBulkRequest\x12\x10\n" +
+	"\x03csv\x18\x01 \x01(\tR\x03csv\"j\n" +
+	"\x16GrantCoinsBulkResponse\x12\x19\n" +
+	"\bbatch_id\x18\x01 \x01(\x05R\abatchId\x12\x12\n" +
+	"\x04rows\x18\x02 \x01(\x05R\x04rows\x12!\n" +
+	"\ftotal_amount\x18\x03 \x01(\x05R\vtotalAmount\"Z\n" +
+	"\x19ReverseTransactionRequest\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\x05R\rtransactionId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xab\x01\n" +
+	"\x1aReverseTransactionResponse\x12\x1f\n" +
+	"\vreversal_id\x18\x01 \x01(\x05R\n" +
+	"reversalId\x12)\n" +
+	"\x10requested_amount\x18\x02 \x01(\x05R\x0frequestedAmount\x12'\n" +
+	"\x0freversed_amount\x18\x03 \x01(\x05R\x0ereversedAmount\x12\x18\n" +
+	"\apartial\x18\x04 \x01(\bR\apartial\"\xa5\x02\n" +
+	"\vCoinRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12!\n" +
+	"\frequester_id\x18\x02 \x01(\x05R\vrequesterId\x12\x19\n" +
+	"\bpayer_id\x18\x03 \x01(\x05R\apayerId\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x05R\x06amount\x12\x12\n" +
+	"\x04note\x18\x05 \x01(\tR\x04note\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12%\n" +
+	"\x0etransaction_id\x18\a \x01(\x05R\rtransactionId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\t \x01(\tR\texpiresAt\x12\x1f\n" +
+	"\vresolved_at\x18\n" +
+	" \x01(\tR\n" +
+	"resolvedAt\"^\n" +
+	"\x13RequestCoinsRequest\x12\x1b\n" +
+	"\tfrom_user\x18\x01 \x01(\x05R\bfromUser\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x05R\x06amount\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\"D\n" +
+	"\x14RequestCoinsResponse\x12,\n" +
+	"\arequest\x18\x01 \x01(\v2\x12.merch.CoinRequestR\arequest\"O\n" +
+	"\x17ListCoinRequestsRequest\x12\x1c\n" +
+	"\tdirection\x18\x01 \x01(\tR\tdirection\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"J\n" +
+	"\x18ListCoinRequestsResponse\x12.\n" +
+	"\brequests\x18\x01 \x03(\v2\x12.merch.CoinRequestR\brequests\":\n" +
+	"\x19ResolveCoinRequestRequest\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\x05R\trequestId\"J\n" +
+	"\x1aResolveCoinRequestResponse\x12,\n" +
+	"\arequest\x18\x01 \x01(\v2\x12.merch.CoinRequestR\arequest\"G\n" +
+	"\x13SetUserRolesRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\"H\n" +
+	"\x14SetUserRolesResponse\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\"O\n" +
+	"\x1bCreateServiceAccountRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\"i\n" +
+	"\x1cCreateServiceAccountResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
+	"\x05roles\x18\x03 \x03(\tR\x05roles\"\xf7\x01\n" +
+	"\x06APIKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06prefix\x18\x04 \x01(\tR\x06prefix\x12\x16\n" +
+	"\x06scopes\x18\x05 \x03(\tR\x06scopes\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\tR\texpiresAt\x12 \n" +
+	"\flast_used_at\x18\b \x01(\tR\n" +
+	"lastUsedAt\x12\x1d\n" +
+	"\n" +
+	"revoked_at\x18\t \x01(\tR\trevokedAt\"\x85\x01\n" +
+	"\x13CreateAPIKeyRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x12&\n" +
+	"\x0fexpires_in_days\x18\x04 \x01(\x05R\rexpiresInDays\"P\n" +
+	"\x14CreateAPIKeyResponse\x12&\n" +
+	"\aapi_key\x18\x01 \x01(\v2\r.merch.APIKeyR\x06apiKey\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\"0\n" +
+	"\x12ListAPIKeysRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"?\n" +
+	"\x13ListAPIKeysResponse\x12(\n" +
+	"\bapi_keys\x18\x01 \x03(\v2\r.merch.APIKeyR\aapiKeys\",\n" +
+	"\x13RevokeAPIKeyRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\x05R\x05keyId\"0\n" +
+	"\x14RevokeAPIKeyResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x85\x04\n" +
+	"\n" +
+	"AuditEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tR\teventType\x12\x19\n" +
+	"\bactor_id\x18\x03 \x01(\x05R\aactorId\x12\x1b\n" +
+	"\ttarget_id\x18\x04 \x01(\x05R\btargetId\x12\x0e\n" +
+	"\x02ip\x18\x05 \x01(\tR\x02ip\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x06 \x01(\tR\tuserAgent\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\a \x01(\tR\trequestId\x12\x1a\n" +
+	"\bcurrency\x18\b \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06amount\x18\t \x01(\x05R\x06amount\x12*\n" +
+	"\x0ebalance_before\x18\n" +
+	" \x01(\x05H\x00R\rbalanceBefore\x88\x01\x01\x12(\n" +
+	"\rbalance_after\x18\v \x01(\x05H\x01R\fbalanceAfter\x88\x01\x01\x128\n" +
+	"\adetails\x18\f \x03(\v2\x1e.merch.AuditEvent.DetailsEntryR\adetails\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\r \x01(\tR\tcreatedAt\x1a:\n" +
+	"\fDetailsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x11\n" +
+	"\x0f_balance_beforeB\x10\n" +
+	"\x0e_balance_after\"\x87\x02\n" +
+	"\x14QueryAuditLogRequest\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\tR\x02to\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x05 \x01(\tR\teventType\x12\x19\n" +
+	"\bactor_id\x18\x06 \x01(\x05R\aactorId\x12\x1b\n" +
+	"\ttarget_id\x18\a \x01(\x05R\btargetId\x12\x17\n" +
+	"\auser_id\x18\b \x01(\x05R\x06userId\x12\x0e\n" +
+	"\x02ip\x18\t \x01(\tR\x02ip\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\n" +
+	" \x01(\tR\trequestId\"c\n" +
+	"\x15QueryAuditLogResponse\x12)\n" +
+	"\x06events\x18\x01 \x03(\v2\x11.merch.AuditEventR\x06events\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"0\n" +
+	"\x12UnlockLoginRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\"/\n" +
+	"\x13UnlockLoginResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"R\n" +
+	"\fAccessPolicy\x12\x16\n" +
+	"\x06public\x18\x01 \x01(\bR\x06public\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\x12\x14\n" +
+	"\x05scope\x18\x03 \x01(\tR\x05scope2\x88)\n" +
+	"\fMerchService\x12S\n" +
+	"\fAuthenticate\x12\x12.merch.AuthRequest\x1a\x13.merch.AuthResponse\"\x1a\x8a\xb5\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/api/auth\x12\\\n" +
+	"\bRegister\x12\x16.merch.RegisterRequest\x1a\x13.merch.AuthResponse\"#\x8a\xb5\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/api/auth/register\x12S\n" +
+	"\x05Login\x12\x13.merch.LoginRequest\x1a\x13.merch.AuthResponse\" \x8a\xb5\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/api/auth/login\x12U\n" +
+	"\x0eBeginOIDCLogin\x12\x1c.merch.BeginOIDCLoginRequest\x1a\x1d.merch.BeginOIDCLoginResponse\"\x06\x8a\xb5\x18\x02\b\x01\x12I\n" +
+	"\rLoginWithOIDC\x12\x1b.merch.LoginWithOIDCRequest\x1a\x13.merch.AuthResponse\"\x06\x8a\xb5\x18\x02\b\x01\x12c\n" +
+	"\fRefreshToken\x12\x1a.merch.RefreshTokenRequest\x1a\x13.merch.AuthResponse\"\"\x8a\xb5\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/api/auth/refresh\x12g\n" +
+	"\x06Logout\x12\x14.merch.LogoutRequest\x1a\x15.merch.LogoutResponse\"0\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/api/auth/logout\x12x\n" +
+	"\fListSessions\x12\x1a.merch.ListSessionsRequest\x1a\x1b.merch.ListSessionsResponse\"/\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x82\xd3\xe4\x93\x02\x14\x12\x12/api/auth/sessions\x12\x92\x01\n" +
+	"\rRevokeSession\x12\x1b.merch.RevokeSessionRequest\x1a\x1c.merch.RevokeSessionResponse\"F\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x82\xd3\xe4\x93\x02+:\x01*\"&/api/auth/sessions/{session_id}/revoke\x12\x95\x01\n" +
+	"\x11RevokeAllSessions\x12\x1f.merch.RevokeAllSessionsRequest\x1a .merch.RevokeAllSessionsResponse\"=\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/api/auth/sessions/revoke-all\x12w\n" +
+	"\x0eChangePassword\x12\x1c.merch.ChangePasswordRequest\x1a\x13.merch.AuthResponse\"2\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/api/auth/password\x12e\n" +
+	"\x0fVerifyTwoFactor\x12\x1d.merch.VerifyTwoFactorRequest\x1a\x13.merch.AuthResponse\"\x1e\x8a\xb5\x18\x02\b\x01\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/api/auth/2fa\x12w\n" +
+	"\n" +
+	"EnrollTOTP\x12\x18.merch.EnrollTOTPRequest\x1a\x19.merch.EnrollTOTPResponse\"4\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/api/auth/2fa/enroll\x12{\n" +
+	"\vConfirmTOTP\x12\x19.merch.ConfirmTOTPRequest\x1a\x1a.merch.ConfirmTOTPResponse\"5\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/api/auth/2fa/confirm\x12{\n" +
+	"\vDisableTOTP\x12\x19.merch.DisableTOTPRequest\x1a\x1a.merch.DisableTOTPResponse\"5\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/api/auth/2fa/disable\x12\x91\x01\n" +
+	"\rPurchaseMerch\x12\x16.merch.PurchaseRequest\x1a\x17.merch.PurchaseResponse\"O\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18\x10\x1a\x0emerch:purchase\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/api/merch/buy/{merch_name}\x12\x84\x01\n" +
+	"\rTransferCoins\x12\x16.merch.TransferRequest\x1a\x17.merch.TransferResponse\"B\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18\x10\x1a\x0ecoins:transfer\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/api/send-coin\x12\x99\x01\n" +
+	"\x12TransferCoinsBatch\x12\x1b.merch.TransferBatchRequest\x1a\x1c.merch.TransferBatchResponse\"H\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18\x10\x1a\x0ecoins:transfer\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/api/send-coin/batch\x12r\n" +
+	"\aGetInfo\x12\x15.merch.GetInfoRequest\x1a\x16.merch.GetInfoResponse\"8\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18\x0e\x1a\fhistory:read\x82\xd3\xe4\x93\x02\v\x12\t/api/info\x12\x95\x01\n" +
+	"\x10ListTransactions\x12\x1e.merch.ListTransactionsRequest\x1a\x1f.merch.ListTransactionsResponse\"@\x92A\x12b\x10\n" +
 	"\x0e\n" +
 	"\n" +
-	"BearerAuth\x12\x00\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/api/merch/buy/{merch_name}\x12p\n" +
-	"\rTransferCoins\x12\x16.merch.TransferRequest\x1a\x17.merch.TransferResponse\".\x92A\x12b\x10\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18\x0e\x1a\fhistory:read\x82\xd3\xe4\x93\x02\x13\x12\x11/api/transactions\x12\x89\x01\n" +
+	"\rListPurchases\x12\x1b.merch.ListPurchasesRequest\x1a\x1c.merch.ListPurchasesResponse\"=\x92A\x12b\x10\n" +
 	"\x0e\n" +
 	"\n" +
-	"BearerAuth\x12\x00\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/api/send-coin\x12`\n" +
-	"\aGetInfo\x12\x15.merch.GetInfoRequest\x1a\x16.merch.GetInfoResponse\"&\x92A\x12b\x10\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18\x0e\x1a\fhistory:read\x82\xd3\xe4\x93\x02\x10\x12\x0e/api/purchases\x12X\n" +
+	"\rExportHistory\x12\x1b.merch.ExportHistoryRequest\x1a\x14.merch.HistoryRecord\"\x12\x8a\xb5\x18\x0e\x1a\fhistory:read0\x01\x12\x90\x01\n" +
+	"\fGetStatement\x12\x1a.merch.GetStatementRequest\x1a\x1b.merch.GetStatementResponse\"G\x92A\x12b\x10\n" +
 	"\x0e\n" +
 	"\n" +
-	"BearerAuth\x12\x00\x82\xd3\xe4\x93\x02\v\x12\t/api/infoBb\x92AT\x12\x12\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18\x0e\x1a\fhistory:read\x82\xd3\xe4\x93\x02\x1a\x12\x18/api/statements/{period}\x12\x97\x01\n" +
+	"\x10GetExpiringCoins\x12\x1e.merch.GetExpiringCoinsRequest\x1a\x1f.merch.GetExpiringCoinsResponse\"B\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18\x0e\x1a\fhistory:read\x82\xd3\xe4\x93\x02\x15\x12\x13/api/coins/expiring\x12\xa0\x01\n" +
+	"\n" +
+	"GrantCoins\x12\x18.merch.GrantCoinsRequest\x1a\x19.merch.GrantCoinsResponse\"]\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18#\x12\vstore_admin\x12\afinance\x1a\vcoins:grant\x82\xd3\xe4\x93\x02\x1b:\x01*\"\x16/api/admin/coins/grant\x12\xb1\x01\n" +
+	"\x0eGrantCoinsBulk\x12\x1c.merch.GrantCoinsBulkRequest\x1a\x1d.merch.GrantCoinsBulkResponse\"b\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18#\x12\vstore_admin\x12\afinance\x1a\vcoins:grant\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/api/admin/coins/grant/bulk\x12\xe5\x01\n" +
+	"\x12ReverseTransaction\x12 .merch.ReverseTransactionRequest\x1a!.merch.ReverseTransactionResponse\"\x89\x01\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x185\x12\vstore_admin\x12\afinance\x12\asupport\x1a\x14transactions:reverse\x82\xd3\xe4\x93\x025:\x01*\"0/api/admin/transactions/{transaction_id}/reverse\x12\xa9\x01\n" +
+	"\fSetUserRoles\x12\x1a.merch.SetUserRolesRequest\x1a\x1b.merch.SetUserRolesResponse\"`\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18\x1b\x12\vstore_admin\x1a\fusers:manage\x82\xd3\xe4\x93\x02&:\x01*\x1a!/api/admin/users/{username}/roles\x12\xa3\x01\n" +
+	"\rQueryAuditLog\x12\x1b.merch.QueryAuditLogRequest\x1a\x1c.merch.QueryAuditLogResponse\"W\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18\"\x12\vstore_admin\x12\afinance\x1a\n" +
+	"audit:read\x82\xd3\xe4\x93\x02\x16\x12\x14/api/admin/audit-log\x12\xb0\x01\n" +
+	"\vUnlockLogin\x12\x19.merch.UnlockLoginRequest\x1a\x1a.merch.UnlockLoginResponse\"j\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18$\x12\vstore_admin\x12\asupport\x1a\fusers:manage\x82\xd3\xe4\x93\x02':\x01*\"\"/api/admin/users/{username}/unlock\x12\xad\x01\n" +
+	"\x14CreateServiceAccount\x12\".merch.CreateServiceAccountRequest\x1a#.merch.CreateServiceAccountResponse\"L\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18\r\x12\vstore_admin\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/api/admin/service-accounts\x12\xa9\x01\n" +
+	"\fCreateAPIKey\x12\x1a.merch.CreateAPIKeyRequest\x1a\x1b.merch.CreateAPIKeyResponse\"`\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18\r\x12\vstore_admin\x82\xd3\xe4\x93\x024:\x01*\"//api/admin/service-accounts/{username}/api-keys\x12\xa3\x01\n" +
+	"\vListAPIKeys\x12\x19.merch.ListAPIKeysRequest\x1a\x1a.merch.ListAPIKeysResponse\"]\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18\r\x12\vstore_admin\x82\xd3\xe4\x93\x021\x12//api/admin/service-accounts/{username}/api-keys\x12\x9d\x01\n" +
+	"\fRevokeAPIKey\x12\x1a.merch.RevokeAPIKeyRequest\x1a\x1b.merch.RevokeAPIKeyResponse\"T\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x8a\xb5\x18\r\x12\vstore_admin\x82\xd3\xe4\x93\x02(:\x01*\"#/api/admin/api-keys/{key_id}/revoke\x12{\n" +
+	"\fRequestCoins\x12\x1a.merch.RequestCoinsRequest\x1a\x1b.merch.RequestCoinsResponse\"2\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/api/coin-requests\x12\x84\x01\n" +
+	"\x10ListCoinRequests\x12\x1e.merch.ListCoinRequestsRequest\x1a\x1f.merch.ListCoinRequestsResponse\"/\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x82\xd3\xe4\x93\x02\x14\x12\x12/api/coin-requests\x12\xa2\x01\n" +
+	"\x12ApproveCoinRequest\x12 .merch.ResolveCoinRequestRequest\x1a!.merch.ResolveCoinRequestResponse\"G\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x82\xd3\xe4\x93\x02,:\x01*\"'/api/coin-requests/{request_id}/approve\x12\xa0\x01\n" +
+	"\x11RejectCoinRequest\x12 .merch.ResolveCoinRequestRequest\x1a!.merch.ResolveCoinRequestResponse\"F\x92A\x12b\x10\n" +
+	"\x0e\n" +
+	"\n" +
+	"BearerAuth\x12\x00\x82\xd3\xe4\x93\x02+:\x01*\"&/api/coin-requests/{request_id}/reject:Z\n" +
+	"\raccess_policy\x12\x1e.google.protobuf.MethodOptions\x18ц\x03 \x01(\v2\x13.merch.AccessPolicyR\faccessPolicyBb\x92AT\x12\x12\n" +
 	"\vMerch Store2\x031.0\x1a\x0elocalhost:8090Z.\n" +
 	",\n" +
 	"\n" +
@@ -695,37 +5536,205 @@ func file_merch_service_proto_rawDescGZIP() []byte {
 	return file_merch_service_proto_rawDescData
 }
 
-var file_merch_service_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_merch_service_proto_msgTypes = make([]protoimpl.MessageInfo, 86)
 var file_merch_service_proto_goTypes = []any{
-	(*AuthRequest)(nil),      // 0: merch.AuthRequest
-	(*AuthResponse)(nil),     // 1: merch.AuthResponse
-	(*PurchaseRequest)(nil),  // 2: merch.PurchaseRequest
-	(*PurchaseResponse)(nil), // 3: merch.PurchaseResponse
-	(*TransferRequest)(nil),  // 4: merch.TransferRequest
-	(*TransferResponse)(nil), // 5: merch.TransferResponse
-	(*GetInfoRequest)(nil),   // 6: merch.GetInfoRequest
-	(*Purchase)(nil),         // 7: merch.Purchase
-	(*Transaction)(nil),      // 8: merch.Transaction
-	(*UserInfo)(nil),         // 9: merch.UserInfo
-	(*GetInfoResponse)(nil),  // 10: merch.GetInfoResponse
+	(*AuthRequest)(nil),                  // 0: merch.AuthRequest
+	(*AuthResponse)(nil),                 // 1: merch.AuthResponse
+	(*RefreshTokenRequest)(nil),          // 2: merch.RefreshTokenRequest
+	(*LogoutRequest)(nil),                // 3: merch.LogoutRequest
+	(*LogoutResponse)(nil),               // 4: merch.LogoutResponse
+	(*ChangePasswordRequest)(nil),        // 5: merch.ChangePasswordRequest
+	(*VerifyTwoFactorRequest)(nil),       // 6: merch.VerifyTwoFactorRequest
+	(*EnrollTOTPRequest)(nil),            // 7: merch.EnrollTOTPRequest
+	(*EnrollTOTPResponse)(nil),           // 8: merch.EnrollTOTPResponse
+	(*ConfirmTOTPRequest)(nil),           // 9: merch.ConfirmTOTPRequest
+	(*ConfirmTOTPResponse)(nil),          // 10: merch.ConfirmTOTPResponse
+	(*DisableTOTPRequest)(nil),           // 11: merch.DisableTOTPRequest
+	(*DisableTOTPResponse)(nil),          // 12: merch.DisableTOTPResponse
+	(*Session)(nil),                      // 13: merch.Session
+	(*ListSessionsRequest)(nil),          // 14: merch.ListSessionsRequest
+	(*ListSessionsResponse)(nil),         // 15: merch.ListSessionsResponse
+	(*RevokeSessionRequest)(nil),         // 16: merch.RevokeSessionRequest
+	(*RevokeSessionResponse)(nil),        // 17: merch.RevokeSessionResponse
+	(*RevokeAllSessionsRequest)(nil),     // 18: merch.RevokeAllSessionsRequest
+	(*RevokeAllSessionsResponse)(nil),    // 19: merch.RevokeAllSessionsResponse
+	(*RegisterRequest)(nil),              // 20: merch.RegisterRequest
+	(*LoginRequest)(nil),                 // 21: merch.LoginRequest
+	(*BeginOIDCLoginRequest)(nil),        // 22: merch.BeginOIDCLoginRequest
+	(*BeginOIDCLoginResponse)(nil),       // 23: merch.BeginOIDCLoginResponse
+	(*LoginWithOIDCRequest)(nil),         // 24: merch.LoginWithOIDCRequest
+	(*PurchaseRequest)(nil),              // 25: merch.PurchaseRequest
+	(*PurchaseResponse)(nil),             // 26: merch.PurchaseResponse
+	(*TransferRequest)(nil),              // 27: merch.TransferRequest
+	(*TransferResponse)(nil),             // 28: merch.TransferResponse
+	(*TransferItem)(nil),                 // 29: merch.TransferItem
+	(*TransferBatchRequest)(nil),         // 30: merch.TransferBatchRequest
+	(*TransferBatchResponse)(nil),        // 31: merch.TransferBatchResponse
+	(*GetInfoRequest)(nil),               // 32: merch.GetInfoRequest
+	(*Purchase)(nil),                     // 33: merch.Purchase
+	(*Transaction)(nil),                  // 34: merch.Transaction
+	(*InventoryItem)(nil),                // 35: merch.InventoryItem
+	(*CoinMovement)(nil),                 // 36: merch.CoinMovement
+	(*CurrencyBalance)(nil),              // 37: merch.CurrencyBalance
+	(*CoinHistory)(nil),                  // 38: merch.CoinHistory
+	(*UserInfo)(nil),                     // 39: merch.UserInfo
+	(*GetInfoResponse)(nil),              // 40: merch.GetInfoResponse
+	(*ListTransactionsRequest)(nil),      // 41: merch.ListTransactionsRequest
+	(*ListTransactionsResponse)(nil),     // 42: merch.ListTransactionsResponse
+	(*ListPurchasesRequest)(nil),         // 43: merch.ListPurchasesRequest
+	(*ListPurchasesResponse)(nil),        // 44: merch.ListPurchasesResponse
+	(*ExportHistoryRequest)(nil),         // 45: merch.ExportHistoryRequest
+	(*HistoryRecord)(nil),                // 46: merch.HistoryRecord
+	(*GetStatementRequest)(nil),          // 47: merch.GetStatementRequest
+	(*StatementMovement)(nil),            // 48: merch.StatementMovement
+	(*CategoryTotal)(nil),                // 49: merch.CategoryTotal
+	(*Statement)(nil),                    // 50: merch.Statement
+	(*GetStatementResponse)(nil),         // 51: merch.GetStatementResponse
+	(*GetExpiringCoinsRequest)(nil),      // 52: merch.GetExpiringCoinsRequest
+	(*ExpiringCoins)(nil),                // 53: merch.ExpiringCoins
+	(*GetExpiringCoinsResponse)(nil),     // 54: merch.GetExpiringCoinsResponse
+	(*GrantCoinsRequest)(nil),            // 55: merch.GrantCoinsRequest
+	(*GrantCoinsResponse)(nil),           // 56: merch.GrantCoinsResponse
+	(*GrantCoinsBulkRequest)(nil),        // 57: merch.GrantCoinsBulkRequest
+	(*GrantCoinsBulkResponse)(nil),       // 58: merch.GrantCoinsBulkResponse
+	(*ReverseTransactionRequest)(nil),    // 59: merch.ReverseTransactionRequest
+	(*ReverseTransactionResponse)(nil),   // 60: merch.ReverseTransactionResponse
+	(*CoinRequest)(nil),                  // 61: merch.CoinRequest
+	(*RequestCoinsRequest)(nil),          // 62: merch.RequestCoinsRequest
+	(*RequestCoinsResponse)(nil),         // 63: merch.RequestCoinsResponse
+	(*ListCoinRequestsRequest)(nil),      // 64: merch.ListCoinRequestsRequest
+	(*ListCoinRequestsResponse)(nil),     // 65: merch.ListCoinRequestsResponse
+	(*ResolveCoinRequestRequest)(nil),    // 66: merch.ResolveCoinRequestRequest
+	(*ResolveCoinRequestResponse)(nil),   // 67: merch.ResolveCoinRequestResponse
+	(*SetUserRolesRequest)(nil),          // 68: merch.SetUserRolesRequest
+	(*SetUserRolesResponse)(nil),         // 69: merch.SetUserRolesResponse
+	(*CreateServiceAccountRequest)(nil),  // 70: merch.CreateServiceAccountRequest
+	(*CreateServiceAccountResponse)(nil), // 71: merch.CreateServiceAccountResponse
+	(*APIKey)(nil),                       // 72: merch.APIKey
+	(*CreateAPIKeyRequest)(nil),          // 73: merch.CreateAPIKeyRequest
+	(*CreateAPIKeyResponse)(nil),         // 74: merch.CreateAPIKeyResponse
+	(*ListAPIKeysRequest)(nil),           // 75: merch.ListAPIKeysRequest
+	(*ListAPIKeysResponse)(nil),          // 76: merch.ListAPIKeysResponse
+	(*RevokeAPIKeyRequest)(nil),          // 77: merch.RevokeAPIKeyRequest
+	(*RevokeAPIKeyResponse)(nil),         // 78: merch.RevokeAPIKeyResponse
+	(*AuditEvent)(nil),                   // 79: merch.AuditEvent
+	(*QueryAuditLogRequest)(nil),         // 80: merch.QueryAuditLogRequest
+	(*QueryAuditLogResponse)(nil),        // 81: merch.QueryAuditLogResponse
+	(*UnlockLoginRequest)(nil),           // 82: merch.UnlockLoginRequest
+	(*UnlockLoginResponse)(nil),          // 83: merch.UnlockLoginResponse
+	(*AccessPolicy)(nil),                 // 84: merch.AccessPolicy
+	nil,                                  // 85: merch.AuditEvent.DetailsEntry
+	(*descriptorpb.MethodOptions)(nil),   // 86: google.protobuf.MethodOptions
 }
 var file_merch_service_proto_depIdxs = []int32{
-	7,  // 0: merch.UserInfo.purchases:type_name -> merch.Purchase
-	8,  // 1: merch.UserInfo.transactions:type_name -> merch.Transaction
-	9,  // 2: merch.GetInfoResponse.info:type_name -> merch.UserInfo
-	0,  // 3: merch.MerchService.Authenticate:input_type -> merch.AuthRequest
-	2,  // 4: merch.MerchService.PurchaseMerch:input_type -> merch.PurchaseRequest
-	4,  // 5: merch.MerchService.TransferCoins:input_type -> merch.TransferRequest
-	6,  // 6: merch.MerchService.GetInfo:input_type -> merch.GetInfoRequest
-	1,  // 7: merch.MerchService.Authenticate:output_type -> merch.AuthResponse
-	3,  // 8: merch.MerchService.PurchaseMerch:output_type -> merch.PurchaseResponse
-	5,  // 9: merch.MerchService.TransferCoins:output_type -> merch.TransferResponse
-	10, // 10: merch.MerchService.GetInfo:output_type -> merch.GetInfoResponse
-	7,  // [7:11] is the sub-list for method output_type
-	3,  // [3:7] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	13, // 0: merch.ListSessionsResponse.sessions:type_name -> merch.Session
+	29, // 1: merch.TransferBatchRequest.transfers:type_name -> merch.TransferItem
+	36, // 2: merch.CoinHistory.received:type_name -> merch.CoinMovement
+	36, // 3: merch.CoinHistory.sent:type_name -> merch.CoinMovement
+	33, // 4: merch.UserInfo.purchases:type_name -> merch.Purchase
+	34, // 5: merch.UserInfo.transactions:type_name -> merch.Transaction
+	35, // 6: merch.UserInfo.inventory:type_name -> merch.InventoryItem
+	38, // 7: merch.UserInfo.coin_history:type_name -> merch.CoinHistory
+	37, // 8: merch.UserInfo.balances:type_name -> merch.CurrencyBalance
+	39, // 9: merch.GetInfoResponse.info:type_name -> merch.UserInfo
+	34, // 10: merch.ListTransactionsResponse.transactions:type_name -> merch.Transaction
+	33, // 11: merch.ListPurchasesResponse.purchases:type_name -> merch.Purchase
+	33, // 12: merch.HistoryRecord.purchase:type_name -> merch.Purchase
+	34, // 13: merch.HistoryRecord.transaction:type_name -> merch.Transaction
+	48, // 14: merch.Statement.movements:type_name -> merch.StatementMovement
+	49, // 15: merch.Statement.totals:type_name -> merch.CategoryTotal
+	50, // 16: merch.GetStatementResponse.statement:type_name -> merch.Statement
+	53, // 17: merch.GetExpiringCoinsResponse.lots:type_name -> merch.ExpiringCoins
+	61, // 18: merch.RequestCoinsResponse.request:type_name -> merch.CoinRequest
+	61, // 19: merch.ListCoinRequestsResponse.requests:type_name -> merch.CoinRequest
+	61, // 20: merch.ResolveCoinRequestResponse.request:type_name -> merch.CoinRequest
+	72, // 21: merch.CreateAPIKeyResponse.api_key:type_name -> merch.APIKey
+	72, // 22: merch.ListAPIKeysResponse.api_keys:type_name -> merch.APIKey
+	85, // 23: merch.AuditEvent.details:type_name -> merch.AuditEvent.DetailsEntry
+	79, // 24: merch.QueryAuditLogResponse.events:type_name -> merch.AuditEvent
+	86, // 25: merch.access_policy:extendee -> google.protobuf.MethodOptions
+	84, // 26: merch.access_policy:type_name -> merch.AccessPolicy
+	0,  // 27: merch.MerchService.Authenticate:input_type -> merch.AuthRequest
+	20, // 28: merch.MerchService.Register:input_type -> merch.RegisterRequest
+	21, // 29: merch.MerchService.Login:input_type -> merch.LoginRequest
+	22, // 30: merch.MerchService.BeginOIDCLogin:input_type -> merch.BeginOIDCLoginRequest
+	24, // 31: merch.MerchService.LoginWithOIDC:input_type -> merch.LoginWithOIDCRequest
+	2,  // 32: merch.MerchService.RefreshToken:input_type -> merch.RefreshTokenRequest
+	3,  // 33: merch.MerchService.Logout:input_type -> merch.LogoutRequest
+	14, // 34: merch.MerchService.ListSessions:input_type -> merch.ListSessionsRequest
+	16, // 35: merch.MerchService.RevokeSession:input_type -> merch.RevokeSessionRequest
+	18, // 36: merch.MerchService.RevokeAllSessions:input_type -> merch.RevokeAllSessionsRequest
+	5,  // 37: merch.MerchService.ChangePassword:input_type -> merch.ChangePasswordRequest
+	6,  // 38: merch.MerchService.VerifyTwoFactor:input_type -> merch.VerifyTwoFactorRequest
+	7,  // 39: merch.MerchService.EnrollTOTP:input_type -> merch.EnrollTOTPRequest
+	9,  // 40: merch.MerchService.ConfirmTOTP:input_type -> merch.ConfirmTOTPRequest
+	11, // 41: merch.MerchService.DisableTOTP:input_type -> merch.DisableTOTPRequest
+	25, // 42: merch.MerchService.PurchaseMerch:input_type -> merch.PurchaseRequest
+	27, // 43: merch.MerchService.TransferCoins:input_type -> merch.TransferRequest
+	30, // 44: merch.MerchService.TransferCoinsBatch:input_type -> merch.TransferBatchRequest
+	32, // 45: merch.MerchService.GetInfo:input_type -> merch.GetInfoRequest
+	41, // 46: merch.MerchService.ListTransactions:input_type -> merch.ListTransactionsRequest
+	43, // 47: merch.MerchService.ListPurchases:input_type -> merch.ListPurchasesRequest
+	45, // 48: merch.MerchService.ExportHistory:input_type -> merch.ExportHistoryRequest
+	47, // 49: merch.MerchService.GetStatement:input_type -> merch.GetStatementRequest
+	52, // 50: merch.MerchService.GetExpiringCoins:input_type -> merch.GetExpiringCoinsRequest
+	55, // 51: merch.MerchService.GrantCoins:input_type -> merch.GrantCoinsRequest
+	57, // 52: merch.MerchService.GrantCoinsBulk:input_type -> merch.GrantCoinsBulkRequest
+	59, // 53: merch.MerchService.ReverseTransaction:input_type -> merch.ReverseTransactionRequest
+	68, // 54: merch.MerchService.SetUserRoles:input_type -> merch.SetUserRolesRequest
+	80, // 55: merch.MerchService.QueryAuditLog:input_type -> merch.QueryAuditLogRequest
+	82, // 56: merch.MerchService.UnlockLogin:input_type -> merch.UnlockLoginRequest
+	70, // 57: merch.MerchService.CreateServiceAccount:input_type -> merch.CreateServiceAccountRequest
+	73, // 58: merch.MerchService.CreateAPIKey:input_type -> merch.CreateAPIKeyRequest
+	75, // 59: merch.MerchService.ListAPIKeys:input_type -> merch.ListAPIKeysRequest
+	77, // 60: merch.MerchService.RevokeAPIKey:input_type -> merch.RevokeAPIKeyRequest
+	62, // 61: merch.MerchService.RequestCoins:input_type -> merch.RequestCoinsRequest
+	64, // 62: merch.MerchService.ListCoinRequests:input_type -> merch.ListCoinRequestsRequest
+	66, // 63: merch.MerchService.ApproveCoinRequest:input_type -> merch.ResolveCoinRequestRequest
+	66, // 64: merch.MerchService.RejectCoinRequest:input_type -> merch.ResolveCoinRequestRequest
+	1,  // 65: merch.MerchService.Authenticate:output_type -> merch.AuthResponse
+	1,  // 66: merch.MerchService.Register:output_type -> merch.AuthResponse
+	1,  // 67: merch.MerchService.Login:output_type -> merch.AuthResponse
+	23, // 68: merch.MerchService.BeginOIDCLogin:output_type -> merch.BeginOIDCLoginResponse
+	1,  // 69: merch.MerchService.LoginWithOIDC:output_type -> merch.AuthResponse
+	1,  // 70: merch.MerchService.RefreshToken:output_type -> merch.AuthResponse
+	4,  // 71: merch.MerchService.Logout:output_type -> merch.LogoutResponse
+	15, // 72: merch.MerchService.ListSessions:output_type -> merch.ListSessionsResponse
+	17, // 73: merch.MerchService.RevokeSession:output_type -> merch.RevokeSessionResponse
+	19, // 74: merch.MerchService.RevokeAllSessions:output_type -> merch.RevokeAllSessionsResponse
+	1,  // 75: merch.MerchService.ChangePassword:output_type -> merch.AuthResponse
+	1,  // 76: merch.MerchService.VerifyTwoFactor:output_type -> merch.AuthResponse
+	8,  // 77: merch.MerchService.EnrollTOTP:output_type -> merch.EnrollTOTPResponse
+	10, // 78: merch.MerchService.ConfirmTOTP:output_type -> merch.ConfirmTOTPResponse
+	12, // 79: merch.MerchService.DisableTOTP:output_type -> merch.DisableTOTPResponse
+	26, // 80: merch.MerchService.PurchaseMerch:output_type -> merch.PurchaseResponse
+	28, // 81: merch.MerchService.TransferCoins:output_type -> merch.TransferResponse
+	31, // 82: merch.MerchService.TransferCoinsBatch:output_type -> merch.TransferBatchResponse
+	40, // 83: merch.MerchService.GetInfo:output_type -> merch.GetInfoResponse
+	42, // 84: merch.MerchService.ListTransactions:output_type -> merch.ListTransactionsResponse
+	44, // 85: merch.MerchService.ListPurchases:output_type -> merch.ListPurchasesResponse
+	46, // 86: merch.MerchService.ExportHistory:output_type -> merch.HistoryRecord
+	51, // 87: merch.MerchService.GetStatement:output_type -> merch.GetStatementResponse
+	54, // 88: merch.MerchService.GetExpiringCoins:output_type -> merch.GetExpiringCoinsResponse
+	56, // 89: merch.MerchService.GrantCoins:output_type -> merch.GrantCoinsResponse
+	58, // 90: merch.MerchService.GrantCoinsBulk:output_type -> merch.GrantCoinsBulkResponse
+	60, // 91: merch.MerchService.ReverseTransaction:output_type -> merch.ReverseTransactionResponse
+	69, // 92: merch.MerchService.SetUserRoles:output_type -> merch.SetUserRolesResponse
+	81, // 93: merch.MerchService.QueryAuditLog:output_type -> merch.QueryAuditLogResponse
+	83, // 94: merch.MerchService.UnlockLogin:output_type -> merch.UnlockLoginResponse
+	71, // 95: merch.MerchService.CreateServiceAccount:output_type -> merch.CreateServiceAccountResponse
+	74, // 96: merch.MerchService.CreateAPIKey:output_type -> merch.CreateAPIKeyResponse
+	76, // 97: merch.MerchService.ListAPIKeys:output_type -> merch.ListAPIKeysResponse
+	78, // 98: merch.MerchService.RevokeAPIKey:output_type -> merch.RevokeAPIKeyResponse
+	63, // 99: merch.MerchService.RequestCoins:output_type -> merch.RequestCoinsResponse
+	65, // 100: merch.MerchService.ListCoinRequests:output_type -> merch.ListCoinRequestsResponse
+	67, // 101: merch.MerchService.ApproveCoinRequest:output_type -> merch.ResolveCoinRequestResponse
+	67, // 102: merch.MerchService.RejectCoinRequest:output_type -> merch.ResolveCoinRequestResponse
+	65, // [65:103] is the sub-list for method output_type
+	27, // [27:65] is the sub-list for method input_type
+	26, // [26:27] is the sub-list for extension type_name
+	25, // [25:26] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_merch_service_proto_init() }
@@ -733,19 +5742,25 @@ func file_merch_service_proto_init() {
 	if File_merch_service_proto != nil {
 		return
 	}
+	file_merch_service_proto_msgTypes[46].OneofWrappers = []any{
+		(*HistoryRecord_Purchase)(nil),
+		(*HistoryRecord_Transaction)(nil),
+	}
+	file_merch_service_proto_msgTypes[79].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_merch_service_proto_rawDesc), len(file_merch_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
-			NumExtensions: 0,
+			NumMessages:   86,
+			NumExtensions: 1,
 			NumServices:   1,
 		},
 		GoTypes:           file_merch_service_proto_goTypes,
 		DependencyIndexes: file_merch_service_proto_depIdxs,
 		MessageInfos:      file_merch_service_proto_msgTypes,
+		ExtensionInfos:    file_merch_service_proto_extTypes,
 	}.Build()
 	File_merch_service_proto = out.File
 	file_merch_service_proto_goTypes = nil
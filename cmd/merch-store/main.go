@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"merch-store-grpc/internal/app"
 	"merch-store-grpc/internal/config"
 	"merch-store-grpc/pkg/logger"
@@ -24,6 +26,12 @@ func main() {
 	log := logger.NewLogger(cfg.Env)
 	defer log.Sync()
 
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		code := runReconcile(ctx, cfg, log, os.Args[2:])
+		log.Sync()
+		os.Exit(code)
+	}
+
 	server := app.NewServer(cfg, log)
 
 	if err := server.Run(ctx); err != nil {
@@ -49,3 +57,26 @@ func main() {
 
 	log.Info("Application stopped gracefully")
 }
+
+// runReconcile обрабатывает подкоманду `merch-store reconcile [--dry-run]`.
+func runReconcile(ctx context.Context, cfg *config.Config, log logger.Logger, args []string) int {
+	fs := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "only report mismatches, do not repair the cache")
+	_ = fs.Parse(args)
+
+	report, err := app.RunReconcile(ctx, cfg, log, *dryRun)
+	if err != nil {
+		log.Errorw("Reconciliation failed",
+			"error", err,
+		)
+		return 1
+	}
+
+	fmt.Printf("scanned=%d mismatched=%d missing=%d repaired=%d skipped=%d dry_run=%t duration=%s\n",
+		report.Scanned, report.Mismatched, report.Missing, report.Repaired, report.Skipped, *dryRun, report.Duration)
+
+	if *dryRun && report.Mismatched > 0 {
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"merch-store-grpc/internal/config"
+	"merch-store-grpc/pkg/password"
+	"strings"
+)
+
+// newPasswordHasher собирает хэшер, который понимает и bcrypt, и Argon2id,
+// а новые пароли хэширует алгоритмом из auth.password_hashing.algorithm.
+func newPasswordHasher(cfg config.PasswordHashConfig) (password.PasswordHasher, error) {
+	current := strings.ToLower(cfg.Algorithm)
+	if current == "" {
+		current = password.AlgorithmArgon2id
+	}
+
+	return password.NewMultiHasher(current, map[string]password.PasswordHasher{
+		password.AlgorithmBcrypt: password.NewBCryptHasher(cfg.BcryptCost),
+		password.AlgorithmArgon2id: password.NewArgon2idHasher(password.Argon2Params{
+			Memory:      cfg.Argon2.Memory,
+			Iterations:  cfg.Argon2.Iterations,
+			Parallelism: cfg.Argon2.Parallelism,
+			SaltLength:  cfg.Argon2.SaltLength,
+			KeyLength:   cfg.Argon2.KeyLength,
+		}),
+	})
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"merch-store-grpc/internal/config"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/reconcile"
+	"merch-store-grpc/internal/storage/cache/redis"
+	"merch-store-grpc/internal/storage/db/postgres"
+	"merch-store-grpc/pkg/logger"
+	"strings"
+	"time"
+)
+
+// RunReconcile выполняет одну сверку балансов без запуска серверов
+// (подкоманда `merch-store reconcile`).
+func RunReconcile(ctx context.Context, cfg *config.Config, log logger.Logger, dryRun bool) (*reconcile.Report, error) {
+	pgPool, err := cfg.Storage.ConnectionToPostgres(log)
+	if err != nil {
+		return nil, fmt.Errorf("connect to postgres: %w", err)
+	}
+	defer pgPool.Close()
+
+	clientRedis, err := cfg.Storage.ConnectionToRedis(log)
+	if err != nil {
+		return nil, fmt.Errorf("connect to redis: %w", err)
+	}
+	defer clientRedis.Close()
+
+	txManager := postgres.NewTxManager(pgPool, log)
+	userRepo := postgres.NewUserRepository(txManager, log)
+	balanceRepo := postgres.NewBalanceRepository(txManager, log)
+	cacheRepo := redis.NewRedisCacheRepository(clientRedis, log)
+
+	reconciler := reconcile.NewReconciler(
+		userRepo,
+		balanceRepo,
+		cacheRepo,
+		currencyCodes(cfg.Currencies),
+		cfg.Reconcile.BatchSize,
+		time.Duration(cfg.Reconcile.ConfirmDelay)*time.Second,
+		log,
+	)
+
+	return reconciler.Run(ctx, dryRun)
+}
+
+// currencyCodes возвращает коды дополнительных валют из конфигурации так же,
+// как их нормализует сервис.
+func currencyCodes(currencies []config.CurrencyConfig) []string {
+	var codes []string
+	for _, c := range currencies {
+		code := strings.ToLower(strings.TrimSpace(c.Code))
+		if code == "" || code == models.CurrencyCoin {
+			continue
+		}
+		codes = append(codes, code)
+	}
+	return codes
+}
@@ -6,24 +6,33 @@ import (
 	"fmt"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/protobuf/encoding/protojson"
 	"merch-store-grpc/api/pb"
 	"merch-store-grpc/internal/config"
+	"merch-store-grpc/internal/controller/gateway"
 	mygprc "merch-store-grpc/internal/controller/grpc"
 	"merch-store-grpc/internal/controller/grpc/middleware"
+	"merch-store-grpc/internal/reconcile"
 	"merch-store-grpc/internal/service"
 	"merch-store-grpc/internal/storage/cache"
 	"merch-store-grpc/internal/storage/cache/redis"
 	"merch-store-grpc/internal/storage/db"
 	"merch-store-grpc/internal/storage/db/postgres"
+	"merch-store-grpc/internal/worker"
 	"merch-store-grpc/pkg/jwt"
 	"merch-store-grpc/pkg/logger"
+	"merch-store-grpc/pkg/oidc"
 	"merch-store-grpc/pkg/password"
+	"merch-store-grpc/pkg/tlsreload"
 	"net"
 	"net/http"
+	"strings"
+	"time"
 )
 
 type Server struct {
@@ -33,6 +42,11 @@ type Server struct {
 	grpcServer *grpc.Server
 	logger     logger.Logger
 	cache      cache.CacheRepository
+	service    service.MerchStoreService
+	reconciler *reconcile.Reconciler
+	tokens     jwt.TokenService
+	oidc       *oidc.Provider
+	grpcTLS    *tlsreload.Source
 }
 
 func NewServer(cfg *config.Config, log logger.Logger) *Server {
@@ -66,20 +80,113 @@ func NewServer(cfg *config.Config, log logger.Logger) *Server {
 	userRepo := postgres.NewUserRepository(txManager, log)
 	purchaseRepo := postgres.NewPurchaseRepository(txManager, log)
 	transactionRepo := postgres.NewTransactionRepository(txManager, log)
+	coinLotRepo := postgres.NewCoinLotRepository(txManager, log)
+	ledgerRepo := postgres.NewLedgerRepository(txManager, log)
+	grantRepo := postgres.NewGrantRepository(txManager, log)
+	coinRequestRepo := postgres.NewCoinRequestRepository(txManager, log)
+	historyRepo := postgres.NewHistoryRepository(txManager, log)
+	statementRepo := postgres.NewStatementRepository(txManager, log)
+	balanceRepo := postgres.NewBalanceRepository(txManager, log)
+	allowanceRepo := postgres.NewAllowanceRepository(txManager, log)
+	refreshTokenRepo := postgres.NewRefreshTokenRepository(txManager, log)
+	apiKeyRepo := postgres.NewAPIKeyRepository(txManager, log)
+	identityRepo := postgres.NewIdentityRepository(txManager, log)
+	totpRepo := postgres.NewTOTPRepository(txManager, log)
+	auditRepo := postgres.NewAuditRepository(txManager, log)
+
+	repo := db.NewRepository(
+		userRepo,
+		purchaseRepo,
+		transactionRepo,
+		coinLotRepo,
+		ledgerRepo,
+		grantRepo,
+		coinRequestRepo,
+		historyRepo,
+		statementRepo,
+		balanceRepo,
+		allowanceRepo,
+		refreshTokenRepo,
+		apiKeyRepo,
+		identityRepo,
+		totpRepo,
+		auditRepo,
+	)
 
-	repo := db.NewRepository(userRepo, purchaseRepo, transactionRepo)
+	tokenService, err := newTokenService(cfg.JWT)
+	if err != nil {
+		log.Fatalw("create token service",
+			"error", err)
+	}
+	passwordHasher, err := newPasswordHasher(cfg.Auth.PasswordHashing)
+	if err != nil {
+		log.Fatalw("create password hasher",
+			"error", err)
+	}
+	passwordPolicy, err := password.NewPolicy(
+		cfg.Auth.PasswordPolicy.MinLength,
+		cfg.Auth.PasswordPolicy.MinClasses,
+		cfg.Auth.PasswordPolicy.DenyListFile,
+	)
+	if err != nil {
+		log.Fatalw("create password policy",
+			"error", err)
+	}
 
-	tokenService := jwt.NewTokenService(cfg.JWT.SecretKey, cfg.JWT.TokenExpiry)
-	passwordHasher := password.NewBCryptHasher(0)
+	var oidcProvider *oidc.Provider
+	if cfg.OIDC.Enabled {
+		oidcProvider = oidc.NewProvider(oidc.Config{
+			Issuer:       cfg.OIDC.Issuer,
+			ClientID:     cfg.OIDC.ClientID,
+			ClientSecret: cfg.OIDC.ClientSecret,
+			RedirectURL:  cfg.OIDC.RedirectURL,
+			Scopes:       cfg.OIDC.Scopes,
+		})
+	}
 
 	cacheRepo := redis.NewRedisCacheRepository(clientRedis, log)
 
-	svc := service.NewMerchStoreService(repo, cacheRepo, txManager, tokenService, passwordHasher, 1000, log)
+	if err := validateClientCerts(cfg.ClientCerts); err != nil {
+		log.Fatalw("invalid client certificate mapping",
+			"error", err)
+	}
 
-	grpcSrv := grpc.NewServer(
-		grpc.UnaryInterceptor(middleware.JWTUnaryInterceptor(tokenService)),
+	svc := service.NewMerchStoreService(repo, cacheRepo, txManager, tokenService, passwordHasher, passwordPolicy, 1000, cfg.Auth, cfg.LoginLimit, cfg.TwoFactor, cfg.APIKeys, oidcProvider, cfg.OIDC, cfg.ClientCerts, cfg.Coins, cfg.Currencies, cfg.Allowance, log)
+
+	reconciler := reconcile.NewReconciler(
+		userRepo,
+		balanceRepo,
+		cacheRepo,
+		currencyCodes(cfg.Currencies),
+		cfg.Reconcile.BatchSize,
+		time.Duration(cfg.Reconcile.ConfirmDelay)*time.Second,
+		log,
 	)
 
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			middleware.ClientInfoUnaryInterceptor(),
+			middleware.JWTUnaryInterceptor(tokenService, cacheRepo, svc, svc, svc),
+		),
+		grpc.ChainStreamInterceptor(
+			middleware.ClientInfoStreamInterceptor(),
+			middleware.JWTStreamInterceptor(tokenService, cacheRepo, svc, svc, svc),
+		),
+	}
+
+	var grpcTLS *tlsreload.Source
+	if cfg.PublicServer.TLS.Enabled {
+		source, tlsConfig, err := newServerTLS(cfg.PublicServer.TLS)
+		if err != nil {
+			log.Fatalw("load gRPC TLS certificate",
+				"error", err)
+		}
+		grpcTLS = source
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	grpcSrv := grpc.NewServer(serverOpts...)
+
 	server := mygprc.NewServer(svc)
 
 	pb.RegisterMerchServiceServer(grpcSrv, server)
@@ -93,6 +200,11 @@ func NewServer(cfg *config.Config, log logger.Logger) *Server {
 		pgPool:     pgPool,
 		logger:     log,
 		cache:      cacheRepo,
+		service:    svc,
+		reconciler: reconciler,
+		tokens:     tokenService,
+		oidc:       oidcProvider,
+		grpcTLS:    grpcTLS,
 	}
 }
 
@@ -126,15 +238,108 @@ func (s *Server) Run(ctx context.Context) error {
 		return err
 	}
 
+	// Запуск фоновых задач
+	s.runWorkers()
+
 	return nil
 }
 
+func (s *Server) runWorkers() {
+	coins := s.config.Coins
+
+	expiration := worker.NewPeriodic(
+		"coin-expiration",
+		time.Duration(coins.ExpirationInterval)*time.Second,
+		func(ctx context.Context) error {
+			_, err := s.service.ExpireCoins(ctx, time.Now(), coins.ExpirationBatch)
+			return err
+		},
+		s.logger,
+	)
+
+	requestExpiration := worker.NewPeriodic(
+		"coin-request-expiration",
+		time.Duration(coins.RequestExpirationInterval)*time.Second,
+		func(ctx context.Context) error {
+			_, err := s.service.ExpireCoinRequests(ctx, time.Now())
+			return err
+		},
+		s.logger,
+	)
+
+	s.startWorker(expiration)
+	s.startWorker(requestExpiration)
+
+	if s.config.Statements.Enabled {
+		statements := worker.NewPeriodic(
+			"statement-generation",
+			time.Duration(s.config.Statements.Interval)*time.Second,
+			func(ctx context.Context) error {
+				_, err := s.service.GenerateStatements(ctx, time.Now(), s.config.Statements.BatchSize)
+				return err
+			},
+			s.logger,
+		)
+		s.startWorker(statements)
+	}
+
+	if s.config.Allowance.Enabled {
+		allowanceReset := worker.NewPeriodic(
+			"gift-allowance-reset",
+			time.Duration(s.config.Allowance.ResetInterval)*time.Second,
+			func(ctx context.Context) error {
+				_, err := s.service.ResetGiftAllowances(ctx, time.Now())
+				return err
+			},
+			s.logger,
+		)
+		s.startWorker(allowanceReset)
+	}
+
+	if s.config.Reconcile.Enabled {
+		reconciliation := worker.NewPeriodic(
+			"balance-reconciliation",
+			time.Duration(s.config.Reconcile.Interval)*time.Second,
+			func(ctx context.Context) error {
+				_, err := s.reconciler.Run(ctx, false)
+				return err
+			},
+			s.logger,
+		)
+		s.startWorker(reconciliation)
+	}
+}
+
+func (s *Server) startWorker(p *worker.Periodic) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		p.Run(ctx)
+	}()
+
+	s.closer.Add(func(shutdownCtx context.Context) error {
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-shutdownCtx.Done():
+			return shutdownCtx.Err()
+		}
+	})
+}
+
 func (s *Server) runGRPC(ctx context.Context) error {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", s.config.PublicServer.Port))
 	if err != nil {
 		return fmt.Errorf("listen: %w", err)
 	}
 
+	if s.grpcTLS != nil {
+		s.watchTLS(s.grpcTLS, s.config.PublicServer.TLS.ReloadInterval)
+	}
+
 	s.closer.Add(func(ctx context.Context) error {
 		s.logger.Infow("Shutting down gRPC server")
 		s.grpcServer.GracefulStop()
@@ -142,7 +347,7 @@ func (s *Server) runGRPC(ctx context.Context) error {
 	})
 
 	go func() {
-		s.logger.Infow("Starting gRPC server", "address", lis.Addr().String())
+		s.logger.Infow("Starting gRPC server", "address", lis.Addr().String(), "tls", s.grpcTLS != nil)
 		if err := s.grpcServer.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
 			s.logger.Fatalw("gRPC server error", "error", err)
 		}
@@ -150,6 +355,27 @@ func (s *Server) runGRPC(ctx context.Context) error {
 	return nil
 }
 
+// outgoingHeaderMatcher отдаёт retry-after и x-request-id как стандартные HTTP-заголовки,
+// остальные метаданные — с префиксом Grpc-Metadata-, как по умолчанию.
+func outgoingHeaderMatcher(key string) (string, bool) {
+	switch key {
+	case "retry-after":
+		return "Retry-After", true
+	case "x-request-id":
+		return "X-Request-Id", true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// incomingHeaderMatcher передаёт в gRPC заголовок X-Request-Id, чтобы запрос можно было
+// найти в журнале аудита по идентификатору, выданному балансировщиком или клиентом.
+func incomingHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, "X-Request-Id") {
+		return "x-request-id", true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
 func (s *Server) runGateway(ctx context.Context) error {
 	mux := runtime.NewServeMux(
 		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
@@ -160,9 +386,26 @@ func (s *Server) runGateway(ctx context.Context) error {
 				DiscardUnknown: true,
 			},
 		}),
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
 	)
 
-	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	creds := insecure.NewCredentials()
+	if s.config.PublicServer.TLS.Enabled {
+		upstream := s.config.Gateway.Upstream
+		source, err := tlsreload.New(upstream.CertFile, upstream.KeyFile, upstream.CAFile)
+		if err != nil {
+			return fmt.Errorf("failed to load gateway upstream TLS: %w", err)
+		}
+		s.watchTLS(source, s.config.PublicServer.TLS.ReloadInterval)
+
+		serverName := upstream.ServerName
+		if serverName == "" {
+			serverName = "localhost"
+		}
+		creds = credentials.NewTLS(source.ClientConfig(serverName))
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
 
 	endpoint := fmt.Sprintf("localhost:%d", s.config.PublicServer.Port)
 	conn, err := grpc.DialContext(ctx, endpoint, opts...)
@@ -179,17 +422,51 @@ func (s *Server) runGateway(ctx context.Context) error {
 		return fmt.Errorf("failed to register merch service handler: %w", err)
 	}
 
+	client := pb.NewMerchServiceClient(conn)
+
+	exportHandler := gateway.NewExportHandler(client)
+	if err := mux.HandlePath(http.MethodGet, "/api/history/export", exportHandler); err != nil {
+		return fmt.Errorf("failed to register history export handler: %w", err)
+	}
+
+	if s.oidc != nil {
+		oidcHandler, err := gateway.NewOIDCHandler(s.oidc, client, s.config.OIDC.StateSecret)
+		if err != nil {
+			return fmt.Errorf("failed to create oidc handler: %w", err)
+		}
+		if err := mux.HandlePath(http.MethodGet, "/api/auth/oidc/login", oidcHandler.Login); err != nil {
+			return fmt.Errorf("failed to register oidc login handler: %w", err)
+		}
+		if err := mux.HandlePath(http.MethodGet, "/api/auth/oidc/callback", oidcHandler.Callback); err != nil {
+			return fmt.Errorf("failed to register oidc callback handler: %w", err)
+		}
+	}
+
 	corsHandler := middleware.EnableCORS(mux)
 	loggingHandler := middleware.LoggingMiddleware(corsHandler)
 
 	fs := http.FileServer(http.Dir("./docs"))
 	http.Handle("/swagger/", http.StripPrefix("/swagger/", fs))
 
+	rootMux := http.NewServeMux()
+	rootMux.Handle("/metrics", promhttp.Handler())
+	rootMux.Handle("/.well-known/jwks.json", gateway.NewJWKSHandler(s.tokens))
+	rootMux.Handle("/", loggingHandler)
+
 	gwAddr := fmt.Sprintf(":%d", s.config.Gateway.Port)
 
 	srv := &http.Server{
 		Addr:    gwAddr,
-		Handler: loggingHandler,
+		Handler: rootMux,
+	}
+	if s.config.Gateway.TLS.Enabled {
+		source, tlsConfig, err := newGatewayTLS(s.config.Gateway.TLS)
+		if err != nil {
+			return fmt.Errorf("failed to load gateway TLS certificate: %w", err)
+		}
+		tlsConfig.NextProtos = []string{"h2", "http/1.1"}
+		srv.TLSConfig = tlsConfig
+		s.watchTLS(source, s.config.Gateway.TLS.ReloadInterval)
 	}
 	s.closer.Add(func(ctx context.Context) error {
 		s.logger.Infow("Shutting down HTTP gateway")
@@ -197,8 +474,15 @@ func (s *Server) runGateway(ctx context.Context) error {
 	})
 
 	go func() {
-		s.logger.Infow("Starting HTTP gateway", "address", gwAddr)
-		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.logger.Infow("Starting HTTP gateway", "address", gwAddr, "tls", srv.TLSConfig != nil)
+		var err error
+		if srv.TLSConfig != nil {
+			// Сертификат отдаёт TLSConfig, поэтому файлы здесь не указываются.
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			s.logger.Fatalw("HTTP gateway error", "error", err)
 		}
 	}()
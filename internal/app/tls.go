@@ -0,0 +1,98 @@
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"merch-store-grpc/internal/config"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/pkg/tlsreload"
+	"slices"
+	"strings"
+	"time"
+)
+
+const defaultTLSReloadInterval = 60
+
+// clientCertIdentityKinds — типы identity в client_certs: URI SAN, DNS SAN и Common Name.
+var clientCertIdentityKinds = []string{"uri", "dns", "cn"}
+
+// newServerTLS загружает сертификат слушателя. Перечитывание файлов запускает watchTLS.
+func newServerTLS(cfg config.TLSConfig) (*tlsreload.Source, *tls.Config, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, nil, fmt.Errorf("cert_file and key_file are required")
+	}
+	clientAuth, err := parseClientAuth(cfg.ClientAuth)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Без CA проверять сертификаты нечем: require молча превратился бы в вход без mTLS.
+	if clientAuth == tls.RequireAndVerifyClientCert && cfg.ClientCAFile == "" {
+		return nil, nil, fmt.Errorf("client_auth require needs client_ca_file")
+	}
+
+	source, err := tlsreload.New(cfg.CertFile, cfg.KeyFile, cfg.ClientCAFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return source, source.ServerConfig(clientAuth), nil
+}
+
+// newGatewayTLS — TLS слушателя gateway. Сертификаты клиентов gateway не запрашивает:
+// в gRPC он ходит со своим сертификатом и от имени пользователя из authorization,
+// поэтому identity клиента дальше не передалась бы. mTLS для сервисов — на public_server.tls.
+func newGatewayTLS(cfg config.TLSConfig) (*tlsreload.Source, *tls.Config, error) {
+	if cfg.ClientCAFile != "" || cfg.ClientAuth == "require" {
+		return nil, nil, fmt.Errorf("gateway does not accept client certificates, configure mTLS on public_server.tls")
+	}
+	return newServerTLS(cfg)
+}
+
+func parseClientAuth(mode string) (tls.ClientAuthType, error) {
+	switch mode {
+	case "", "request":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require":
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return 0, fmt.Errorf("unknown client_auth %q, use request or require", mode)
+	}
+}
+
+func validateClientCerts(certs []config.ClientCertConfig) error {
+	for _, c := range certs {
+		if c.Identity == "" || c.ServiceAccount == "" {
+			return fmt.Errorf("client_certs: identity and service_account are required")
+		}
+		kind, value, _ := strings.Cut(c.Identity, ":")
+		if value == "" || !slices.Contains(clientCertIdentityKinds, kind) {
+			return fmt.Errorf("client_certs: %s: identity must start with uri:, dns: or cn:", c.Identity)
+		}
+		for _, scope := range c.Scopes {
+			if !slices.Contains(models.Scopes, scope) {
+				return fmt.Errorf("client_certs: %s: unknown scope %s", c.Identity, scope)
+			}
+		}
+	}
+	return nil
+}
+
+// watchTLS перечитывает сертификаты source до остановки сервера.
+func (s *Server) watchTLS(source *tlsreload.Source, interval int) {
+	if interval <= 0 {
+		interval = defaultTLSReloadInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		source.Watch(ctx, time.Duration(interval)*time.Second, s.logger)
+	}()
+
+	s.closer.Add(func(context.Context) error {
+		cancel()
+		<-done
+		return nil
+	})
+}
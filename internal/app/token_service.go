@@ -0,0 +1,29 @@
+package app
+
+import (
+	"fmt"
+	"merch-store-grpc/internal/config"
+	"merch-store-grpc/pkg/jwt"
+	"strings"
+)
+
+// newTokenService выбирает реализацию по jwt.algorithm: HS256 с общим секретом
+// или асимметричные ключи из файлов с kid и ротацией.
+func newTokenService(cfg config.JWTConfig) (jwt.TokenService, error) {
+	switch strings.ToLower(cfg.Algorithm) {
+	case "", "hs256":
+		return jwt.NewTokenService(cfg.SecretKey, cfg.TokenExpiry), nil
+	case "asymmetric":
+		keys := make([]*jwt.Key, 0, len(cfg.Keys))
+		for _, k := range cfg.Keys {
+			key, err := jwt.LoadKey(k.ID, k.PrivateKeyFile, k.PublicKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("load jwt key: %w", err)
+			}
+			keys = append(keys, key)
+		}
+		return jwt.NewAsymmetricTokenService(keys, cfg.SigningKeyID, cfg.TokenExpiry)
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", cfg.Algorithm)
+	}
+}
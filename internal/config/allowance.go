@@ -0,0 +1,7 @@
+package config
+
+type AllowanceConfig struct {
+	Enabled       bool `mapstructure:"enabled"`
+	MonthlyAmount int  `mapstructure:"monthly_amount"`
+	ResetInterval int  `mapstructure:"reset_interval"`
+}
@@ -0,0 +1,7 @@
+package config
+
+// APIKeysConfig: срок действия ключа в днях, если он не указан при выпуске, и максимально допустимый.
+type APIKeysConfig struct {
+	DefaultExpiryDays int `mapstructure:"default_expiry_days"`
+	MaxExpiryDays     int `mapstructure:"max_expiry_days"`
+}
@@ -0,0 +1,35 @@
+package config
+
+// AuthConfig: AutoRegister включает старое поведение Authenticate (неизвестный username
+// регистрируется автоматически), RefreshTokenExpiry — срок жизни refresh-токена в секундах.
+type AuthConfig struct {
+	AutoRegister       bool                 `mapstructure:"auto_register"`
+	RefreshTokenExpiry int                  `mapstructure:"refresh_token_expiry"`
+	PasswordPolicy     PasswordPolicyConfig `mapstructure:"password_policy"`
+	PasswordHashing    PasswordHashConfig   `mapstructure:"password_hashing"`
+}
+
+// PasswordPolicyConfig: MinClasses — сколько классов символов (строчные, заглавные, цифры,
+// прочие) должно быть в пароле; DenyListFile дополняет встроенный список распространённых паролей.
+type PasswordPolicyConfig struct {
+	MinLength    int    `mapstructure:"min_length"`
+	MinClasses   int    `mapstructure:"min_classes"`
+	DenyListFile string `mapstructure:"deny_list_file"`
+}
+
+// PasswordHashConfig: Algorithm (argon2id или bcrypt) — чем хэшируются новые пароли.
+// Хэши другого алгоритма или с другими параметрами пересчитываются при успешном входе.
+type PasswordHashConfig struct {
+	Algorithm  string       `mapstructure:"algorithm"`
+	BcryptCost int          `mapstructure:"bcrypt_cost"`
+	Argon2     Argon2Config `mapstructure:"argon2"`
+}
+
+// Argon2Config: Memory — в KiB.
+type Argon2Config struct {
+	Memory      uint32 `mapstructure:"memory"`
+	Iterations  uint32 `mapstructure:"iterations"`
+	Parallelism uint8  `mapstructure:"parallelism"`
+	SaltLength  uint32 `mapstructure:"salt_length"`
+	KeyLength   uint32 `mapstructure:"key_length"`
+}
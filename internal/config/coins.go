@@ -0,0 +1,12 @@
+package config
+
+type CoinsConfig struct {
+	ExpiryMonths       int `mapstructure:"expiry_months"`
+	ExpirationInterval int `mapstructure:"expiration_interval"`
+	ExpirationBatch    int `mapstructure:"expiration_batch"`
+
+	RequestTTL                int `mapstructure:"request_ttl"`
+	RequestExpirationInterval int `mapstructure:"request_expiration_interval"`
+
+	AllowPartialReversal bool `mapstructure:"allow_partial_reversal"`
+}
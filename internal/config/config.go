@@ -12,7 +12,18 @@ type Config struct {
 	PublicServer PublicServerConfig `mapstructure:"public_server"`
 	Storage      StorageConfig      `mapstructure:"storage"`
 	JWT          JWTConfig          `mapstructure:"jwt"`
+	Auth         AuthConfig         `mapstructure:"auth"`
+	LoginLimit   LoginLimitConfig   `mapstructure:"login_limit"`
+	TwoFactor    TwoFactorConfig    `mapstructure:"two_factor"`
+	APIKeys      APIKeysConfig      `mapstructure:"api_keys"`
+	OIDC         OIDCConfig         `mapstructure:"oidc"`
+	ClientCerts  []ClientCertConfig `mapstructure:"client_certs"`
 	Gateway      GatewayConfig      `mapstructure:"gateway"`
+	Coins        CoinsConfig        `mapstructure:"coins"`
+	Reconcile    ReconcileConfig    `mapstructure:"reconcile"`
+	Statements   StatementsConfig   `mapstructure:"statements"`
+	Currencies   []CurrencyConfig   `mapstructure:"currencies"`
+	Allowance    AllowanceConfig    `mapstructure:"allowance"`
 }
 
 func LoadConfig(configPath, envPath string) (*Config, error) {
@@ -36,12 +47,16 @@ func LoadConfig(configPath, envPath string) (*Config, error) {
 	viper.BindEnv("storage.postgres.password", "DB_PASSWORD")
 	viper.BindEnv("storage.redis.host", "REDIS_HOST")
 	viper.BindEnv("jwt.secret_key", "JWT_SECRET_KEY")
+	viper.BindEnv("oidc.client_secret", "OIDC_CLIENT_SECRET")
 
 	var config Config
 	err = viper.Unmarshal(&config)
 	if err != nil {
 		return nil, fmt.Errorf("unable to decode into struct: %v", err)
 	}
+	if err := config.applyDefaults(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
 
 	return &config, nil
 }
@@ -0,0 +1,9 @@
+package config
+
+// CurrencyConfig описывает дополнительную валюту. Основная валюта coin задаётся
+// неявно: она всегда тратится в магазине, а её стартовый баланс — initialBalance сервиса.
+type CurrencyConfig struct {
+	Code           string `mapstructure:"code"`
+	Spendable      bool   `mapstructure:"spendable"`
+	InitialBalance int    `mapstructure:"initial_balance"`
+}
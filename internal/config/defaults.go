@@ -0,0 +1,44 @@
+package config
+
+import "fmt"
+
+// Значения по умолчанию для интервалов фоновых задач (в секундах) и размеров пачек.
+// Нулевой интервал уронил бы time.NewTicker, нулевая пачка зациклила бы обработку.
+const (
+	defaultExpirationInterval        = 3600
+	defaultExpirationBatch           = 500
+	defaultRequestExpirationInterval = 600
+	defaultReconcileInterval         = 300
+	defaultReconcileBatchSize        = 1000
+	defaultStatementsInterval        = 86400
+	defaultStatementsBatchSize       = 500
+	defaultAllowanceResetInterval    = 3600
+)
+
+// applyDefaults подставляет значения по умолчанию вместо незаданных (нулевых) интервалов
+// и размеров пачек и отклоняет отрицательные.
+func (c *Config) applyDefaults() error {
+	fields := []struct {
+		name  string
+		value *int
+		def   int
+	}{
+		{"coins.expiration_interval", &c.Coins.ExpirationInterval, defaultExpirationInterval},
+		{"coins.expiration_batch", &c.Coins.ExpirationBatch, defaultExpirationBatch},
+		{"coins.request_expiration_interval", &c.Coins.RequestExpirationInterval, defaultRequestExpirationInterval},
+		{"reconcile.interval", &c.Reconcile.Interval, defaultReconcileInterval},
+		{"reconcile.batch_size", &c.Reconcile.BatchSize, defaultReconcileBatchSize},
+		{"statements.interval", &c.Statements.Interval, defaultStatementsInterval},
+		{"statements.batch_size", &c.Statements.BatchSize, defaultStatementsBatchSize},
+		{"allowance.reset_interval", &c.Allowance.ResetInterval, defaultAllowanceResetInterval},
+	}
+	for _, f := range fields {
+		switch {
+		case *f.value < 0:
+			return fmt.Errorf("%s must be positive, got %d", f.name, *f.value)
+		case *f.value == 0:
+			*f.value = f.def
+		}
+	}
+	return nil
+}
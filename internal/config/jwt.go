@@ -3,4 +3,16 @@ package config
 type JWTConfig struct {
 	SecretKey   string `mapstructure:"secret_key"`
 	TokenExpiry int    `mapstructure:"token_expiry"`
+
+	// Algorithm: HS256 (по умолчанию, подпись secret_key) или asymmetric —
+	// подпись ключом signing_key_id из keys, алгоритм (RS256/EdDSA) определяется типом ключа.
+	Algorithm    string         `mapstructure:"algorithm"`
+	SigningKeyID string         `mapstructure:"signing_key_id"`
+	Keys         []JWTKeyConfig `mapstructure:"keys"`
+}
+
+type JWTKeyConfig struct {
+	ID             string `mapstructure:"id"`
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+	PublicKeyFile  string `mapstructure:"public_key_file"`
 }
@@ -0,0 +1,17 @@
+package config
+
+// LoginLimitConfig: окна и длительности блокировок — в секундах. После MaxFailures неверных
+// паролей за FailureWindow вход под username блокируется на LockoutBase, каждая следующая
+// блокировка в пределах LockoutReset вдвое дольше, но не больше LockoutMax.
+type LoginLimitConfig struct {
+	Enabled             bool `mapstructure:"enabled"`
+	IPMaxAttempts       int  `mapstructure:"ip_max_attempts"`
+	IPWindow            int  `mapstructure:"ip_window"`
+	UsernameMaxAttempts int  `mapstructure:"username_max_attempts"`
+	UsernameWindow      int  `mapstructure:"username_window"`
+	MaxFailures         int  `mapstructure:"max_failures"`
+	FailureWindow       int  `mapstructure:"failure_window"`
+	LockoutBase         int  `mapstructure:"lockout_base"`
+	LockoutMax          int  `mapstructure:"lockout_max"`
+	LockoutReset        int  `mapstructure:"lockout_reset"`
+}
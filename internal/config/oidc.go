@@ -0,0 +1,19 @@
+package config
+
+// OIDCConfig — вход через корпоративный SSO. UsernameClaim — утверждение id_token, из которого
+// берётся username нового пользователя (email — по части до @, только при email_verified).
+// LinkExistingUsers привязывает вход к уже существующему локальному пользователю, если подтверждённый
+// email равен {username}@{LinkEmailDomain}; пользователи с ролями сверх user не привязываются.
+// StateSecret подписывает cookie с состоянием входа; пустой — случайный ключ на время жизни процесса.
+type OIDCConfig struct {
+	Enabled           bool     `mapstructure:"enabled"`
+	Issuer            string   `mapstructure:"issuer"`
+	ClientID          string   `mapstructure:"client_id"`
+	ClientSecret      string   `mapstructure:"client_secret"`
+	RedirectURL       string   `mapstructure:"redirect_url"`
+	Scopes            []string `mapstructure:"scopes"`
+	UsernameClaim     string   `mapstructure:"username_claim"`
+	LinkExistingUsers bool     `mapstructure:"link_existing_users"`
+	LinkEmailDomain   string   `mapstructure:"link_email_domain"`
+	StateSecret       string   `mapstructure:"state_secret"`
+}
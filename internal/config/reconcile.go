@@ -0,0 +1,8 @@
+package config
+
+type ReconcileConfig struct {
+	Enabled      bool `mapstructure:"enabled"`
+	Interval     int  `mapstructure:"interval"`
+	BatchSize    int  `mapstructure:"batch_size"`
+	ConfirmDelay int  `mapstructure:"confirm_delay"`
+}
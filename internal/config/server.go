@@ -1,13 +1,16 @@
 package config
 
 type PublicServerConfig struct {
-	Enable          bool   `mapstructure:"enabled"`
-	Endpoint        string `mapstructure:"endpoint"`
-	Port            int    `mapstructure:"port"`
-	ShutdownTimeout int    `mapstructure:"shutdown_timeout"`
+	Enable          bool      `mapstructure:"enabled"`
+	Endpoint        string    `mapstructure:"endpoint"`
+	Port            int       `mapstructure:"port"`
+	ShutdownTimeout int       `mapstructure:"shutdown_timeout"`
+	TLS             TLSConfig `mapstructure:"tls"`
 }
 
 type GatewayConfig struct {
-	Port     int    `mapstructure:"port"`
-	Endpoint string `mapstructure:"endpoint"`
+	Port     int               `mapstructure:"port"`
+	Endpoint string            `mapstructure:"endpoint"`
+	TLS      TLSConfig         `mapstructure:"tls"`
+	Upstream UpstreamTLSConfig `mapstructure:"upstream_tls"`
 }
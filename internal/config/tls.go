@@ -0,0 +1,33 @@
+package config
+
+// TLSConfig — TLS слушателя. Файлы проверяются каждые ReloadInterval секунд, изменённый
+// сертификат подхватывается без перезапуска. ClientCAFile включает проверку сертификатов
+// клиентов (mTLS): ClientAuth request — сертификат необязателен, require — обязателен.
+// Для gateway ClientCAFile и require не поддерживаются.
+type TLSConfig struct {
+	Enabled        bool   `mapstructure:"enabled"`
+	CertFile       string `mapstructure:"cert_file"`
+	KeyFile        string `mapstructure:"key_file"`
+	ClientCAFile   string `mapstructure:"client_ca_file"`
+	ClientAuth     string `mapstructure:"client_auth"`
+	ReloadInterval int    `mapstructure:"reload_interval"`
+}
+
+// UpstreamTLSConfig — как gateway подключается к gRPC-серверу, когда на нём включён TLS:
+// CAFile проверяет сертификат сервера, CertFile/KeyFile — сертификат gateway для mTLS.
+type UpstreamTLSConfig struct {
+	CAFile     string `mapstructure:"ca_file"`
+	CertFile   string `mapstructure:"cert_file"`
+	KeyFile    string `mapstructure:"key_file"`
+	ServerName string `mapstructure:"server_name"`
+}
+
+// ClientCertConfig сопоставляет сертификат внутреннего клиента сервисному аккаунту.
+// Identity задаётся с типом: uri:<URI SAN> (например, uri:spiffe://…), dns:<DNS SAN> или
+// cn:<Common Name> — и сравнивается только с полем этого типа;
+// Scopes ограничивают доступ так же, как у API-ключа.
+type ClientCertConfig struct {
+	Identity       string   `mapstructure:"identity"`
+	ServiceAccount string   `mapstructure:"service_account"`
+	Scopes         []string `mapstructure:"scopes"`
+}
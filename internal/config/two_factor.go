@@ -0,0 +1,13 @@
+package config
+
+// TwoFactorConfig: Issuer — подпись в приложении-аутентификаторе; ChallengeTTL — сколько секунд
+// после проверки пароля ждать код; MaxAttempts — попыток ввести код на один challenge;
+// Skew — допустимое расхождение часов в 30-секундных шагах; RecoveryCodes — сколько кодов
+// восстановления выдавать.
+type TwoFactorConfig struct {
+	Issuer        string `mapstructure:"issuer"`
+	ChallengeTTL  int    `mapstructure:"challenge_ttl"`
+	MaxAttempts   int    `mapstructure:"max_attempts"`
+	Skew          int    `mapstructure:"skew"`
+	RecoveryCodes int    `mapstructure:"recovery_codes"`
+}
@@ -0,0 +1,193 @@
+package gateway
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"merch-store-grpc/api/pb"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+)
+
+var csvHeader = []string{"type", "id", "created_at", "merch_name", "price", "sender_id", "receiver_id", "amount", "currency"}
+
+type historyRow struct {
+	Type       string `json:"type"`
+	ID         int32  `json:"id"`
+	CreatedAt  string `json:"created_at"`
+	MerchName  string `json:"merch_name,omitempty"`
+	Price      int32  `json:"price,omitempty"`
+	SenderID   int32  `json:"sender_id,omitempty"`
+	ReceiverID int32  `json:"receiver_id,omitempty"`
+	Amount     int32  `json:"amount,omitempty"`
+	Currency   string `json:"currency"`
+}
+
+// NewExportHandler возвращает обработчик GET /api/history/export, который читает поток
+// ExportHistory и отдаёт его файлом CSV или NDJSON. Формат берётся из параметра
+// format, иначе из заголовка Accept; по умолчанию CSV.
+func NewExportHandler(client pb.MerchServiceClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		format, ok := negotiateFormat(r)
+		if !ok {
+			http.Error(w, "unsupported format, use csv or ndjson", http.StatusNotAcceptable)
+			return
+		}
+
+		ctx := outgoingContext(r)
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", auth)
+		}
+
+		query := r.URL.Query()
+		stream, err := client.ExportHistory(ctx, &pb.ExportHistoryRequest{
+			From: query.Get("from"),
+			To:   query.Get("to"),
+		})
+		if err != nil {
+			writeStatusError(w, err)
+			return
+		}
+
+		// Первое сообщение читаем до записи заголовков, чтобы ошибки
+		// авторизации и валидации вернулись правильным HTTP-статусом.
+		first, err := stream.Recv()
+		if err != nil && !errors.Is(err, io.EOF) {
+			writeStatusError(w, err)
+			return
+		}
+
+		var write func(historyRow) error
+		var flush func() error
+		switch format {
+		case formatNDJSON:
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Header().Set("Content-Disposition", `attachment; filename="history.ndjson"`)
+			enc := json.NewEncoder(w)
+			write = func(row historyRow) error { return enc.Encode(row) }
+			flush = func() error { return nil }
+		default:
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition", `attachment; filename="history.csv"`)
+			cw := csv.NewWriter(w)
+			if err := cw.Write(csvHeader); err != nil {
+				return
+			}
+			write = func(row historyRow) error { return cw.Write(row.csv()) }
+			flush = func() error { cw.Flush(); return cw.Error() }
+		}
+
+		flusher, _ := w.(http.Flusher)
+		count := 0
+		for record := first; record != nil; {
+			if err := write(toHistoryRow(record)); err != nil {
+				return
+			}
+			count++
+			if count%500 == 0 {
+				if err := flush(); err != nil {
+					return
+				}
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}
+
+			record, err = stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				// Заголовки уже отправлены: обрываем выгрузку, клиент получит неполный файл.
+				return
+			}
+		}
+
+		_ = flush()
+	}
+}
+
+func negotiateFormat(r *http.Request) (string, bool) {
+	if f := strings.ToLower(r.URL.Query().Get("format")); f != "" {
+		switch f {
+		case formatCSV:
+			return formatCSV, true
+		case formatNDJSON, "jsonl":
+			return formatNDJSON, true
+		}
+		return "", false
+	}
+
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case "text/csv":
+			return formatCSV, true
+		case "application/x-ndjson", "application/jsonl", "application/jsonlines":
+			return formatNDJSON, true
+		}
+	}
+	return formatCSV, true
+}
+
+func toHistoryRow(record *pb.HistoryRecord) historyRow {
+	if p := record.GetPurchase(); p != nil {
+		return historyRow{
+			Type:      "purchase",
+			ID:        p.Id,
+			CreatedAt: p.PurchaseDate,
+			MerchName: p.MerchName,
+			Price:     p.Price,
+			Currency:  p.Currency,
+		}
+	}
+	t := record.GetTransaction()
+	return historyRow{
+		Type:       "transaction",
+		ID:         t.GetId(),
+		CreatedAt:  t.GetCreatedAt(),
+		SenderID:   t.GetSenderId(),
+		ReceiverID: t.GetReceiverId(),
+		Amount:     t.GetAmount(),
+		Currency:   t.GetCurrency(),
+	}
+}
+
+func (r historyRow) csv() []string {
+	itoa := func(v int32) string {
+		if v == 0 {
+			return ""
+		}
+		return strconv.Itoa(int(v))
+	}
+	return []string{
+		r.Type,
+		strconv.Itoa(int(r.ID)),
+		r.CreatedAt,
+		r.MerchName,
+		itoa(r.Price),
+		itoa(r.SenderID),
+		itoa(r.ReceiverID),
+		itoa(r.Amount),
+		r.Currency,
+	}
+}
+
+func writeStatusError(w http.ResponseWriter, err error) {
+	st, _ := status.FromError(err)
+	http.Error(w, st.Message(), runtime.HTTPStatusFromCode(st.Code()))
+}
@@ -0,0 +1,25 @@
+package gateway
+
+import (
+	"encoding/json"
+	"merch-store-grpc/pkg/jwt"
+	"net/http"
+)
+
+// NewJWKSHandler отдаёт публичные ключи проверки токенов (/.well-known/jwks.json),
+// чтобы другие сервисы могли проверять токены merch-store без общего секрета.
+func NewJWKSHandler(tokenService jwt.TokenService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		if err := json.NewEncoder(w).Encode(tokenService.JWKS()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package gateway
+
+import (
+	"context"
+	"google.golang.org/grpc/metadata"
+	"net"
+	"net/http"
+)
+
+// outgoingContext передаёт в gRPC адрес клиента, User-Agent и X-Request-Id так же, как
+// это делает runtime.ServeMux для сгенерированных маршрутов: обработчики, написанные
+// вручную, иначе приходят на сервер без данных клиента.
+func outgoingContext(r *http.Request) context.Context {
+	var pairs []string
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		forwarded := host
+		if prior := r.Header.Get("X-Forwarded-For"); prior != "" {
+			forwarded = prior + ", " + host
+		}
+		pairs = append(pairs, "x-forwarded-for", forwarded)
+	}
+	if ua := r.Header.Get("User-Agent"); ua != "" {
+		pairs = append(pairs, "grpcgateway-user-agent", ua)
+	}
+	if id := r.Header.Get("X-Request-Id"); id != "" {
+		pairs = append(pairs, "x-request-id", id)
+	}
+	return metadata.AppendToOutgoingContext(r.Context(), pairs...)
+}
@@ -0,0 +1,186 @@
+package gateway
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"google.golang.org/protobuf/encoding/protojson"
+	"merch-store-grpc/api/pb"
+	"merch-store-grpc/pkg/oidc"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	oidcCookieName = "merch_oidc"
+	oidcCookiePath = "/api/auth/oidc"
+	oidcStateTTL   = 10 * time.Minute
+)
+
+// oidcState — то, что нужно сохранить между редиректом к провайдеру и возвратом на callback.
+// Сам nonce остаётся на сервере, в cookie лежит только непрозрачный LoginHandle.
+type oidcState struct {
+	State        string `json:"s"`
+	LoginHandle  string `json:"h"`
+	CodeVerifier string `json:"v"`
+	ExpiresAt    int64  `json:"e"`
+}
+
+// OIDCHandler — вход через корпоративный SSO (authorization code flow с PKCE).
+// Состояние входа хранится в подписанной cookie, поэтому gateway остаётся без состояния.
+type OIDCHandler struct {
+	provider *oidc.Provider
+	client   pb.MerchServiceClient
+	secret   []byte
+}
+
+// NewOIDCHandler создаёт обработчики. Пустой stateSecret заменяется случайным ключом:
+// так работает один экземпляр gateway, для нескольких ключ нужно задать явно.
+func NewOIDCHandler(provider *oidc.Provider, client pb.MerchServiceClient, stateSecret string) (*OIDCHandler, error) {
+	secret := []byte(stateSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+	}
+	return &OIDCHandler{provider: provider, client: client, secret: secret}, nil
+}
+
+// Login — GET /api/auth/oidc/login: перенаправляет браузер к провайдеру.
+func (h *OIDCHandler) Login(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	login, err := h.client.BeginOIDCLogin(outgoingContext(r), &pb.BeginOIDCLoginRequest{})
+	if err != nil {
+		writeStatusError(w, err)
+		return
+	}
+
+	st := oidcState{
+		LoginHandle: login.LoginHandle,
+		ExpiresAt:   time.Now().Add(oidcStateTTL).Unix(),
+	}
+	if st.State, err = oidc.RandomString(24); err == nil {
+		st.CodeVerifier, err = oidc.RandomString(32)
+	}
+	if err != nil {
+		http.Error(w, "failed to start login", http.StatusInternalServerError)
+		return
+	}
+
+	authURL, err := h.provider.AuthCodeURL(r.Context(), st.State, login.Nonce, st.CodeVerifier)
+	if err != nil {
+		http.Error(w, "identity provider is unavailable", http.StatusBadGateway)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookieName,
+		Value:    h.encodeState(st),
+		Path:     oidcCookiePath,
+		MaxAge:   int(oidcStateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback — GET /api/auth/oidc/callback: обменивает code на id_token и выдаёт
+// обычные токены merch-store в том же формате, что /api/auth/login.
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	query := r.URL.Query()
+	if e := query.Get("error"); e != "" {
+		http.Error(w, "identity provider returned error: "+e, http.StatusUnauthorized)
+		return
+	}
+
+	cookie, err := r.Cookie(oidcCookieName)
+	if err != nil {
+		http.Error(w, "login session not found, start again at "+oidcCookiePath+"/login", http.StatusBadRequest)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookieName,
+		Path:     oidcCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	st, ok := h.decodeState(cookie.Value)
+	if !ok || time.Now().Unix() > st.ExpiresAt {
+		http.Error(w, "login session expired, start again at "+oidcCookiePath+"/login", http.StatusBadRequest)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(st.State)) != 1 {
+		http.Error(w, "state mismatch", http.StatusBadRequest)
+		return
+	}
+	code := query.Get("code")
+	if code == "" {
+		http.Error(w, "code is required", http.StatusBadRequest)
+		return
+	}
+
+	idToken, err := h.provider.Exchange(r.Context(), code, st.CodeVerifier)
+	if err != nil {
+		http.Error(w, "authorization code exchange failed", http.StatusBadGateway)
+		return
+	}
+
+	resp, err := h.client.LoginWithOIDC(outgoingContext(r), &pb.LoginWithOIDCRequest{
+		IdToken:     idToken,
+		LoginHandle: st.LoginHandle,
+	})
+	if err != nil {
+		writeStatusError(w, err)
+		return
+	}
+
+	body, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(body)
+}
+
+func (h *OIDCHandler) encodeState(st oidcState) string {
+	payload, _ := json.Marshal(st)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(h.sign(encoded))
+}
+
+func (h *OIDCHandler) decodeState(value string) (oidcState, bool) {
+	var st oidcState
+	encoded, sig, ok := strings.Cut(value, ".")
+	if !ok {
+		return st, false
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, h.sign(encoded)) {
+		return st, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return st, false
+	}
+	return st, json.Unmarshal(payload, &st) == nil
+}
+
+func (h *OIDCHandler) sign(data string) []byte {
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"merch-store-grpc/api/pb"
+)
+
+func (s *Server) GrantCoins(ctx context.Context, req *pb.GrantCoinsRequest) (*pb.GrantCoinsResponse, error) {
+	adminIDVal := ctx.Value("userID")
+	if adminIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	adminID, ok := adminIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	grant, err := s.svc.GrantCoins(ctx, adminID, req.Username, int(req.Amount), req.Reason)
+	if err != nil {
+		return nil, statusFromError(err, "grant failed")
+	}
+
+	return &pb.GrantCoinsResponse{
+		GrantId: int32(grant.ID),
+		Message: fmt.Sprintf("granted %d coins to %s", grant.Amount, req.Username),
+	}, nil
+}
+
+func (s *Server) GrantCoinsBulk(ctx context.Context, req *pb.GrantCoinsBulkRequest) (*pb.GrantCoinsBulkResponse, error) {
+	adminIDVal := ctx.Value("userID")
+	if adminIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	adminID, ok := adminIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	batch, err := s.svc.GrantCoinsBulk(ctx, adminID, req.Csv)
+	if err != nil {
+		return nil, statusFromError(err, "bulk grant failed")
+	}
+
+	return &pb.GrantCoinsBulkResponse{
+		BatchId:     int32(batch.ID),
+		Rows:        int32(batch.RowCount),
+		TotalAmount: int32(batch.TotalAmount),
+	}, nil
+}
+
+func (s *Server) ReverseTransaction(ctx context.Context, req *pb.ReverseTransactionRequest) (*pb.ReverseTransactionResponse, error) {
+	adminIDVal := ctx.Value("userID")
+	if adminIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	adminID, ok := adminIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	reversal, err := s.svc.ReverseTransaction(ctx, adminID, int(req.TransactionId), req.Reason)
+	if err != nil {
+		return nil, statusFromError(err, "reversal failed")
+	}
+
+	return &pb.ReverseTransactionResponse{
+		ReversalId:      int32(reversal.ReversalID),
+		RequestedAmount: int32(reversal.RequestedAmount),
+		ReversedAmount:  int32(reversal.ReversedAmount),
+		Partial:         reversal.ReversedAmount < reversal.RequestedAmount,
+	}, nil
+}
+
+func (s *Server) SetUserRoles(ctx context.Context, req *pb.SetUserRolesRequest) (*pb.SetUserRolesResponse, error) {
+	adminIDVal := ctx.Value("userID")
+	if adminIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	adminID, ok := adminIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	user, err := s.svc.SetUserRoles(ctx, adminID, req.Username, req.Roles)
+	if err != nil {
+		return nil, statusFromError(err, "set roles failed")
+	}
+
+	return &pb.SetUserRolesResponse{
+		Username: user.Username,
+		Roles:    user.Roles,
+	}, nil
+}
+
+func (s *Server) UnlockLogin(ctx context.Context, req *pb.UnlockLoginRequest) (*pb.UnlockLoginResponse, error) {
+	adminIDVal := ctx.Value("userID")
+	if adminIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	adminID, ok := adminIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	if err := s.svc.UnlockLogin(ctx, adminID, req.Username); err != nil {
+		return nil, statusFromError(err, "unlock failed")
+	}
+
+	return &pb.UnlockLoginResponse{Success: true}, nil
+}
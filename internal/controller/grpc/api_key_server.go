@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"merch-store-grpc/api/pb"
+	"merch-store-grpc/internal/models"
+	"time"
+)
+
+func (s *Server) CreateServiceAccount(ctx context.Context, req *pb.CreateServiceAccountRequest) (*pb.CreateServiceAccountResponse, error) {
+	adminIDVal := ctx.Value("userID")
+	if adminIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	adminID, ok := adminIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	user, err := s.svc.CreateServiceAccount(ctx, adminID, req.Username, req.Roles)
+	if err != nil {
+		return nil, statusFromError(err, "create service account failed")
+	}
+
+	return &pb.CreateServiceAccountResponse{
+		UserId:   int32(user.ID),
+		Username: user.Username,
+		Roles:    user.Roles,
+	}, nil
+}
+
+func (s *Server) CreateAPIKey(ctx context.Context, req *pb.CreateAPIKeyRequest) (*pb.CreateAPIKeyResponse, error) {
+	adminIDVal := ctx.Value("userID")
+	if adminIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	adminID, ok := adminIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	key, plain, err := s.svc.CreateAPIKey(ctx, adminID, req.Username, req.Name, req.Scopes, int(req.ExpiresInDays))
+	if err != nil {
+		return nil, statusFromError(err, "create api key failed")
+	}
+
+	return &pb.CreateAPIKeyResponse{
+		ApiKey: toPBAPIKey(key),
+		Key:    plain,
+	}, nil
+}
+
+func (s *Server) ListAPIKeys(ctx context.Context, req *pb.ListAPIKeysRequest) (*pb.ListAPIKeysResponse, error) {
+	keys, err := s.svc.ListAPIKeys(ctx, req.Username)
+	if err != nil {
+		return nil, statusFromError(err, "list api keys failed")
+	}
+
+	pbKeys := make([]*pb.APIKey, 0, len(keys))
+	for _, key := range keys {
+		pbKeys = append(pbKeys, toPBAPIKey(key))
+	}
+	return &pb.ListAPIKeysResponse{ApiKeys: pbKeys}, nil
+}
+
+func (s *Server) RevokeAPIKey(ctx context.Context, req *pb.RevokeAPIKeyRequest) (*pb.RevokeAPIKeyResponse, error) {
+	adminIDVal := ctx.Value("userID")
+	if adminIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	adminID, ok := adminIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	if err := s.svc.RevokeAPIKey(ctx, adminID, int(req.KeyId)); err != nil {
+		return nil, statusFromError(err, "revoke api key failed")
+	}
+	return &pb.RevokeAPIKeyResponse{Success: true}, nil
+}
+
+func toPBAPIKey(key *models.APIKey) *pb.APIKey {
+	pbKey := &pb.APIKey{
+		Id:        int32(key.ID),
+		Username:  key.Username,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		CreatedAt: key.CreatedAt.Format(time.RFC3339),
+		ExpiresAt: key.ExpiresAt.Format(time.RFC3339),
+	}
+	if key.LastUsedAt != nil {
+		pbKey.LastUsedAt = key.LastUsedAt.Format(time.RFC3339)
+	}
+	if key.RevokedAt != nil {
+		pbKey.RevokedAt = key.RevokedAt.Format(time.RFC3339)
+	}
+	return pbKey
+}
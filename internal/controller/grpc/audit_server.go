@@ -0,0 +1,65 @@
+package grpc
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"merch-store-grpc/api/pb"
+	"merch-store-grpc/internal/models"
+	"time"
+)
+
+func (s *Server) QueryAuditLog(ctx context.Context, req *pb.QueryAuditLogRequest) (*pb.QueryAuditLogResponse, error) {
+	from, to, err := parsePeriod(req.From, req.To)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter := &models.AuditFilter{
+		EventType: req.EventType,
+		ActorID:   int(req.ActorId),
+		TargetID:  int(req.TargetId),
+		UserID:    int(req.UserId),
+		IP:        req.Ip,
+		RequestID: req.RequestId,
+		From:      from,
+		To:        to,
+		Limit:     int(req.Limit),
+	}
+
+	events, next, err := s.svc.QueryAuditLog(ctx, filter, req.Cursor)
+	if err != nil {
+		return nil, statusFromError(err, "failed to query audit log")
+	}
+
+	pbEvents := make([]*pb.AuditEvent, 0, len(events))
+	for _, e := range events {
+		pbEvents = append(pbEvents, toPBAuditEvent(e))
+	}
+	return &pb.QueryAuditLogResponse{Events: pbEvents, NextCursor: next}, nil
+}
+
+func toPBAuditEvent(e *models.AuditEvent) *pb.AuditEvent {
+	pbEvent := &pb.AuditEvent{
+		Id:        int32(e.ID),
+		EventType: e.EventType,
+		ActorId:   int32(e.ActorID),
+		TargetId:  int32(e.TargetID),
+		Ip:        e.IP,
+		UserAgent: e.UserAgent,
+		RequestId: e.RequestID,
+		Currency:  e.Currency,
+		Amount:    int32(e.Amount),
+		Details:   e.Details,
+		CreatedAt: e.CreatedAt.Format(time.RFC3339),
+	}
+	if e.BalanceBefore != nil {
+		before := int32(*e.BalanceBefore)
+		pbEvent.BalanceBefore = &before
+	}
+	if e.BalanceAfter != nil {
+		after := int32(*e.BalanceAfter)
+		pbEvent.BalanceAfter = &after
+	}
+	return pbEvent
+}
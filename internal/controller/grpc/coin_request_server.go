@@ -0,0 +1,112 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"merch-store-grpc/api/pb"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/service"
+	"time"
+)
+
+func (s *Server) RequestCoins(ctx context.Context, req *pb.RequestCoinsRequest) (*pb.RequestCoinsResponse, error) {
+	userIDVal := ctx.Value("userID")
+	if userIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	coinReq, err := s.svc.RequestCoins(ctx, userID, int(req.FromUser), int(req.Amount), req.Note)
+	if err != nil {
+		return nil, statusFromError(err, "request coins failed")
+	}
+
+	return &pb.RequestCoinsResponse{Request: toPBCoinRequest(coinReq)}, nil
+}
+
+func (s *Server) ListCoinRequests(ctx context.Context, req *pb.ListCoinRequestsRequest) (*pb.ListCoinRequestsResponse, error) {
+	userIDVal := ctx.Value("userID")
+	if userIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	requests, err := s.svc.ListCoinRequests(ctx, userID, req.Direction, req.Status)
+	if err != nil {
+		return nil, statusFromError(err, "failed to list coin requests")
+	}
+
+	pbRequests := make([]*pb.CoinRequest, 0, len(requests))
+	for _, r := range requests {
+		pbRequests = append(pbRequests, toPBCoinRequest(r))
+	}
+
+	return &pb.ListCoinRequestsResponse{Requests: pbRequests}, nil
+}
+
+func (s *Server) ApproveCoinRequest(ctx context.Context, req *pb.ResolveCoinRequestRequest) (*pb.ResolveCoinRequestResponse, error) {
+	userIDVal := ctx.Value("userID")
+	if userIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	coinReq, err := s.svc.ApproveCoinRequest(ctx, userID, int(req.RequestId))
+	if err != nil {
+		if errors.Is(err, service.ErrCoinRequestNotFound) || errors.Is(err, service.ErrCoinRequestNotPending) {
+			return nil, statusFromError(err, "approve failed")
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "approve failed: %v", err)
+	}
+
+	return &pb.ResolveCoinRequestResponse{Request: toPBCoinRequest(coinReq)}, nil
+}
+
+func (s *Server) RejectCoinRequest(ctx context.Context, req *pb.ResolveCoinRequestRequest) (*pb.ResolveCoinRequestResponse, error) {
+	userIDVal := ctx.Value("userID")
+	if userIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	coinReq, err := s.svc.RejectCoinRequest(ctx, userID, int(req.RequestId))
+	if err != nil {
+		return nil, statusFromError(err, "reject failed")
+	}
+
+	return &pb.ResolveCoinRequestResponse{Request: toPBCoinRequest(coinReq)}, nil
+}
+
+func toPBCoinRequest(r *models.CoinRequest) *pb.CoinRequest {
+	pbReq := &pb.CoinRequest{
+		Id:          int32(r.ID),
+		RequesterId: int32(r.RequesterID),
+		PayerId:     int32(r.PayerID),
+		Amount:      int32(r.Amount),
+		Note:        r.Note,
+		Status:      r.Status,
+		CreatedAt:   r.CreatedAt.Format(time.RFC3339),
+		ExpiresAt:   r.ExpiresAt.Format(time.RFC3339),
+	}
+	if r.TransactionID != nil {
+		pbReq.TransactionId = int32(*r.TransactionID)
+	}
+	if r.ResolvedAt != nil {
+		pbReq.ResolvedAt = r.ResolvedAt.Format(time.RFC3339)
+	}
+	return pbReq
+}
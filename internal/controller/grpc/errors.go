@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"math"
+	"merch-store-grpc/internal/service"
+	"strconv"
+)
+
+// statusFromError сопоставляет ошибки сервисного слоя с gRPC-кодами.
+func statusFromError(err error, msg string) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, service.ErrPermissionDenied):
+		code = codes.PermissionDenied
+	case errors.Is(err, service.ErrInvalidCredentials):
+		code = codes.Unauthenticated
+	case errors.Is(err, service.ErrTooManyAttempts):
+		code = codes.ResourceExhausted
+	case errors.Is(err, service.ErrUserAlreadyExists):
+		code = codes.AlreadyExists
+	case errors.Is(err, service.ErrInvalidArgument):
+		code = codes.InvalidArgument
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrCoinRequestNotFound),
+		errors.Is(err, service.ErrTransactionNotFound), errors.Is(err, service.ErrAPIKeyNotFound),
+		errors.Is(err, service.ErrSessionNotFound):
+		code = codes.NotFound
+	case errors.Is(err, service.ErrCoinRequestNotPending), errors.Is(err, service.ErrTransactionNotReversible),
+		errors.Is(err, service.ErrCurrencyNotSpendable), errors.Is(err, service.ErrOIDCDisabled),
+		errors.Is(err, service.ErrTwoFactorAlreadyEnabled), errors.Is(err, service.ErrTwoFactorNotEnabled):
+		code = codes.FailedPrecondition
+	case errors.Is(err, service.ErrTransactionAlreadyReversed):
+		code = codes.AlreadyExists
+	}
+	return status.Errorf(code, "%s: %v", msg, err)
+}
+
+// setRetryAfter передаёт клиенту в заголовке retry-after, через сколько секунд можно
+// повторить вход. Gateway отдаёт его как HTTP-заголовок Retry-After.
+func setRetryAfter(ctx context.Context, err error) {
+	var throttled *service.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		return
+	}
+	seconds := int(math.Ceil(throttled.RetryAfter.Seconds()))
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(seconds)))
+}
@@ -0,0 +1,178 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"merch-store-grpc/api/pb"
+	"merch-store-grpc/internal/models"
+	"time"
+)
+
+func (s *Server) ListTransactions(ctx context.Context, req *pb.ListTransactionsRequest) (*pb.ListTransactionsResponse, error) {
+	userIDVal := ctx.Value("userID")
+	if userIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	from, to, err := parsePeriod(req.From, req.To)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter := &models.TransactionFilter{
+		UserID:         userID,
+		Direction:      req.Direction,
+		CounterpartyID: int(req.CounterpartyId),
+		Currency:       req.Currency,
+		MinAmount:      int(req.MinAmount),
+		MaxAmount:      int(req.MaxAmount),
+		From:           from,
+		To:             to,
+		Limit:          int(req.Limit),
+	}
+
+	transactions, next, err := s.svc.ListTransactions(ctx, filter, req.Cursor)
+	if err != nil {
+		return nil, statusFromError(err, "failed to list transactions")
+	}
+
+	pbTransactions := make([]*pb.Transaction, 0, len(transactions))
+	for _, t := range transactions {
+		pbTransactions = append(pbTransactions, &pb.Transaction{
+			Id:         int32(t.ID),
+			SenderId:   int32(t.SenderID),
+			ReceiverId: int32(t.ReceiverID),
+			Amount:     int32(t.Amount),
+			CreatedAt:  t.CreatedAt.Format(time.RFC3339),
+			ReversalOf: reversalOfToPB(t.ReversalOf),
+			Currency:   t.Currency,
+		})
+	}
+
+	return &pb.ListTransactionsResponse{Transactions: pbTransactions, NextCursor: next}, nil
+}
+
+func (s *Server) ListPurchases(ctx context.Context, req *pb.ListPurchasesRequest) (*pb.ListPurchasesResponse, error) {
+	userIDVal := ctx.Value("userID")
+	if userIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	from, to, err := parsePeriod(req.From, req.To)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter := &models.PurchaseFilter{
+		UserID:    userID,
+		MerchName: req.MerchName,
+		MinPrice:  int(req.MinPrice),
+		MaxPrice:  int(req.MaxPrice),
+		From:      from,
+		To:        to,
+		Limit:     int(req.Limit),
+	}
+
+	purchases, next, err := s.svc.ListPurchases(ctx, filter, req.Cursor)
+	if err != nil {
+		return nil, statusFromError(err, "failed to list purchases")
+	}
+
+	pbPurchases := make([]*pb.Purchase, 0, len(purchases))
+	for _, p := range purchases {
+		pbPurchases = append(pbPurchases, &pb.Purchase{
+			Id:           int32(p.ID),
+			MerchName:    p.MerchName,
+			Price:        int32(p.Price),
+			PurchaseDate: p.CreatedAt.Format(time.RFC3339),
+			Currency:     p.Currency,
+		})
+	}
+
+	return &pb.ListPurchasesResponse{Purchases: pbPurchases, NextCursor: next}, nil
+}
+
+func (s *Server) ExportHistory(req *pb.ExportHistoryRequest, stream pb.MerchService_ExportHistoryServer) error {
+	ctx := stream.Context()
+	userIDVal := ctx.Value("userID")
+	if userIDVal == nil {
+		return status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		return status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	from, to, err := parsePeriod(req.From, req.To)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.svc.ExportHistory(ctx, userID, from, to, func(r *models.HistoryRecord) error {
+		return stream.Send(toPBHistoryRecord(r))
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return statusFromError(err, "failed to export history")
+	}
+	return nil
+}
+
+func toPBHistoryRecord(r *models.HistoryRecord) *pb.HistoryRecord {
+	if r.Kind == models.HistoryKindPurchase {
+		return &pb.HistoryRecord{Record: &pb.HistoryRecord_Purchase{Purchase: &pb.Purchase{
+			Id:           int32(r.ID),
+			MerchName:    r.MerchName,
+			Price:        int32(r.Price),
+			PurchaseDate: r.CreatedAt.Format(time.RFC3339),
+			Currency:     r.Currency,
+		}}}
+	}
+	return &pb.HistoryRecord{Record: &pb.HistoryRecord_Transaction{Transaction: &pb.Transaction{
+		Id:         int32(r.ID),
+		SenderId:   int32(r.SenderID),
+		ReceiverId: int32(r.ReceiverID),
+		Amount:     int32(r.Amount),
+		CreatedAt:  r.CreatedAt.Format(time.RFC3339),
+		Currency:   r.Currency,
+	}}}
+}
+
+func reversalOfToPB(id *int) int32 {
+	if id == nil {
+		return 0
+	}
+	return int32(*id)
+}
+
+// parsePeriod разбирает необязательные границы периода в формате RFC3339.
+func parsePeriod(fromStr, toStr string) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if fromStr != "" {
+		t, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid from: %v", err)
+		}
+		from = &t
+	}
+	if toStr != "" {
+		t, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid to: %v", err)
+		}
+		to = &t
+	}
+	return from, to, nil
+}
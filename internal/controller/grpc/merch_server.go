@@ -2,10 +2,14 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"math"
 	"merch-store-grpc/api/pb"
+	"merch-store-grpc/internal/models"
 	"merch-store-grpc/internal/service"
+	"merch-store-grpc/pkg/jwt"
 	"time"
 )
 
@@ -21,11 +25,102 @@ func NewServer(svc service.MerchStoreService) *Server {
 }
 
 func (s *Server) Authenticate(ctx context.Context, req *pb.AuthRequest) (*pb.AuthResponse, error) {
-	token, err := s.svc.Authenticate(ctx, req.Username, req.Password)
+	tokens, err := s.svc.Authenticate(ctx, req.Username, req.Password, service.ClientInfoFromContext(ctx).IP)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "authentication: %v", err)
+		setRetryAfter(ctx, err)
+		return nil, statusFromError(err, "authentication")
+	}
+	return toPBAuthResponse(tokens), nil
+}
+
+func (s *Server) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.AuthResponse, error) {
+	tokens, err := s.svc.Register(ctx, req.Username, req.Password)
+	if err != nil {
+		setRetryAfter(ctx, err)
+		return nil, statusFromError(err, "registration failed")
+	}
+	return toPBAuthResponse(tokens), nil
+}
+
+func (s *Server) Login(ctx context.Context, req *pb.LoginRequest) (*pb.AuthResponse, error) {
+	tokens, err := s.svc.Login(ctx, req.Username, req.Password, service.ClientInfoFromContext(ctx).IP)
+	if err != nil {
+		setRetryAfter(ctx, err)
+		return nil, statusFromError(err, "login failed")
+	}
+	return toPBAuthResponse(tokens), nil
+}
+
+func (s *Server) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.AuthResponse, error) {
+	tokens, err := s.svc.RefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, statusFromError(err, "refresh failed")
+	}
+	return toPBAuthResponse(tokens), nil
+}
+
+func (s *Server) BeginOIDCLogin(ctx context.Context, req *pb.BeginOIDCLoginRequest) (*pb.BeginOIDCLoginResponse, error) {
+	login, err := s.svc.BeginOIDCLogin(ctx)
+	if err != nil {
+		return nil, statusFromError(err, "oidc login failed")
+	}
+	return &pb.BeginOIDCLoginResponse{
+		LoginHandle: login.Handle,
+		Nonce:       login.Nonce,
+		ExpiresAt:   login.ExpiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (s *Server) LoginWithOIDC(ctx context.Context, req *pb.LoginWithOIDCRequest) (*pb.AuthResponse, error) {
+	tokens, err := s.svc.LoginWithOIDC(ctx, req.IdToken, req.LoginHandle)
+	if err != nil {
+		return nil, statusFromError(err, "oidc login failed")
+	}
+	return toPBAuthResponse(tokens), nil
+}
+
+func (s *Server) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest) (*pb.AuthResponse, error) {
+	userIDVal := ctx.Value("userID")
+	if userIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	tokens, err := s.svc.ChangePassword(ctx, userID, req.OldPassword, req.NewPassword)
+	if err != nil {
+		setRetryAfter(ctx, err)
+		return nil, statusFromError(err, "change password failed")
+	}
+	return toPBAuthResponse(tokens), nil
+}
+
+func (s *Server) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	claims, ok := ctx.Value("tokenClaims").(*jwt.Claims)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+
+	if err := s.svc.Logout(ctx, claims); err != nil {
+		return nil, statusFromError(err, "logout failed")
+	}
+	return &pb.LogoutResponse{Success: true}, nil
+}
+
+func toPBAuthResponse(tokens *models.AuthTokens) *pb.AuthResponse {
+	if tokens.ChallengeToken != "" {
+		return &pb.AuthResponse{
+			ChallengeToken:     tokens.ChallengeToken,
+			ChallengeExpiresAt: tokens.ChallengeExpiresAt.Format(time.RFC3339),
+		}
+	}
+	return &pb.AuthResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.ExpiresAt.Format(time.RFC3339),
 	}
-	return &pb.AuthResponse{Token: token}, nil
 }
 
 func (s *Server) PurchaseMerch(ctx context.Context, req *pb.PurchaseRequest) (*pb.PurchaseResponse, error) {
@@ -38,7 +133,10 @@ func (s *Server) PurchaseMerch(ctx context.Context, req *pb.PurchaseRequest) (*p
 		return nil, status.Error(codes.Internal, "invalid userID in context")
 	}
 
-	if err := s.svc.PurchaseMerch(ctx, userID, req.MerchName); err != nil {
+	if err := s.svc.PurchaseMerch(ctx, userID, req.MerchName, req.Currency); err != nil {
+		if errors.Is(err, service.ErrInvalidArgument) {
+			return nil, statusFromError(err, "purchase failed")
+		}
 		return nil, status.Errorf(codes.FailedPrecondition, "purchase failed: %v", err)
 	}
 
@@ -62,7 +160,10 @@ func (s *Server) TransferCoins(ctx context.Context, req *pb.TransferRequest) (*p
 		return nil, status.Error(codes.InvalidArgument, "cannot transfer to yourself")
 	}
 
-	if err := s.svc.TransferCoins(ctx, senderID, int(req.ToUser), int(req.Amount)); err != nil {
+	if err := s.svc.TransferCoins(ctx, senderID, int(req.ToUser), req.Currency, int(req.Amount)); err != nil {
+		if errors.Is(err, service.ErrInvalidArgument) {
+			return nil, statusFromError(err, "transfer failed")
+		}
 		return nil, status.Errorf(codes.FailedPrecondition, "transfer failed: %v", err)
 	}
 
@@ -72,6 +173,48 @@ func (s *Server) TransferCoins(ctx context.Context, req *pb.TransferRequest) (*p
 	}, nil
 }
 
+func (s *Server) TransferCoinsBatch(ctx context.Context, req *pb.TransferBatchRequest) (*pb.TransferBatchResponse, error) {
+	senderIDVal := ctx.Value("userID")
+	if senderIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	senderID, ok := senderIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	transfers := make([]models.TransferItem, 0, len(req.Transfers))
+	var total64 int64
+	for _, t := range req.Transfers {
+		if t.Amount > 0 {
+			total64 += int64(t.Amount)
+		}
+		transfers = append(transfers, models.TransferItem{
+			ToUser: int(t.ToUser),
+			Amount: int(t.Amount),
+		})
+	}
+
+	// Сумма возвращается в int32, поэтому большие пакеты отклоняются до перевода.
+	if total64 > math.MaxInt32 {
+		return nil, status.Errorf(codes.InvalidArgument, "total amount exceeds %d", math.MaxInt32)
+	}
+
+	total, err := s.svc.TransferCoinsBatch(ctx, senderID, req.Currency, transfers)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidArgument) || errors.Is(err, service.ErrUserNotFound) {
+			return nil, statusFromError(err, "transfer failed")
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "transfer failed: %v", err)
+	}
+
+	return &pb.TransferBatchResponse{
+		Success:     true,
+		Message:     "transfer successful",
+		TotalAmount: int32(total),
+	}, nil
+}
+
 func (s *Server) GetInfo(ctx context.Context, req *pb.GetInfoRequest) (*pb.GetInfoResponse, error) {
 	userIDVal := ctx.Value("userID")
 	if userIDVal == nil {
@@ -94,6 +237,7 @@ func (s *Server) GetInfo(ctx context.Context, req *pb.GetInfoRequest) (*pb.GetIn
 			MerchName:    p.MerchName,
 			Price:        int32(p.Price),
 			PurchaseDate: p.CreatedAt.Format(time.RFC3339),
+			Currency:     p.Currency,
 		})
 	}
 
@@ -105,16 +249,87 @@ func (s *Server) GetInfo(ctx context.Context, req *pb.GetInfoRequest) (*pb.GetIn
 			ReceiverId: int32(t.ReceiverID),
 			Amount:     int32(t.Amount),
 			CreatedAt:  t.CreatedAt.Format(time.RFC3339),
+			ReversalOf: reversalOfToPB(t.ReversalOf),
+			Currency:   t.Currency,
+		})
+	}
+
+	pbInventory := make([]*pb.InventoryItem, 0, len(info.Inventory))
+	for _, item := range info.Inventory {
+		pbInventory = append(pbInventory, &pb.InventoryItem{
+			MerchName: item.MerchName,
+			Quantity:  int32(item.Quantity),
+		})
+	}
+
+	pbBalances := make([]*pb.CurrencyBalance, 0, len(info.Balances))
+	for _, b := range info.Balances {
+		pbBalances = append(pbBalances, &pb.CurrencyBalance{
+			Currency:  b.Currency,
+			Balance:   int32(b.Balance),
+			Spendable: b.Spendable,
 		})
 	}
 
 	userInfo := &pb.UserInfo{
 		UserId:       int32(info.UserID),
 		Username:     info.Username,
+		Roles:        info.Roles,
 		Balance:      int32(info.Balance),
 		Purchases:    pbPurchases,
 		Transactions: pbTransactions,
+		Inventory:    pbInventory,
+		Balances:     pbBalances,
+		CoinHistory: &pb.CoinHistory{
+			Received: toPBCoinMovements(info.Received),
+			Sent:     toPBCoinMovements(info.Sent),
+		},
+	}
+	if info.Allowance != nil {
+		userInfo.GiftAllowance = int32(info.Allowance.Balance)
+		userInfo.GiftAllowanceResetsAt = info.Allowance.Period.AddDate(0, 1, 0).Format(time.RFC3339)
 	}
 
 	return &pb.GetInfoResponse{Info: userInfo}, nil
 }
+
+func toPBCoinMovements(movements []*models.CoinMovement) []*pb.CoinMovement {
+	pbMovements := make([]*pb.CoinMovement, 0, len(movements))
+	for _, m := range movements {
+		pbMovements = append(pbMovements, &pb.CoinMovement{
+			Username: m.Username,
+			Amount:   int32(m.Amount),
+			Currency: m.Currency,
+		})
+	}
+	return pbMovements
+}
+
+func (s *Server) GetExpiringCoins(ctx context.Context, req *pb.GetExpiringCoinsRequest) (*pb.GetExpiringCoinsResponse, error) {
+	userIDVal := ctx.Value("userID")
+	if userIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	lots, err := s.svc.GetExpiringCoins(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get expiring coins: %v", err)
+	}
+
+	var total int32
+	pbLots := make([]*pb.ExpiringCoins, 0, len(lots))
+	for _, l := range lots {
+		total += int32(l.Remaining)
+		pbLots = append(pbLots, &pb.ExpiringCoins{
+			Amount:     int32(l.Remaining),
+			ReceivedAt: l.ReceivedAt.Format(time.RFC3339),
+			ExpiresAt:  l.ExpiresAt.Format(time.RFC3339),
+		})
+	}
+
+	return &pb.GetExpiringCoinsResponse{Total: total, Lots: pbLots}, nil
+}
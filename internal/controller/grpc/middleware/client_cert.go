@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// peerCertIdentities возвращает identity сертификата клиента, проверенного по client_ca_file,
+// с явным типом: uri:<URI SAN>, dns:<DNS SAN>, cn:<Common Name>. Тип не даёт CN
+// «spiffe://…» совпасть с сопоставлением для URI SAN. Без mTLS или без сертификата — пусто.
+func peerCertIdentities(ctx context.Context) []string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	var identities []string
+	for _, uri := range cert.URIs {
+		identities = append(identities, "uri:"+uri.String())
+	}
+	for _, name := range cert.DNSNames {
+		identities = append(identities, "dns:"+name)
+	}
+	if cert.Subject.CommonName != "" {
+		identities = append(identities, "cn:"+cert.Subject.CommonName)
+	}
+	return identities
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/service"
+	"net"
+	"regexp"
+	"strings"
+)
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// ClientInfoUnaryInterceptor кладёт в контекст адрес и User-Agent клиента и идентификатор
+// запроса. Их используют лимиты входа, сессии и журнал аудита, поэтому интерцептор стоит
+// до проверки токена. Идентификатор берётся из x-request-id или создаётся и возвращается
+// клиенту в том же заголовке.
+func ClientInfoUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		requestID := requestID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs("x-request-id", requestID))
+		return handler(withClientInfo(ctx, requestID), req)
+	}
+}
+
+// ClientInfoStreamInterceptor — то же для потоковых методов (например, ExportHistory).
+func ClientInfoStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		requestID := requestID(ctx)
+		_ = ss.SetHeader(metadata.Pairs("x-request-id", requestID))
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: withClientInfo(ctx, requestID)})
+	}
+}
+
+func withClientInfo(ctx context.Context, requestID string) context.Context {
+	return service.WithClientInfo(ctx, &models.ClientInfo{
+		IP:        clientIP(ctx),
+		UserAgent: userAgent(ctx),
+		RequestID: requestID,
+	})
+}
+
+// requestID возвращает x-request-id клиента, если он похож на идентификатор, иначе новый.
+func requestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if ids := md.Get("x-request-id"); len(ids) > 0 && requestIDPattern.MatchString(ids[0]) {
+		return ids[0]
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// clientIP возвращает адрес клиента. Запросы через gateway приходят с loopback,
+// тогда берётся последний адрес из x-forwarded-for — его добавил сам gateway,
+// остальные пришли от клиента и подделываются.
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsLoopback() {
+		return host
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	forwarded := md.Get("x-forwarded-for")
+	if len(forwarded) == 0 {
+		return host
+	}
+	parts := strings.Split(forwarded[len(forwarded)-1], ",")
+	if last := strings.TrimSpace(parts[len(parts)-1]); last != "" {
+		return last
+	}
+	return host
+}
+
+// userAgent — User-Agent браузера или HTTP-клиента, если запрос пришёл через gateway,
+// иначе — gRPC-клиента.
+func userAgent(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if ua := md.Get("grpcgateway-user-agent"); len(ua) > 0 && ua[0] != "" {
+		return ua[0]
+	}
+	if ua := md.Get("user-agent"); len(ua) > 0 {
+		return ua[0]
+	}
+	return ""
+}
@@ -2,41 +2,215 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"merch-store-grpc/api/pb"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/service"
 	"merch-store-grpc/pkg/jwt"
+	"slices"
 	"strings"
 )
 
-func JWTUnaryInterceptor(tokenService jwt.TokenService) grpc.UnaryServerInterceptor {
+// accessPolicies — правила доступа из опции access_policy методов MerchService по полному имени метода.
+var accessPolicies = loadAccessPolicies()
+
+func loadAccessPolicies() map[string]*pb.AccessPolicy {
+	service := pb.File_merch_service_proto.Services().ByName("MerchService")
+	methods := service.Methods()
+
+	policies := make(map[string]*pb.AccessPolicy, methods.Len())
+	for i := 0; i < methods.Len(); i++ {
+		method := methods.Get(i)
+		policy, _ := proto.GetExtension(method.Options(), pb.E_AccessPolicy).(*pb.AccessPolicy)
+		if policy == nil {
+			policy = &pb.AccessPolicy{}
+		}
+		policies["/"+string(service.FullName())+"/"+string(method.Name())] = policy
+	}
+	return policies
+}
+
+// RevocationList — список отозванных токенов по jti и отозванных сессий по sid.
+type RevocationList interface {
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+	IsSessionRevoked(ctx context.Context, sessionID string) (bool, error)
+}
+
+// APIKeyAuthenticator проверяет API-ключ сервисного аккаунта.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*models.Principal, error)
+}
+
+// SessionTracker отмечает активность сессии по запросу с access-токеном.
+type SessionTracker interface {
+	TouchSession(ctx context.Context, sessionID string)
+}
+
+// ClientCertAuthenticator сопоставляет сертификат клиента (mTLS) сервисному аккаунту.
+type ClientCertAuthenticator interface {
+	AuthenticateClientCert(ctx context.Context, identities []string) (*models.Principal, error)
+}
+
+func JWTUnaryInterceptor(tokenService jwt.TokenService, revocations RevocationList, apiKeys APIKeyAuthenticator, clientCerts ClientCertAuthenticator, sessions SessionTracker) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		if strings.Contains(info.FullMethod, "Authenticate") {
+		policy := accessPolicies[info.FullMethod]
+		if policy.GetPublic() {
 			return handler(ctx, req)
 		}
 
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Error(codes.Unauthenticated, "missing metadata")
+		newCtx, err := authenticate(ctx, tokenService, revocations, apiKeys, clientCerts, sessions)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorize(newCtx, policy); err != nil {
+			return nil, err
+		}
+		return handler(newCtx, req)
+	}
+}
+
+func JWTStreamInterceptor(tokenService jwt.TokenService, revocations RevocationList, apiKeys APIKeyAuthenticator, clientCerts ClientCertAuthenticator, sessions SessionTracker) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		policy := accessPolicies[info.FullMethod]
+		if policy.GetPublic() {
+			return handler(srv, ss)
 		}
 
-		authHeaders := md.Get("authorization")
-		if len(authHeaders) == 0 {
+		newCtx, err := authenticate(ss.Context(), tokenService, revocations, apiKeys, clientCerts, sessions)
+		if err != nil {
+			return err
+		}
+		if err := authorize(newCtx, policy); err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: newCtx})
+	}
+}
+
+// authenticate проверяет JWT (со списками отзыва токенов и сессий) или API-ключ из заголовка
+// authorization, а без заголовка — сертификат клиента, и кладёт в контекст principal и userID,
+// а для JWT ещё и claims; активность сессии JWT отмечается через sessions. Заголовок важнее сертификата: gateway подключается со своим сертификатом,
+// но действует от имени пользователя.
+func authenticate(ctx context.Context, tokenService jwt.TokenService, revocations RevocationList, apiKeys APIKeyAuthenticator, clientCerts ClientCertAuthenticator, sessions SessionTracker) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	authHeaders := md.Get("authorization")
+	if len(authHeaders) == 0 {
+		identities := peerCertIdentities(ctx)
+		if len(identities) == 0 {
 			return nil, status.Error(codes.Unauthenticated, "authorization token not supplied")
 		}
+		principal, err := clientCerts.AuthenticateClientCert(ctx, identities)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidCredentials) {
+				return nil, status.Error(codes.Unauthenticated, "client certificate is not mapped to a service account")
+			}
+			return nil, status.Errorf(codes.Unavailable, "check client certificate: %v", err)
+		}
+		ctx = context.WithValue(ctx, "principal", principal)
+		return context.WithValue(ctx, "userID", principal.UserID), nil
+	}
+
+	tokenString := authHeaders[0]
+	if strings.HasPrefix(tokenString, "Bearer ") {
+		tokenString = strings.TrimPrefix(tokenString, "Bearer ")
+	}
+	if key, ok := strings.CutPrefix(tokenString, "ApiKey "); ok {
+		tokenString = key
+	}
 
-		tokenString := authHeaders[0]
-		if strings.HasPrefix(tokenString, "Bearer ") {
-			tokenString = strings.TrimPrefix(tokenString, "Bearer ")
+	if strings.HasPrefix(tokenString, service.APIKeyPrefix) {
+		principal, err := apiKeys.AuthenticateAPIKey(ctx, tokenString)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidCredentials) {
+				return nil, status.Error(codes.Unauthenticated, "invalid api key")
+			}
+			return nil, status.Errorf(codes.Unavailable, "check api key: %v", err)
 		}
+		ctx = context.WithValue(ctx, "principal", principal)
+		return context.WithValue(ctx, "userID", principal.UserID), nil
+	}
+
+	claims, err := tokenService.ParseJWTToken(tokenString)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
 
-		userID, err := tokenService.ParseJWTToken(tokenString)
+	if claims.ID != "" {
+		revoked, err := revocations.IsTokenRevoked(ctx, claims.ID)
 		if err != nil {
-			return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+			return nil, status.Errorf(codes.Unavailable, "check token revocation: %v", err)
 		}
+		if revoked {
+			return nil, status.Error(codes.Unauthenticated, "token has been revoked")
+		}
+	}
+	if claims.SessionID != "" {
+		revoked, err := revocations.IsSessionRevoked(ctx, claims.SessionID)
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "check session revocation: %v", err)
+		}
+		if revoked {
+			return nil, status.Error(codes.Unauthenticated, "session has been revoked")
+		}
+		sessions.TouchSession(ctx, claims.SessionID)
+	}
 
-		newCtx := context.WithValue(ctx, "userID", userID)
-		return handler(newCtx, req)
+	roles := claims.Roles
+	if len(roles) == 0 {
+		// Токены, выданные до появления ролей.
+		roles = []string{models.RoleUser}
 	}
+
+	ctx = context.WithValue(ctx, "tokenClaims", claims)
+	ctx = context.WithValue(ctx, "principal", &models.Principal{UserID: claims.UserID, Roles: roles})
+	return context.WithValue(ctx, "userID", claims.UserID), nil
+}
+
+// authorize проверяет роли и, для API-ключа и сертификата клиента, область доступа по
+// правилу метода. Методам вне MerchService (например, reflection) правило не задано, им
+// достаточно действующего токена; по API-ключу и сертификату их вызвать нельзя.
+func authorize(ctx context.Context, policy *pb.AccessPolicy) error {
+	principal, _ := ctx.Value("principal").(*models.Principal)
+
+	if principal.IsScoped() {
+		credential := "api key"
+		if !principal.IsAPIKey() {
+			credential = "client certificate"
+		}
+		scope := policy.GetScope()
+		if scope == "" {
+			return status.Errorf(codes.PermissionDenied, "method is not available with a %s", credential)
+		}
+		if !slices.Contains(principal.Scopes, scope) {
+			return status.Errorf(codes.PermissionDenied, "%s lacks scope %s", credential, scope)
+		}
+	}
+
+	if len(policy.GetRoles()) == 0 {
+		return nil
+	}
+	for _, role := range policy.Roles {
+		if slices.Contains(principal.Roles, role) {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "requires one of roles: %s", strings.Join(policy.Roles, ", "))
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
 }
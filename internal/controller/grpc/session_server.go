@@ -0,0 +1,96 @@
+package grpc
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"merch-store-grpc/api/pb"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/pkg/jwt"
+	"time"
+)
+
+func (s *Server) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	userIDVal := ctx.Value("userID")
+	if userIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	sessions, err := s.svc.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, statusFromError(err, "list sessions failed")
+	}
+
+	current := currentSessionID(ctx)
+	pbSessions := make([]*pb.Session, 0, len(sessions))
+	for _, session := range sessions {
+		pbSessions = append(pbSessions, toPBSession(session, current))
+	}
+	return &pb.ListSessionsResponse{Sessions: pbSessions}, nil
+}
+
+func (s *Server) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	userIDVal := ctx.Value("userID")
+	if userIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	if req.SessionId == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id is required")
+	}
+
+	if err := s.svc.RevokeSession(ctx, userID, req.SessionId); err != nil {
+		return nil, statusFromError(err, "revoke session failed")
+	}
+	return &pb.RevokeSessionResponse{Success: true}, nil
+}
+
+func (s *Server) RevokeAllSessions(ctx context.Context, req *pb.RevokeAllSessionsRequest) (*pb.RevokeAllSessionsResponse, error) {
+	userIDVal := ctx.Value("userID")
+	if userIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	var keep string
+	if req.KeepCurrent {
+		keep = currentSessionID(ctx)
+	}
+
+	revoked, err := s.svc.RevokeAllSessions(ctx, userID, keep)
+	if err != nil {
+		return nil, statusFromError(err, "revoke sessions failed")
+	}
+	return &pb.RevokeAllSessionsResponse{Revoked: int32(revoked)}, nil
+}
+
+// currentSessionID — sid токена текущего запроса; у API-ключа и сертификата клиента сессии нет.
+func currentSessionID(ctx context.Context) string {
+	if claims, ok := ctx.Value("tokenClaims").(*jwt.Claims); ok {
+		return claims.SessionID
+	}
+	return ""
+}
+
+func toPBSession(session *models.Session, currentID string) *pb.Session {
+	return &pb.Session{
+		Id:         session.ID,
+		UserAgent:  session.UserAgent,
+		Ip:         session.IP,
+		CreatedAt:  session.CreatedAt.Format(time.RFC3339),
+		LastSeenAt: session.LastSeenAt.Format(time.RFC3339),
+		ExpiresAt:  session.ExpiresAt.Format(time.RFC3339),
+		Current:    currentID != "" && session.ID == currentID,
+	}
+}
@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"merch-store-grpc/api/pb"
+	"merch-store-grpc/internal/service"
+	"sort"
+	"time"
+)
+
+func (s *Server) GetStatement(ctx context.Context, req *pb.GetStatementRequest) (*pb.GetStatementResponse, error) {
+	userIDVal := ctx.Value("userID")
+	if userIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	period, err := service.ParseStatementPeriod(req.Period)
+	if err != nil {
+		return nil, statusFromError(err, "invalid period")
+	}
+
+	st, err := s.svc.GetStatement(ctx, userID, period)
+	if err != nil {
+		return nil, statusFromError(err, "failed to get statement")
+	}
+
+	movements := make([]*pb.StatementMovement, 0, len(st.Movements))
+	for _, m := range st.Movements {
+		movements = append(movements, &pb.StatementMovement{
+			Category:       m.Category,
+			Id:             int32(m.ID),
+			CreatedAt:      m.CreatedAt.Format(time.RFC3339),
+			Amount:         int32(m.Amount),
+			Description:    m.Description,
+			CounterpartyId: int32(m.CounterpartyID),
+		})
+	}
+
+	categories := make([]string, 0, len(st.Totals))
+	for c := range st.Totals {
+		categories = append(categories, c)
+	}
+	sort.Strings(categories)
+
+	totals := make([]*pb.CategoryTotal, 0, len(categories))
+	for _, c := range categories {
+		totals = append(totals, &pb.CategoryTotal{Category: c, Amount: int32(st.Totals[c])})
+	}
+
+	return &pb.GetStatementResponse{Statement: &pb.Statement{
+		Period:         st.Period.Format("2006-01"),
+		OpeningBalance: int32(st.OpeningBalance),
+		ClosingBalance: int32(st.ClosingBalance),
+		Movements:      movements,
+		Totals:         totals,
+		GeneratedAt:    st.GeneratedAt.Format(time.RFC3339),
+	}}, nil
+}
@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"merch-store-grpc/api/pb"
+)
+
+func (s *Server) VerifyTwoFactor(ctx context.Context, req *pb.VerifyTwoFactorRequest) (*pb.AuthResponse, error) {
+	tokens, err := s.svc.VerifyTwoFactor(ctx, req.ChallengeToken, req.Code)
+	if err != nil {
+		return nil, statusFromError(err, "two-factor verification failed")
+	}
+	return toPBAuthResponse(tokens), nil
+}
+
+func (s *Server) EnrollTOTP(ctx context.Context, req *pb.EnrollTOTPRequest) (*pb.EnrollTOTPResponse, error) {
+	userIDVal := ctx.Value("userID")
+	if userIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	enrollment, err := s.svc.EnrollTOTP(ctx, userID)
+	if err != nil {
+		return nil, statusFromError(err, "totp enrollment failed")
+	}
+
+	return &pb.EnrollTOTPResponse{
+		Secret:          enrollment.Secret,
+		ProvisioningUri: enrollment.ProvisioningURI,
+	}, nil
+}
+
+func (s *Server) ConfirmTOTP(ctx context.Context, req *pb.ConfirmTOTPRequest) (*pb.ConfirmTOTPResponse, error) {
+	userIDVal := ctx.Value("userID")
+	if userIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	recoveryCodes, err := s.svc.ConfirmTOTP(ctx, userID, req.Code)
+	if err != nil {
+		return nil, statusFromError(err, "totp confirmation failed")
+	}
+
+	return &pb.ConfirmTOTPResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (s *Server) DisableTOTP(ctx context.Context, req *pb.DisableTOTPRequest) (*pb.DisableTOTPResponse, error) {
+	userIDVal := ctx.Value("userID")
+	if userIDVal == nil {
+		return nil, status.Error(codes.Unauthenticated, "user not authenticated")
+	}
+	userID, ok := userIDVal.(int)
+	if !ok {
+		return nil, status.Error(codes.Internal, "invalid userID in context")
+	}
+
+	if err := s.svc.DisableTOTP(ctx, userID, req.Password, req.Code); err != nil {
+		return nil, statusFromError(err, "disable totp failed")
+	}
+
+	return &pb.DisableTOTPResponse{Success: true}, nil
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Области доступа API-ключей. Какой области требует метод, задано в proto (access_policy.scope);
+// методы без области по API-ключу недоступны.
+const (
+	ScopeCoinsTransfer       = "coins:transfer"
+	ScopeCoinsGrant          = "coins:grant"
+	ScopeTransactionsReverse = "transactions:reverse"
+	ScopeMerchPurchase       = "merch:purchase"
+	ScopeHistoryRead         = "history:read"
+	ScopeUsersManage         = "users:manage"
+	ScopeAuditRead           = "audit:read"
+)
+
+// Scopes — все известные области доступа.
+var Scopes = []string{
+	ScopeCoinsTransfer,
+	ScopeCoinsGrant,
+	ScopeTransactionsReverse,
+	ScopeMerchPurchase,
+	ScopeHistoryRead,
+	ScopeUsersManage,
+	ScopeAuditRead,
+}
+
+type APIKey struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	Username   string     `json:"username"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  int        `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Principal — тот, от чьего имени выполняется запрос: пользователь по JWT
+// или сервисный аккаунт по API-ключу (APIKeyID != 0) либо по сертификату клиента
+// (ClientCert — опознанная identity). У сервисных аккаунтов доступ ограничен Scopes.
+type Principal struct {
+	UserID     int
+	Roles      []string
+	APIKeyID   int
+	ClientCert string
+	Scopes     []string
+}
+
+func (p *Principal) IsAPIKey() bool {
+	return p.APIKeyID != 0
+}
+
+// IsScoped сообщает, ограничен ли доступ областями (API-ключ или сертификат клиента).
+func (p *Principal) IsScoped() bool {
+	return p.IsAPIKey() || p.ClientCert != ""
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Типы событий журнала аудита.
+const (
+	AuditLogin          = "login"
+	AuditLoginFailed    = "login_failed"
+	AuditPasswordChange = "password_change"
+	AuditTransfer       = "transfer"
+	AuditPurchase       = "purchase"
+	AuditGrant          = "grant"
+	AuditReversal       = "reversal"
+	AuditRoleChange     = "role_change"
+	AuditServiceAccount = "service_account_create"
+	AuditAPIKeyCreate   = "api_key_create"
+	AuditAPIKeyRevoke   = "api_key_revoke"
+	AuditLoginUnlock    = "login_unlock"
+	AuditSessionRevoke  = "session_revoke"
+	AuditTOTPEnable     = "totp_enable"
+	AuditTOTPDisable    = "totp_disable"
+)
+
+// AuditEventTypes — все типы событий, по ним проверяется фильтр.
+var AuditEventTypes = []string{
+	AuditLogin,
+	AuditLoginFailed,
+	AuditPasswordChange,
+	AuditTransfer,
+	AuditPurchase,
+	AuditGrant,
+	AuditReversal,
+	AuditRoleChange,
+	AuditServiceAccount,
+	AuditAPIKeyCreate,
+	AuditAPIKeyRevoke,
+	AuditLoginUnlock,
+	AuditSessionRevoke,
+	AuditTOTPEnable,
+	AuditTOTPDisable,
+}
+
+// AuditEvent — запись журнала аудита. ActorID — кто совершил действие (0 — неизвестно,
+// например неудачный вход), TargetID — над чьим счётом или учётной записью. Балансы —
+// счёта, с которого списаны или на который зачислены монеты: у перевода и покупки это
+// плательщик, у начисления — получатель, у отмены перевода — получатель исходного перевода.
+type AuditEvent struct {
+	ID            int               `json:"id"`
+	EventType     string            `json:"event_type"`
+	ActorID       int               `json:"actor_id"`
+	TargetID      int               `json:"target_id"`
+	IP            string            `json:"ip"`
+	UserAgent     string            `json:"user_agent"`
+	RequestID     string            `json:"request_id"`
+	Currency      string            `json:"currency,omitempty"`
+	Amount        int               `json:"amount,omitempty"`
+	BalanceBefore *int              `json:"balance_before,omitempty"`
+	BalanceAfter  *int              `json:"balance_after,omitempty"`
+	Details       map[string]string `json:"details,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+type AuditFilter struct {
+	EventType string
+	ActorID   int
+	TargetID  int
+	// UserID — события, где пользователь был инициатором или целью.
+	UserID    int
+	IP        string
+	RequestID string
+	From      *time.Time
+	To        *time.Time
+	After     *Cursor
+	Limit     int
+}
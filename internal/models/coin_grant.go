@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type CoinGrant struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Amount    int       `json:"amount"`
+	Reason    string    `json:"reason"`
+	GrantedBy int       `json:"granted_by"`
+	BatchID   *int      `json:"batch_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GrantBatch struct {
+	ID          int       `json:"id"`
+	GrantedBy   int       `json:"granted_by"`
+	RowCount    int       `json:"row_count"`
+	TotalAmount int       `json:"total_amount"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// GrantRow — одна строка массового начисления (username,amount,reason).
+type GrantRow struct {
+	Line     int    `json:"line"`
+	Username string `json:"username"`
+	Amount   int    `json:"amount"`
+	Reason   string `json:"reason"`
+}
@@ -0,0 +1,13 @@
+package models
+
+// CoinMovement — суммарное перемещение монет с одним контрагентом.
+type CoinMovement struct {
+	Username string `json:"username"`
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type InventoryItem struct {
+	MerchName string `json:"merch_name"`
+	Quantity  int    `json:"quantity"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	CoinLotSourceInitial  = "initial"
+	CoinLotSourceTransfer = "transfer"
+	CoinLotSourceGrant    = "grant"
+	CoinLotSourceReversal = "reversal"
+)
+
+type CoinLot struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	Source     string     `json:"source"`
+	Amount     int        `json:"amount"`
+	Remaining  int        `json:"remaining"`
+	ReceivedAt time.Time  `json:"received_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	ExpiredAt  *time.Time `json:"expired_at,omitempty"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+const (
+	CoinRequestPending  = "pending"
+	CoinRequestApproved = "approved"
+	CoinRequestRejected = "rejected"
+	CoinRequestExpired  = "expired"
+)
+
+const (
+	CoinRequestsIncoming = "incoming"
+	CoinRequestsOutgoing = "outgoing"
+)
+
+type CoinRequest struct {
+	ID            int        `json:"id"`
+	RequesterID   int        `json:"requester_id"`
+	PayerID       int        `json:"payer_id"`
+	Amount        int        `json:"amount"`
+	Note          string     `json:"note"`
+	Status        string     `json:"status"`
+	TransactionID *int       `json:"transaction_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// CurrencyCoin — основная валюта: тратится в магазине, баланс хранится в users.balance.
+const CurrencyCoin = "coin"
+
+// CurrencyBalance — баланс пользователя в одной валюте.
+type CurrencyBalance struct {
+	Currency  string `json:"currency"`
+	Balance   int    `json:"balance"`
+	Spendable bool   `json:"spendable"`
+}
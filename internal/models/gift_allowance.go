@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// GiftAllowance — остаток ежемесячного лимита на подарки за период (первое число месяца, UTC).
+type GiftAllowance struct {
+	UserID    int       `json:"user_id"`
+	Balance   int       `json:"balance"`
+	Period    time.Time `json:"period"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+const (
+	DirectionSent     = "sent"
+	DirectionReceived = "received"
+)
+
+// Cursor — позиция keyset-пагинации: последняя отданная запись (created_at, id).
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+type TransactionFilter struct {
+	UserID         int
+	Direction      string
+	CounterpartyID int
+	Currency       string
+	MinAmount      int
+	MaxAmount      int
+	From           *time.Time
+	To             *time.Time
+	After          *Cursor
+	Limit          int
+}
+
+type PurchaseFilter struct {
+	UserID    int
+	MerchName string
+	MinPrice  int
+	MaxPrice  int
+	From      *time.Time
+	To        *time.Time
+	After     *Cursor
+	Limit     int
+}
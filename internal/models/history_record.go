@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	HistoryKindPurchase    = "purchase"
+	HistoryKindTransaction = "transaction"
+)
+
+// HistoryRecord — строка выгрузки истории: покупка или перевод.
+type HistoryRecord struct {
+	Kind       string    `json:"kind"`
+	ID         int       `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	MerchName  string    `json:"merch_name,omitempty"`
+	Price      int       `json:"price,omitempty"`
+	SenderID   int       `json:"sender_id,omitempty"`
+	ReceiverID int       `json:"receiver_id,omitempty"`
+	Amount     int       `json:"amount,omitempty"`
+	Currency   string    `json:"currency"`
+}
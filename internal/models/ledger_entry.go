@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+const (
+	LedgerEntryExpiration = "expiration"
+)
+
+type LedgerEntry struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	EntryType string    `json:"entry_type"`
+	Amount    int       `json:"amount"`
+	LotID     *int      `json:"lot_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -7,5 +7,6 @@ type Purchase struct {
 	UserID    int       `json:"user_id"`
 	MerchName string    `json:"merch_name"`
 	Price     int       `json:"price"`
+	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
 }
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type RefreshToken struct {
+	ID              int        `json:"id"`
+	UserID          int        `json:"user_id"`
+	SessionID       string     `json:"session_id"`
+	TokenHash       string     `json:"-"`
+	AccessJTI       string     `json:"-"`
+	AccessExpiresAt time.Time  `json:"access_expires_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy      *int       `json:"replaced_by,omitempty"`
+}
+
+// AuthTokens — пара токенов, выдаваемая при входе и обновлении. Если у пользователя
+// включена 2FA, вход по паролю возвращает только ChallengeToken: он обменивается
+// на токены вместе с кодом второго фактора.
+type AuthTokens struct {
+	AccessToken        string    `json:"access_token"`
+	RefreshToken       string    `json:"refresh_token"`
+	ExpiresAt          time.Time `json:"expires_at"`
+	ChallengeToken     string    `json:"challenge_token,omitempty"`
+	ChallengeExpiresAt time.Time `json:"challenge_expires_at,omitempty"`
+}
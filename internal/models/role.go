@@ -0,0 +1,21 @@
+package models
+
+// Роли пользователя. Права на методы задаются в proto (access_policy).
+const (
+	RoleUser       = "user"
+	RoleStoreAdmin = "store_admin"
+	RoleFinance    = "finance"
+	RoleSupport    = "support"
+)
+
+// Roles — все известные роли.
+var Roles = []string{RoleUser, RoleStoreAdmin, RoleFinance, RoleSupport}
+
+func IsValidRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Session — вход пользователя на одном устройстве. ID совпадает с claim sid в access-токене.
+type Session struct {
+	ID         string     `json:"id"`
+	UserID     int        `json:"user_id"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// ClientInfo — откуда пришёл запрос: адрес клиента, его User-Agent и идентификатор запроса.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+	RequestID string
+}
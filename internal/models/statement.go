@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+const (
+	MovementInitial     = "initial"
+	MovementPurchase    = "purchase"
+	MovementTransferIn  = "transfer_in"
+	MovementTransferOut = "transfer_out"
+	MovementGrant       = "grant"
+	MovementExpiration  = "expiration"
+)
+
+// StatementMovement — одно изменение баланса. Amount со знаком: поступления
+// положительные, списания отрицательные.
+type StatementMovement struct {
+	Category       string    `json:"category"`
+	ID             int       `json:"id"`
+	CreatedAt      time.Time `json:"created_at"`
+	Amount         int       `json:"amount"`
+	Description    string    `json:"description,omitempty"`
+	CounterpartyID int       `json:"counterparty_id,omitempty"`
+}
+
+type Statement struct {
+	UserID         int                  `json:"user_id"`
+	Period         time.Time            `json:"period"`
+	OpeningBalance int                  `json:"opening_balance"`
+	ClosingBalance int                  `json:"closing_balance"`
+	Totals         map[string]int       `json:"totals"`
+	Movements      []*StatementMovement `json:"movements"`
+	GeneratedAt    time.Time            `json:"generated_at"`
+}
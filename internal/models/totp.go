@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// TOTP — второй фактор пользователя. Включён, когда EnabledAt задан.
+type TOTP struct {
+	UserID      int        `json:"user_id"`
+	Secret      string     `json:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+	EnabledAt   *time.Time `json:"enabled_at,omitempty"`
+	LastCounter int64      `json:"-"`
+}
+
+func (t *TOTP) Enabled() bool {
+	return t.EnabledAt != nil
+}
+
+// TOTPEnrollment — секрет для приложения-аутентификатора, выдаётся при подключении 2FA.
+type TOTPEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
@@ -3,9 +3,28 @@ package models
 import "time"
 
 type Transaction struct {
-	ID         int       `json:"id"`
-	SenderID   int       `json:"sender_id"`
-	ReceiverID int       `json:"receiver_id"`
-	Amount     int       `json:"amount"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID              int       `json:"id"`
+	SenderID        int       `json:"sender_id"`
+	ReceiverID      int       `json:"receiver_id"`
+	Amount          int       `json:"amount"`
+	Currency        string    `json:"currency"`
+	CreatedAt       time.Time `json:"created_at"`
+	ReversalOf      *int      `json:"reversal_of,omitempty"`
+	AllowanceAmount int       `json:"allowance_amount,omitempty"`
+}
+
+type TransactionReversal struct {
+	ID              int       `json:"id"`
+	OriginalID      int       `json:"original_id"`
+	ReversalID      int       `json:"reversal_id"`
+	RequestedAmount int       `json:"requested_amount"`
+	ReversedAmount  int       `json:"reversed_amount"`
+	Reason          string    `json:"reason"`
+	ReversedBy      int       `json:"reversed_by"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type TransferItem struct {
+	ToUser int `json:"to_user"`
+	Amount int `json:"amount"`
 }
@@ -3,9 +3,11 @@ package models
 import "time"
 
 type User struct {
-	ID           int       `json:"id"`
-	Username     string    `json:"username"`
-	PasswordHash string    `json:"-"`
-	Balance      int       `json:"balance"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID               int       `json:"id"`
+	Username         string    `json:"username"`
+	PasswordHash     string    `json:"-"`
+	Balance          int       `json:"balance"`
+	Roles            []string  `json:"roles"`
+	IsServiceAccount bool      `json:"is_service_account"`
+	CreatedAt        time.Time `json:"created_at"`
 }
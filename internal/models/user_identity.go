@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// UserIdentity связывает пользователя с учётной записью у OIDC-провайдера.
+type UserIdentity struct {
+	Issuer      string    `json:"issuer"`
+	Subject     string    `json:"subject"`
+	UserID      int       `json:"user_id"`
+	Email       string    `json:"email"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+// OIDCLogin — начатый вход через OIDC: nonce для провайдера и непрозрачный Handle,
+// по которому сервер найдёт этот nonce при возврате id_token.
+type OIDCLogin struct {
+	Handle    string
+	Nonce     string
+	ExpiresAt time.Time
+}
@@ -1,9 +1,15 @@
 package models
 
 type UserInfo struct {
-	UserID       int            `json:"user_id"`
-	Username     string         `json:"username"`
-	Balance      int            `json:"balance"`
-	Purchases    []*Purchase    `json:"purchases"`
-	Transactions []*Transaction `json:"transactions"`
+	UserID       int                `json:"user_id"`
+	Username     string             `json:"username"`
+	Roles        []string           `json:"roles"`
+	Balance      int                `json:"balance"`
+	Balances     []*CurrencyBalance `json:"balances"`
+	Allowance    *GiftAllowance     `json:"allowance"`
+	Purchases    []*Purchase        `json:"purchases"`
+	Transactions []*Transaction     `json:"transactions"`
+	Inventory    []*InventoryItem   `json:"inventory"`
+	Received     []*CoinMovement    `json:"received"`
+	Sent         []*CoinMovement    `json:"sent"`
 }
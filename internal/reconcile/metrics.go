@@ -0,0 +1,51 @@
+package reconcile
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"strconv"
+)
+
+var (
+	runsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "merch_store_reconcile_runs_total",
+		Help: "Number of balance reconciliation runs.",
+	}, []string{"dry_run"})
+
+	usersScanned = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "merch_store_reconcile_users_scanned_total",
+		Help: "Number of users checked by the balance reconciler.",
+	})
+
+	mismatchesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "merch_store_reconcile_mismatches_total",
+		Help: "Number of confirmed Redis/PostgreSQL balance mismatches.",
+	}, []string{"kind"})
+
+	repairsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "merch_store_reconcile_repairs_total",
+		Help: "Number of repair attempts by result.",
+	}, []string{"result"})
+
+	lastMismatches = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "merch_store_reconcile_last_mismatches",
+		Help: "Mismatches found by the last reconciliation run.",
+	})
+
+	runDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "merch_store_reconcile_duration_seconds",
+		Help:    "Duration of balance reconciliation runs.",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 10),
+	})
+)
+
+func observe(report *Report, dryRun bool) {
+	runsTotal.WithLabelValues(strconv.FormatBool(dryRun)).Inc()
+	usersScanned.Add(float64(report.Scanned))
+	mismatchesTotal.WithLabelValues("missing").Add(float64(report.Missing))
+	mismatchesTotal.WithLabelValues("different").Add(float64(report.Mismatched - report.Missing))
+	repairsTotal.WithLabelValues("repaired").Add(float64(report.Repaired))
+	repairsTotal.WithLabelValues("skipped").Add(float64(report.Skipped))
+	lastMismatches.Set(float64(report.Mismatched))
+	runDuration.Observe(report.Duration.Seconds())
+}
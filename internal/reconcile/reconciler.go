@@ -0,0 +1,248 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/cache"
+	"merch-store-grpc/internal/storage/db"
+	"merch-store-grpc/pkg/logger"
+	"time"
+)
+
+// Reconciler сверяет балансы в Redis с PostgreSQL: balance:{id} — с users.balance,
+// balance:{id}:{currency} — с user_balances (нет строки — нулевой баланс).
+// Источником истины считается PostgreSQL.
+//
+// Кэш обновляется после коммита в БД, поэтому кратковременное расхождение — норма
+// для операций «в полёте». Чтобы не затереть такой баланс, расхождение перепроверяется
+// через confirmDelay, а исправление выполняется compare-and-set'ом по наблюдавшемуся значению.
+type Reconciler struct {
+	users        db.UserRepository
+	balances     db.BalanceRepository
+	cache        cache.CacheRepository
+	currencies   []string
+	batchSize    int
+	confirmDelay time.Duration
+	logger       logger.Logger
+}
+
+type Report struct {
+	Scanned    int
+	Mismatched int
+	Missing    int
+	Repaired   int
+	Skipped    int
+	Duration   time.Duration
+}
+
+type mismatch struct {
+	userID    int
+	currency  string
+	dbBalance int
+	cached    *int
+}
+
+// currencies — коды дополнительных валют; coin проверяется всегда.
+func NewReconciler(users db.UserRepository, balances db.BalanceRepository, cache cache.CacheRepository, currencies []string, batchSize int, confirmDelay time.Duration, log logger.Logger) *Reconciler {
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	return &Reconciler{
+		users:        users,
+		balances:     balances,
+		cache:        cache,
+		currencies:   append([]string{models.CurrencyCoin}, currencies...),
+		batchSize:    batchSize,
+		confirmDelay: confirmDelay,
+		logger:       log,
+	}
+}
+
+// Run проходит по всем пользователям пачками. При dryRun расхождения только
+// подсчитываются и логируются.
+func (r *Reconciler) Run(ctx context.Context, dryRun bool) (*Report, error) {
+	start := time.Now()
+	report := &Report{}
+
+	afterID := 0
+	for {
+		users, err := r.users.ListUsers(ctx, afterID, r.batchSize)
+		if err != nil {
+			return report, err
+		}
+		if len(users) == 0 {
+			break
+		}
+		afterID = users[len(users)-1].ID
+
+		coinBalances := make(map[int]int, len(users))
+		ids := make([]int, 0, len(users))
+		for _, u := range users {
+			coinBalances[u.ID] = u.Balance
+			ids = append(ids, u.ID)
+		}
+		report.Scanned += len(users)
+
+		var found []mismatch
+		for _, currency := range r.currencies {
+			dbBalances := coinBalances
+			if currency != models.CurrencyCoin {
+				if dbBalances, err = r.balances.GetBalancesByUserIDs(ctx, ids, currency); err != nil {
+					return report, err
+				}
+			}
+			m, err := r.findMismatches(ctx, currency, ids, dbBalances)
+			if err != nil {
+				return report, err
+			}
+			found = append(found, m...)
+		}
+		if len(found) == 0 {
+			continue
+		}
+
+		confirmed, err := r.confirm(ctx, found)
+		if err != nil {
+			return report, err
+		}
+
+		for _, m := range confirmed {
+			report.Mismatched++
+			if m.cached == nil {
+				report.Missing++
+			}
+
+			r.logger.Warnw("balance mismatch",
+				"userID", m.userID,
+				"currency", m.currency,
+				"dbBalance", m.dbBalance,
+				"cachedBalance", cachedValue(m.cached),
+				"dryRun", dryRun,
+			)
+
+			if dryRun {
+				continue
+			}
+
+			ok, err := r.cache.RepairCurrencyBalance(ctx, m.userID, m.currency, m.cached, m.dbBalance)
+			if err != nil {
+				return report, fmt.Errorf("repair %s balance for user %d: %w", m.currency, m.userID, err)
+			}
+			if ok {
+				report.Repaired++
+			} else {
+				report.Skipped++
+			}
+		}
+	}
+
+	report.Duration = time.Since(start)
+	observe(report, dryRun)
+
+	r.logger.Infow("Balance reconciliation finished",
+		"scanned", report.Scanned,
+		"mismatched", report.Mismatched,
+		"missing", report.Missing,
+		"repaired", report.Repaired,
+		"skipped", report.Skipped,
+		"dryRun", dryRun,
+		"duration", report.Duration.String(),
+	)
+
+	return report, nil
+}
+
+func (r *Reconciler) findMismatches(ctx context.Context, currency string, ids []int, dbBalances map[int]int) ([]mismatch, error) {
+	cached, err := r.cache.GetCurrencyBalances(ctx, currency, ids)
+	if err != nil {
+		return nil, fmt.Errorf("get cached %s balances: %w", currency, err)
+	}
+
+	var found []mismatch
+	for _, id := range ids {
+		c, ok := cached[id]
+		if ok && c == dbBalances[id] {
+			continue
+		}
+		m := mismatch{userID: id, currency: currency, dbBalance: dbBalances[id]}
+		if ok {
+			m.cached = &c
+		}
+		found = append(found, m)
+	}
+	return found, nil
+}
+
+// confirm перечитывает расхождения через confirmDelay и оставляет только те,
+// где ни БД, ни кэш за это время не изменились.
+func (r *Reconciler) confirm(ctx context.Context, found []mismatch) ([]mismatch, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(r.confirmDelay):
+	}
+
+	byCurrency := make(map[string][]int)
+	for _, m := range found {
+		byCurrency[m.currency] = append(byCurrency[m.currency], m.userID)
+	}
+
+	dbNow := make(map[string]map[int]int, len(byCurrency))
+	cachedNow := make(map[string]map[int]int, len(byCurrency))
+	for currency, ids := range byCurrency {
+		balances, err := r.dbBalances(ctx, currency, ids)
+		if err != nil {
+			return nil, err
+		}
+		dbNow[currency] = balances
+
+		cached, err := r.cache.GetCurrencyBalances(ctx, currency, ids)
+		if err != nil {
+			return nil, fmt.Errorf("get cached %s balances: %w", currency, err)
+		}
+		cachedNow[currency] = cached
+	}
+
+	var confirmed []mismatch
+	for _, m := range found {
+		balance, exists := dbNow[m.currency][m.userID]
+		if m.currency != models.CurrencyCoin {
+			// Нет строки в user_balances — нулевой баланс.
+			exists = true
+		}
+		if !exists || balance != m.dbBalance {
+			continue
+		}
+		c, ok := cachedNow[m.currency][m.userID]
+		if ok != (m.cached != nil) || (ok && c != *m.cached) {
+			continue
+		}
+		confirmed = append(confirmed, m)
+	}
+	return confirmed, nil
+}
+
+// dbBalances читает текущие балансы из PostgreSQL. Для coin в результат попадают только
+// существующие пользователи.
+func (r *Reconciler) dbBalances(ctx context.Context, currency string, ids []int) (map[int]int, error) {
+	if currency != models.CurrencyCoin {
+		return r.balances.GetBalancesByUserIDs(ctx, ids, currency)
+	}
+	users, err := r.users.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	balances := make(map[int]int, len(users))
+	for _, u := range users {
+		balances[u.ID] = u.Balance
+	}
+	return balances, nil
+}
+
+func cachedValue(v *int) interface{} {
+	if v == nil {
+		return "missing"
+	}
+	return *v
+}
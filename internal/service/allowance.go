@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/models"
+	"time"
+)
+
+// allowancePeriod — период лимита на подарки: первое число месяца в UTC.
+func allowancePeriod(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// effectiveAllowance учитывает сброс: лимит за прошлый месяц (или отсутствующий)
+// считается полным месячным лимитом, даже если фоновая задача ещё не отработала.
+func (s *merchStoreServiceImp) effectiveAllowance(allowance *models.GiftAllowance, err error, now time.Time) (int, error) {
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return s.allowance.MonthlyAmount, nil
+		}
+		return 0, err
+	}
+	if allowance.Period.Before(allowancePeriod(now)) {
+		return s.allowance.MonthlyAmount, nil
+	}
+	return allowance.Balance, nil
+}
+
+// GetGiftAllowance возвращает текущий остаток лимита на подарки.
+func (s *merchStoreServiceImp) GetGiftAllowance(ctx context.Context, userID int) (*models.GiftAllowance, error) {
+	now := time.Now()
+	result := &models.GiftAllowance{UserID: userID, Period: allowancePeriod(now)}
+	if !s.allowance.Enabled {
+		return result, nil
+	}
+
+	allowance, err := s.repo.GetAllowance(ctx, userID)
+	balance, err := s.effectiveAllowance(allowance, err, now)
+	if err != nil {
+		return nil, err
+	}
+	result.Balance = balance
+	return result, nil
+}
+
+// allowanceInTx блокирует и возвращает остаток лимита внутри транзакции.
+func (s *merchStoreServiceImp) allowanceInTx(txCtx context.Context, userID int, now time.Time) (int, error) {
+	if !s.allowance.Enabled {
+		return 0, nil
+	}
+	allowance, err := s.repo.GetAllowanceForUpdate(txCtx, userID)
+	return s.effectiveAllowance(allowance, err, now)
+}
+
+// ResetGiftAllowances выставляет месячный лимит всем, у кого он относится к прошлому периоду.
+func (s *merchStoreServiceImp) ResetGiftAllowances(ctx context.Context, now time.Time) (int, error) {
+	if !s.allowance.Enabled {
+		return 0, nil
+	}
+
+	period := allowancePeriod(now)
+	reset, err := s.repo.ResetAllowances(ctx, s.allowance.MonthlyAmount, period)
+	if err != nil {
+		return 0, err
+	}
+	if reset > 0 {
+		s.log.Infow("Gift allowances reset",
+			"users", reset,
+			"period", period.Format("2006-01"),
+			"amount", s.allowance.MonthlyAmount,
+		)
+	}
+	return reset, nil
+}
+
+// transferInCache повторяет в Redis уже закоммиченный перевод. Часть из лимита
+// на подарки в кэшированном балансе не участвует.
+func (s *merchStoreServiceImp) transferInCache(ctx context.Context, t *models.Transaction) error {
+	if t.Currency != models.CurrencyCoin {
+		return s.cacheRepo.TransferCurrency(ctx, t.Currency, t.SenderID, t.ReceiverID, t.Amount)
+	}
+	if t.AllowanceAmount == 0 {
+		return s.cacheRepo.TransferCoins(ctx, t.SenderID, t.ReceiverID, t.Amount)
+	}
+
+	debit, credit := t.Amount-t.AllowanceAmount, t.Amount
+	if t.ReversalOf != nil {
+		debit, credit = t.Amount, t.Amount-t.AllowanceAmount
+	}
+	return s.cacheRepo.IncrementBalances(ctx, map[int]int{
+		t.SenderID:   -debit,
+		t.ReceiverID: credit,
+	})
+}
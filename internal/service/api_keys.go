@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// APIKeyPrefix — начало каждого API-ключа, по нему interceptor отличает ключ от JWT.
+	APIKeyPrefix = "msk_"
+
+	defaultAPIKeyExpiryDays = 90
+	maxAPIKeyNameLength     = 100
+)
+
+// CreateServiceAccount создаёт пользователя без пароля для интеграций. Войти по паролю
+// под ним нельзя, запросы выполняются только с API-ключами.
+func (s *merchStoreServiceImp) CreateServiceAccount(ctx context.Context, adminID int, username string, roles []string) (*models.User, error) {
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+	normalized, err := normalizeRoles(roles)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username:         username,
+		IsServiceAccount: true,
+		CreatedAt:        time.Now(),
+	}
+	err = s.txManager.WithTx(ctx, pgx.ReadCommitted, pgx.ReadWrite, func(txCtx context.Context) error {
+		var err error
+		user.ID, err = s.repo.CreateUser(txCtx, user)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.SetUserRoles(txCtx, user.ID, normalized); err != nil {
+			return err
+		}
+		return s.auditInTx(txCtx, &models.AuditEvent{
+			EventType: models.AuditServiceAccount,
+			ActorID:   adminID,
+			TargetID:  user.ID,
+			Details:   map[string]string{"username": username, "roles": strings.Join(normalized, ",")},
+		})
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrDuplicateKey) {
+			return nil, fmt.Errorf("%w: %s", ErrUserAlreadyExists, username)
+		}
+		return nil, err
+	}
+	user.Roles = normalized
+
+	if err := s.cacheRepo.SetBalance(ctx, user.ID, 0); err != nil {
+		s.log.Errorw("set balance in cache", "userID", user.ID, "error", err)
+	}
+
+	s.log.Infow("Service account created",
+		"userID", user.ID,
+		"roles", normalized,
+		"createdBy", adminID,
+	)
+	return user, nil
+}
+
+// CreateAPIKey выпускает ключ сервисному аккаунту. Ключ целиком возвращается только здесь,
+// в базе остаются его префикс и SHA-256.
+func (s *merchStoreServiceImp) CreateAPIKey(ctx context.Context, adminID int, username, name string, scopes []string, expiresInDays int) (*models.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxAPIKeyNameLength {
+		return nil, "", fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidArgument, maxAPIKeyNameLength)
+	}
+
+	if len(scopes) == 0 {
+		return nil, "", fmt.Errorf("%w: at least one scope is required", ErrInvalidArgument)
+	}
+	var normalized []string
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !slices.Contains(models.Scopes, scope) {
+			return nil, "", fmt.Errorf("%w: unknown scope %q", ErrInvalidArgument, scope)
+		}
+		if !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+
+	if expiresInDays <= 0 {
+		expiresInDays = s.apiKeys.DefaultExpiryDays
+		if expiresInDays <= 0 {
+			expiresInDays = defaultAPIKeyExpiryDays
+		}
+	}
+	if maxDays := s.apiKeys.MaxExpiryDays; maxDays > 0 && expiresInDays > maxDays {
+		return nil, "", fmt.Errorf("%w: expiry must not exceed %d days", ErrInvalidArgument, maxDays)
+	}
+
+	user, err := s.repo.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, "", fmt.Errorf("%w: %s", ErrUserNotFound, username)
+		}
+		return nil, "", err
+	}
+	if !user.IsServiceAccount {
+		return nil, "", fmt.Errorf("%w: %s is not a service account", ErrInvalidArgument, username)
+	}
+
+	prefix, secret, err := newAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	plain := prefix + "_" + secret
+
+	now := time.Now()
+	key := &models.APIKey{
+		UserID:    user.ID,
+		Username:  user.Username,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   hashAPIKey(plain),
+		Scopes:    normalized,
+		CreatedBy: adminID,
+		CreatedAt: now,
+		ExpiresAt: now.AddDate(0, 0, expiresInDays),
+	}
+	err = s.txManager.WithTx(ctx, pgx.ReadCommitted, pgx.ReadWrite, func(txCtx context.Context) error {
+		var err error
+		key.ID, err = s.repo.CreateAPIKey(txCtx, key)
+		if err != nil {
+			return err
+		}
+		return s.auditInTx(txCtx, &models.AuditEvent{
+			EventType: models.AuditAPIKeyCreate,
+			ActorID:   adminID,
+			TargetID:  user.ID,
+			Details: map[string]string{
+				"key_id":     strconv.Itoa(key.ID),
+				"prefix":     key.Prefix,
+				"name":       key.Name,
+				"scopes":     strings.Join(normalized, ","),
+				"expires_at": key.ExpiresAt.UTC().Format(time.RFC3339),
+			},
+			CreatedAt: now,
+		})
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.log.Infow("API key created",
+		"keyID", key.ID,
+		"prefix", key.Prefix,
+		"userID", user.ID,
+		"scopes", normalized,
+		"createdBy", adminID,
+	)
+	return key, plain, nil
+}
+
+func (s *merchStoreServiceImp) ListAPIKeys(ctx context.Context, username string) ([]*models.APIKey, error) {
+	user, err := s.repo.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrUserNotFound, username)
+		}
+		return nil, err
+	}
+	return s.repo.ListAPIKeys(ctx, user.ID)
+}
+
+func (s *merchStoreServiceImp) RevokeAPIKey(ctx context.Context, adminID, keyID int) error {
+	err := s.txManager.WithTx(ctx, pgx.ReadCommitted, pgx.ReadWrite, func(txCtx context.Context) error {
+		ownerID, revoked, err := s.repo.RevokeAPIKey(txCtx, keyID, time.Now())
+		if err != nil {
+			return err
+		}
+		if !revoked {
+			return ErrAPIKeyNotFound
+		}
+		return s.auditInTx(txCtx, &models.AuditEvent{
+			EventType: models.AuditAPIKeyRevoke,
+			ActorID:   adminID,
+			TargetID:  ownerID,
+			Details:   map[string]string{"key_id": strconv.Itoa(keyID)},
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.log.Infow("API key revoked", "keyID", keyID, "revokedBy", adminID)
+	return nil
+}
+
+// AuthenticateAPIKey проверяет ключ и возвращает сервисный аккаунт с ролями и областями ключа.
+func (s *merchStoreServiceImp) AuthenticateAPIKey(ctx context.Context, plain string) (*models.Principal, error) {
+	prefix, _, ok := splitAPIKey(plain)
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+
+	key, err := s.repo.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashAPIKey(plain))) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+
+	now := time.Now()
+	if key.RevokedAt != nil || !now.Before(key.ExpiresAt) {
+		return nil, ErrInvalidCredentials
+	}
+
+	user, err := s.repo.GetUserByID(ctx, key.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.TouchAPIKey(ctx, key.ID, now); err != nil {
+		s.log.Errorw("update api key last used", "keyID", key.ID, "error", err)
+	}
+
+	return &models.Principal{
+		UserID:   user.ID,
+		Roles:    user.Roles,
+		APIKeyID: key.ID,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+// newAPIKey возвращает открытый префикс (msk_ и 12 hex-символов) и секретную часть ключа.
+func newAPIKey() (string, string, error) {
+	id := make([]byte, 6)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", fmt.Errorf("generate api key: %w", err)
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", fmt.Errorf("generate api key: %w", err)
+	}
+	return APIKeyPrefix + hex.EncodeToString(id), base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// splitAPIKey разбирает ключ вида msk_<id>_<secret>; в id нет '_', в секрете может быть.
+func splitAPIKey(plain string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(plain, APIKeyPrefix)
+	if !ok {
+		return "", "", false
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || id == "" || secret == "" {
+		return "", "", false
+	}
+	return APIKeyPrefix + id, secret, true
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
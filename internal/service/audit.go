@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db/postgres"
+	"slices"
+	"time"
+)
+
+// Причины неудачного входа в details.reason журнала аудита.
+const (
+	loginFailureUnknownUser     = "unknown_user"
+	loginFailureInvalidPassword = "invalid_password"
+	loginFailureInvalidCode     = "invalid_two_factor_code"
+	loginFailureThrottled       = "throttled"
+)
+
+// auditInTx пишет событие в транзакции операции: если запись не удалась, операция откатывается.
+// Так в журнале нет движений монет, которых не было, и нет движений без записи.
+func (s *merchStoreServiceImp) auditInTx(txCtx context.Context, event *models.AuditEvent) error {
+	s.fillAuditEvent(txCtx, event)
+	_, err := s.repo.CreateAuditEvent(txCtx, event)
+	return err
+}
+
+// audit пишет событие вне транзакции. Ошибка только логируется: сбой журнала не должен
+// мешать входу.
+func (s *merchStoreServiceImp) audit(ctx context.Context, event *models.AuditEvent) {
+	s.fillAuditEvent(ctx, event)
+	if _, err := s.repo.CreateAuditEvent(ctx, event); err != nil {
+		s.log.Errorw("write audit event", "eventType", event.EventType, "actorID", event.ActorID, "error", err)
+	}
+}
+
+func (s *merchStoreServiceImp) fillAuditEvent(ctx context.Context, event *models.AuditEvent) {
+	client := clientInfo(ctx)
+	event.IP = client.IP
+	event.UserAgent = client.UserAgent
+	event.RequestID = client.RequestID
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+}
+
+func (s *merchStoreServiceImp) auditLogin(ctx context.Context, userID int, method string) {
+	s.audit(ctx, &models.AuditEvent{
+		EventType: models.AuditLogin,
+		ActorID:   userID,
+		TargetID:  userID,
+		Details:   map[string]string{"method": method},
+	})
+}
+
+// auditLoginFailure записывает неудачный вход. userID — учётная запись, под которую пытались
+// войти (0, если такой нет); username сохраняется всегда, в том числе несуществующий.
+func (s *merchStoreServiceImp) auditLoginFailure(ctx context.Context, userID int, username, reason string) {
+	s.audit(ctx, &models.AuditEvent{
+		EventType: models.AuditLoginFailed,
+		TargetID:  userID,
+		Details:   map[string]string{"username": username, "reason": reason},
+	})
+}
+
+// auditThrottledLogin записывает попытку входа, отклонённую лимитами или блокировкой.
+func (s *merchStoreServiceImp) auditThrottledLogin(ctx context.Context, username string, err error) {
+	var throttled *LoginThrottledError
+	if errors.As(err, &throttled) {
+		s.auditLoginFailure(ctx, 0, username, loginFailureThrottled)
+	}
+}
+
+// QueryAuditLog возвращает записи журнала аудита по фильтру, от новых к старым.
+func (s *merchStoreServiceImp) QueryAuditLog(ctx context.Context, filter *models.AuditFilter, cursor string) ([]*models.AuditEvent, string, error) {
+	if filter.EventType != "" && !slices.Contains(models.AuditEventTypes, filter.EventType) {
+		return nil, "", fmt.Errorf("%w: unknown event type %q", ErrInvalidArgument, filter.EventType)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, "", fmt.Errorf("%w: from must be before to", ErrInvalidArgument)
+	}
+
+	limit, err := normalizePageSize(filter.Limit)
+	if err != nil {
+		return nil, "", err
+	}
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := *filter
+	query.After = after
+	query.Limit = limit + 1
+
+	var events []*models.AuditEvent
+	err = s.txManager.WithTx(ctx, postgres.IsolationLevelReadCommitted, postgres.AccessModeReadOnly, func(txCtx context.Context) error {
+		var err error
+		events, err = s.repo.ListAuditEvents(txCtx, &query)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(events) > limit {
+		events = events[:limit]
+		last := events[limit-1]
+		next = encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return events, next, nil
+}
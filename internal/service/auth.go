@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9._-]{3,32}$`)
+
+// Register создаёт нового пользователя и сразу выдаёт ему токен.
+func (s *merchStoreServiceImp) Register(ctx context.Context, username, password string) (*models.AuthTokens, error) {
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+	if err := s.validatePassword(password, username); err != nil {
+		return nil, err
+	}
+	if err := s.checkRegisterAllowed(ctx); err != nil {
+		s.log.Warnw("Registration throttled", "username", username, "error", err)
+		return nil, err
+	}
+
+	existing, err := s.repo.GetUserByUsername(ctx, username)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUserAlreadyExists, username)
+	}
+
+	user, err := s.createNewUser(ctx, username, password)
+	if err != nil {
+		if errors.Is(err, db.ErrDuplicateKey) {
+			return nil, fmt.Errorf("%w: %s", ErrUserAlreadyExists, username)
+		}
+		return nil, err
+	}
+
+	s.log.Infow("User registered", "userID", user.ID, "username", username)
+	return s.issueTokens(ctx, user.ID)
+}
+
+// Login проверяет пароль существующего пользователя. Неизвестный username и неверный
+// пароль неразличимы для клиента. При включённой 2FA вместо токенов возвращается challenge.
+func (s *merchStoreServiceImp) Login(ctx context.Context, username, password, clientIP string) (*models.AuthTokens, error) {
+	user, err := s.login(ctx, username, password, clientIP)
+	if err != nil {
+		return nil, err
+	}
+	return s.completeLogin(ctx, user, "password")
+}
+
+func (s *merchStoreServiceImp) login(ctx context.Context, username, password, clientIP string) (*models.User, error) {
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+	if err := s.checkLoginAllowed(ctx, username, clientIP); err != nil {
+		s.auditThrottledLogin(ctx, username, err)
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.passwordHasher.Check(s.dummyHash, password)
+			s.recordLoginFailure(ctx, username)
+			s.auditLoginFailure(ctx, 0, username, loginFailureUnknownUser)
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if !s.passwordHasher.Check(user.PasswordHash, password) {
+		s.recordLoginFailure(ctx, username)
+		s.auditLoginFailure(ctx, user.ID, username, loginFailureInvalidPassword)
+		return nil, ErrInvalidCredentials
+	}
+	s.recordLoginSuccess(ctx, username)
+	s.upgradePasswordHash(ctx, user, password)
+
+	s.warmCache(ctx, user)
+	return user, nil
+}
+
+// upgradePasswordHash пересчитывает хэш, сделанный устаревшим алгоритмом или с другими
+// параметрами. Ошибка не мешает входу: хэш обновится при следующем.
+func (s *merchStoreServiceImp) upgradePasswordHash(ctx context.Context, user *models.User, password string) {
+	if !s.passwordHasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+
+	hash, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		s.log.Errorw("rehash password", "userID", user.ID, "error", err)
+		return
+	}
+	replaced, err := s.repo.ReplacePasswordHash(ctx, user.ID, user.PasswordHash, hash)
+	if err != nil {
+		s.log.Errorw("rehash password", "userID", user.ID, "error", err)
+		return
+	}
+	if replaced {
+		s.log.Infow("Password hash upgraded", "userID", user.ID)
+		user.PasswordHash = hash
+	}
+}
+
+// ChangePassword меняет пароль после проверки старого. Все сессии пользователя
+// отзываются, взамен открывается новая — для клиента, сменившего пароль.
+func (s *merchStoreServiceImp) ChangePassword(ctx context.Context, userID int, oldPassword, newPassword string) (*models.AuthTokens, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	// Старый пароль подбирается так же, как при входе, поэтому действуют те же лимиты.
+	if err := s.checkLoginAllowed(ctx, user.Username, ""); err != nil {
+		s.auditThrottledLogin(ctx, user.Username, err)
+		return nil, err
+	}
+	if oldPassword == "" || !s.passwordHasher.Check(user.PasswordHash, oldPassword) {
+		s.recordLoginFailure(ctx, user.Username)
+		s.auditLoginFailure(ctx, user.ID, user.Username, loginFailureInvalidPassword)
+		return nil, ErrInvalidCredentials
+	}
+	s.recordLoginSuccess(ctx, user.Username)
+
+	if newPassword == oldPassword {
+		return nil, fmt.Errorf("%w: new password must differ from the old one", ErrInvalidArgument)
+	}
+	if err := s.validatePassword(newPassword, user.Username); err != nil {
+		return nil, err
+	}
+
+	hash, err := s.passwordHasher.Hash(newPassword)
+	if err != nil {
+		return nil, fmt.Errorf("hash password: %w", err)
+	}
+
+	var revoked []*models.RefreshToken
+	err = s.txManager.WithTx(ctx, pgx.ReadCommitted, pgx.ReadWrite, func(txCtx context.Context) error {
+		if err := s.repo.UpdatePassword(txCtx, userID, hash); err != nil {
+			return err
+		}
+		var err error
+		revoked, err = s.repo.RevokeUserSessions(txCtx, userID, time.Now())
+		if err != nil {
+			return err
+		}
+		return s.auditInTx(txCtx, &models.AuditEvent{
+			EventType: models.AuditPasswordChange,
+			ActorID:   userID,
+			TargetID:  userID,
+			Details:   map[string]string{"revoked_tokens": strconv.Itoa(len(revoked))},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.revokeAccessTokens(ctx, revoked); err != nil {
+		return nil, fmt.Errorf("password changed but failed to revoke sessions: %w", err)
+	}
+
+	s.log.Infow("Password changed", "userID", userID, "revokedTokens", len(revoked))
+	return s.issueTokens(ctx, userID)
+}
+
+// warmCache кладёт балансы пользователя в Redis, если их там нет.
+func (s *merchStoreServiceImp) warmCache(ctx context.Context, user *models.User) {
+	_, err := s.cacheRepo.GetBalance(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			if setErr := s.cacheRepo.SetBalance(ctx, user.ID, user.Balance); setErr != nil {
+				s.log.Errorw("set balance in cache", "userID", user.ID, "error", setErr)
+			}
+		} else {
+			s.log.Errorw("get balance from cache", "userID", user.ID, "error", err)
+		}
+	}
+	s.warmCurrencyCache(ctx, user.ID)
+}
+
+func validateUsername(username string) error {
+	if !usernamePattern.MatchString(username) {
+		return fmt.Errorf("%w: username must be 3-32 characters of letters, digits, '.', '_' or '-'", ErrInvalidArgument)
+	}
+	return nil
+}
+
+func (s *merchStoreServiceImp) validatePassword(password, username string) error {
+	if err := s.passwordPolicy.Validate(password, username); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArgument, err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/models"
+	"slices"
+)
+
+// AuthenticateClientCert сопоставляет identity проверенного сертификата клиента сервисному
+// аккаунту по client_certs. Сертификат без сопоставления — ErrInvalidCredentials.
+func (s *merchStoreServiceImp) AuthenticateClientCert(ctx context.Context, identities []string) (*models.Principal, error) {
+	for _, mapping := range s.clientCerts {
+		if !slices.Contains(identities, mapping.Identity) {
+			continue
+		}
+
+		user, err := s.repo.GetUserByUsername(ctx, mapping.ServiceAccount)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				s.log.Warnw("client certificate mapped to unknown service account",
+					"identity", mapping.Identity,
+					"serviceAccount", mapping.ServiceAccount,
+				)
+				return nil, fmt.Errorf("%w: service account %s not found", ErrInvalidCredentials, mapping.ServiceAccount)
+			}
+			return nil, err
+		}
+		if !user.IsServiceAccount {
+			s.log.Warnw("client certificate mapped to a regular user",
+				"identity", mapping.Identity,
+				"username", mapping.ServiceAccount,
+			)
+			return nil, fmt.Errorf("%w: %s is not a service account", ErrInvalidCredentials, mapping.ServiceAccount)
+		}
+
+		return &models.Principal{
+			UserID:     user.ID,
+			Roles:      user.Roles,
+			ClientCert: mapping.Identity,
+			Scopes:     mapping.Scopes,
+		}, nil
+	}
+
+	return nil, ErrInvalidCredentials
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db/postgres"
+	"time"
+)
+
+func (s *merchStoreServiceImp) newCoinLot(userID int, source string, amount int, receivedAt time.Time) *models.CoinLot {
+	return &models.CoinLot{
+		UserID:     userID,
+		Source:     source,
+		Amount:     amount,
+		Remaining:  amount,
+		ReceivedAt: receivedAt,
+		ExpiresAt:  receivedAt.AddDate(0, s.coins.ExpiryMonths, 0),
+	}
+}
+
+func (s *merchStoreServiceImp) GetExpiringCoins(ctx context.Context, userID int) ([]*models.CoinLot, error) {
+	var lots []*models.CoinLot
+
+	err := s.txManager.WithTx(ctx, postgres.IsolationLevelReadCommitted, postgres.AccessModeReadOnly, func(txCtx context.Context) error {
+		var err error
+		lots, err = s.repo.GetOpenCoinLots(txCtx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lots, nil
+}
+
+// ExpireCoins сгорает все лоты, срок которых истёк к моменту now, и возвращает
+// количество обработанных лотов. Лоты обрабатываются пачками по batchSize,
+// каждая пачка — в отдельной транзакции.
+func (s *merchStoreServiceImp) ExpireCoins(ctx context.Context, now time.Time, batchSize int) (int, error) {
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("%w: batch size must be positive", ErrInvalidArgument)
+	}
+	total := 0
+	for {
+		processed, err := s.expireCoinsBatch(ctx, now, batchSize)
+		if err != nil {
+			return total, err
+		}
+		total += processed
+		if processed < batchSize {
+			return total, nil
+		}
+	}
+}
+
+func (s *merchStoreServiceImp) expireCoinsBatch(ctx context.Context, now time.Time, batchSize int) (int, error) {
+	var (
+		processed int
+		perUser   map[int]int
+	)
+
+	err := s.txManager.WithTx(ctx, pgx.Serializable, pgx.ReadWrite, func(txCtx context.Context) error {
+		lots, err := s.repo.GetExpiredCoinLots(txCtx, now, batchSize)
+		if err != nil {
+			return err
+		}
+
+		perUser = make(map[int]int)
+		for _, lot := range lots {
+			if err := s.repo.MarkCoinLotExpired(txCtx, lot.ID, now); err != nil {
+				return err
+			}
+			entry := &models.LedgerEntry{
+				UserID:    lot.UserID,
+				EntryType: models.LedgerEntryExpiration,
+				Amount:    -lot.Remaining,
+				LotID:     &lot.ID,
+				CreatedAt: now,
+			}
+			if _, err := s.repo.CreateLedgerEntry(txCtx, entry); err != nil {
+				return err
+			}
+			perUser[lot.UserID] += lot.Remaining
+		}
+
+		for userID, amount := range perUser {
+			user, err := s.repo.GetUserByID(txCtx, userID)
+			if err != nil {
+				return err
+			}
+			if user.Balance < amount {
+				return fmt.Errorf("user %d: balance %d is lower than expiring amount %d", userID, user.Balance, amount)
+			}
+			if err := s.repo.UpdateBalance(txCtx, userID, user.Balance-amount); err != nil {
+				return err
+			}
+		}
+
+		processed = len(lots)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for userID, amount := range perUser {
+		if err := s.cacheRepo.IncrementBalance(ctx, userID, -amount); err != nil {
+			s.log.Errorw("coins expired but failed to update cache",
+				"userID", userID,
+				"amount", amount,
+				"error", err,
+			)
+		}
+	}
+
+	if processed > 0 {
+		s.log.Infow("Expired coin lots",
+			"lots", processed,
+			"users", len(perUser),
+		)
+	}
+
+	return processed, nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db/postgres"
+	"time"
+)
+
+const maxCoinRequestNoteLength = 500
+
+func (s *merchStoreServiceImp) RequestCoins(ctx context.Context, requesterID, payerID, amount int, note string) (*models.CoinRequest, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidArgument)
+	}
+	if requesterID == payerID {
+		return nil, fmt.Errorf("%w: cannot request coins from yourself", ErrInvalidArgument)
+	}
+	if len(note) > maxCoinRequestNoteLength {
+		return nil, fmt.Errorf("%w: note is longer than %d characters", ErrInvalidArgument, maxCoinRequestNoteLength)
+	}
+
+	if _, err := s.repo.GetUserByID(ctx, payerID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %d", ErrUserNotFound, payerID)
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	req := &models.CoinRequest{
+		RequesterID: requesterID,
+		PayerID:     payerID,
+		Amount:      amount,
+		Note:        note,
+		Status:      models.CoinRequestPending,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(time.Duration(s.coins.RequestTTL) * time.Second),
+	}
+
+	requestID, err := s.repo.CreateCoinRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	req.ID = requestID
+
+	return req, nil
+}
+
+func (s *merchStoreServiceImp) ListCoinRequests(ctx context.Context, userID int, direction, status string) ([]*models.CoinRequest, error) {
+	switch direction {
+	case "", models.CoinRequestsIncoming, models.CoinRequestsOutgoing:
+	default:
+		return nil, fmt.Errorf("%w: unknown direction %q", ErrInvalidArgument, direction)
+	}
+	switch status {
+	case "", models.CoinRequestPending, models.CoinRequestApproved, models.CoinRequestRejected, models.CoinRequestExpired:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidArgument, status)
+	}
+
+	var requests []*models.CoinRequest
+	err := s.txManager.WithTx(ctx, postgres.IsolationLevelReadCommitted, postgres.AccessModeReadOnly, func(txCtx context.Context) error {
+		var err error
+		requests, err = s.repo.ListCoinRequests(txCtx, userID, direction, status)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// ApproveCoinRequest одобряет запрос: плательщик переводит монеты запросившему
+// той же атомарной операцией, что и TransferCoins.
+func (s *merchStoreServiceImp) ApproveCoinRequest(ctx context.Context, payerID, requestID int) (*models.CoinRequest, error) {
+	var (
+		req      *models.CoinRequest
+		txRecord *models.Transaction
+	)
+
+	err := s.txManager.WithTx(ctx, pgx.Serializable, pgx.ReadWrite, func(txCtx context.Context) error {
+		var err error
+		req, err = s.pendingCoinRequestForPayer(txCtx, payerID, requestID)
+		if err != nil {
+			return err
+		}
+
+		txRecord, err = s.transferInTx(txCtx, req.PayerID, req.RequesterID, models.CurrencyCoin, req.Amount)
+		if err != nil {
+			return err
+		}
+		transactionID := txRecord.ID
+
+		now := time.Now()
+		if err := s.repo.ResolveCoinRequest(txCtx, req.ID, models.CoinRequestApproved, &transactionID, now); err != nil {
+			return err
+		}
+		req.Status = models.CoinRequestApproved
+		req.TransactionID = &transactionID
+		req.ResolvedAt = &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transferInCache(ctx, txRecord); err != nil {
+		return nil, fmt.Errorf("transfer succeeded but failed to update cache: %w", err)
+	}
+
+	return req, nil
+}
+
+func (s *merchStoreServiceImp) RejectCoinRequest(ctx context.Context, payerID, requestID int) (*models.CoinRequest, error) {
+	var req *models.CoinRequest
+
+	err := s.txManager.WithTx(ctx, pgx.ReadCommitted, pgx.ReadWrite, func(txCtx context.Context) error {
+		var err error
+		req, err = s.pendingCoinRequestForPayer(txCtx, payerID, requestID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := s.repo.ResolveCoinRequest(txCtx, req.ID, models.CoinRequestRejected, nil, now); err != nil {
+			return err
+		}
+		req.Status = models.CoinRequestRejected
+		req.ResolvedAt = &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// ExpireCoinRequests переводит просроченные запросы в статус expired.
+func (s *merchStoreServiceImp) ExpireCoinRequests(ctx context.Context, now time.Time) (int, error) {
+	expired, err := s.repo.ExpireCoinRequests(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	if expired > 0 {
+		s.log.Infow("Expired coin requests", "count", expired)
+	}
+	return expired, nil
+}
+
+func (s *merchStoreServiceImp) pendingCoinRequestForPayer(txCtx context.Context, payerID, requestID int) (*models.CoinRequest, error) {
+	req, err := s.repo.GetCoinRequestForUpdate(txCtx, requestID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCoinRequestNotFound
+		}
+		return nil, err
+	}
+	if req.PayerID != payerID {
+		// Не раскрываем чужие запросы
+		return nil, ErrCoinRequestNotFound
+	}
+	if req.Status != models.CoinRequestPending {
+		return nil, fmt.Errorf("%w: status is %s", ErrCoinRequestNotPending, req.Status)
+	}
+	if !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: request expired", ErrCoinRequestNotPending)
+	}
+	return req, nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"merch-store-grpc/internal/config"
+	"merch-store-grpc/internal/models"
+	"strings"
+	"time"
+)
+
+// newCurrencyList собирает список валют: основная coin всегда первая.
+func newCurrencyList(initialBalance int, extra []config.CurrencyConfig) []config.CurrencyConfig {
+	currencies := []config.CurrencyConfig{{
+		Code:           models.CurrencyCoin,
+		Spendable:      true,
+		InitialBalance: initialBalance,
+	}}
+	for _, c := range extra {
+		code := strings.ToLower(strings.TrimSpace(c.Code))
+		if code == "" || code == models.CurrencyCoin {
+			continue
+		}
+		c.Code = code
+		currencies = append(currencies, c)
+	}
+	return currencies
+}
+
+// resolveCurrency находит валюту по коду; пустой код — основная валюта.
+func (s *merchStoreServiceImp) resolveCurrency(code string) (config.CurrencyConfig, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		code = models.CurrencyCoin
+	}
+	for _, c := range s.currencies {
+		if c.Code == code {
+			return c, nil
+		}
+	}
+	return config.CurrencyConfig{}, fmt.Errorf("%w: unknown currency %q", ErrInvalidArgument, code)
+}
+
+func (s *merchStoreServiceImp) cachedBalance(ctx context.Context, userID int, currency string) (int, error) {
+	if currency == models.CurrencyCoin {
+		return s.cacheRepo.GetBalance(ctx, userID)
+	}
+	return s.cacheRepo.GetCurrencyBalance(ctx, userID, currency)
+}
+
+// warmCurrencyCache кладёт в Redis балансы дополнительных валют, если их там нет.
+func (s *merchStoreServiceImp) warmCurrencyCache(ctx context.Context, userID int) {
+	if len(s.currencies) == 1 {
+		return
+	}
+
+	var balances map[string]int
+	for _, c := range s.currencies[1:] {
+		_, err := s.cacheRepo.GetCurrencyBalance(ctx, userID, c.Code)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, redis.Nil) {
+			s.log.Errorw("get balance from cache", "userID", userID, "currency", c.Code, "error", err)
+			continue
+		}
+		if balances == nil {
+			if balances, err = s.repo.GetBalancesByUserID(ctx, userID); err != nil {
+				s.log.Errorw("get balances from DB", "userID", userID, "error", err)
+				return
+			}
+		}
+		if err := s.cacheRepo.SetCurrencyBalance(ctx, userID, c.Code, balances[c.Code]); err != nil {
+			s.log.Errorw("set balance in cache", "userID", userID, "currency", c.Code, "error", err)
+		}
+	}
+}
+
+// balanceInTx читает баланс внутри транзакции. Для дополнительных валют строка блокируется.
+func (s *merchStoreServiceImp) balanceInTx(txCtx context.Context, userID int, currency string) (int, error) {
+	if currency == models.CurrencyCoin {
+		user, err := s.repo.GetUserByID(txCtx, userID)
+		if err != nil {
+			return 0, err
+		}
+		return user.Balance, nil
+	}
+	return s.repo.GetBalanceForUpdate(txCtx, userID, currency)
+}
+
+// debitInTx списывает amount; для coin дополнительно списываются лоты по FIFO.
+func (s *merchStoreServiceImp) debitInTx(txCtx context.Context, userID int, currency string, amount int) error {
+	balance, err := s.balanceInTx(txCtx, userID, currency)
+	if err != nil {
+		return err
+	}
+	if balance < amount {
+		return fmt.Errorf("insufficient %s funds in DB", currency)
+	}
+
+	if currency != models.CurrencyCoin {
+		return s.repo.AddBalance(txCtx, userID, currency, -amount)
+	}
+	if err := s.repo.UpdateBalance(txCtx, userID, balance-amount); err != nil {
+		return err
+	}
+	return s.repo.ConsumeCoinLots(txCtx, userID, amount)
+}
+
+// creditInTx зачисляет amount; для coin создаётся новый лот с источником source.
+func (s *merchStoreServiceImp) creditInTx(txCtx context.Context, userID int, currency string, amount int, source string, at time.Time) error {
+	if currency != models.CurrencyCoin {
+		return s.repo.AddBalance(txCtx, userID, currency, amount)
+	}
+
+	balance, err := s.balanceInTx(txCtx, userID, currency)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdateBalance(txCtx, userID, balance+amount); err != nil {
+		return err
+	}
+	_, err = s.repo.CreateCoinLot(txCtx, s.newCoinLot(userID, source, amount, at))
+	return err
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"merch-store-grpc/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// encodeCursor упаковывает позицию (created_at, id) в непрозрачную строку для клиента.
+func encodeCursor(createdAt time.Time, id int) string {
+	raw := fmt.Sprintf("%d:%d", createdAt.UnixNano(), id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (*models.Cursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidArgument)
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidArgument)
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidArgument)
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidArgument)
+	}
+
+	return &models.Cursor{CreatedAt: time.Unix(0, nanos).UTC(), ID: id}, nil
+}
+
+func normalizePageSize(limit int) (int, error) {
+	switch {
+	case limit < 0:
+		return 0, fmt.Errorf("%w: limit must not be negative", ErrInvalidArgument)
+	case limit == 0:
+		return defaultPageSize, nil
+	case limit > maxPageSize:
+		return maxPageSize, nil
+	}
+	return limit, nil
+}
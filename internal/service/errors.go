@@ -0,0 +1,30 @@
+package service
+
+import "errors"
+
+var (
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrInvalidArgument  = errors.New("invalid argument")
+	ErrUserNotFound     = errors.New("user not found")
+
+	ErrUserAlreadyExists  = errors.New("user already exists")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrTooManyAttempts    = errors.New("too many login attempts")
+	ErrOIDCDisabled       = errors.New("oidc login is not enabled")
+
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+
+	ErrCoinRequestNotFound   = errors.New("coin request not found")
+	ErrCoinRequestNotPending = errors.New("coin request is not pending")
+
+	ErrTransactionNotFound        = errors.New("transaction not found")
+	ErrTransactionAlreadyReversed = errors.New("transaction already reversed")
+	ErrTransactionNotReversible   = errors.New("transaction cannot be reversed")
+
+	ErrCurrencyNotSpendable = errors.New("currency cannot be spent in the store")
+
+	ErrAPIKeyNotFound = errors.New("api key not found")
+
+	ErrSessionNotFound = errors.New("session not found")
+)
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"io"
+	"merch-store-grpc/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxGrantReasonLength = 500
+
+func (s *merchStoreServiceImp) GrantCoins(ctx context.Context, adminID int, username string, amount int, reason string) (*models.CoinGrant, error) {
+	row := models.GrantRow{Line: 1, Username: username, Amount: amount, Reason: reason}
+	if problem := validateGrantRow(row); problem != "" {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidArgument, problem)
+	}
+
+	user, err := s.repo.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrUserNotFound, username)
+		}
+		return nil, err
+	}
+
+	grant := &models.CoinGrant{
+		UserID:    user.ID,
+		Amount:    amount,
+		Reason:    strings.TrimSpace(reason),
+		GrantedBy: adminID,
+		CreatedAt: time.Now(),
+	}
+
+	err = s.txManager.WithTx(ctx, pgx.Serializable, pgx.ReadWrite, func(txCtx context.Context) error {
+		return s.applyGrant(txCtx, grant)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cacheRepo.IncrementBalance(ctx, user.ID, amount); err != nil {
+		return nil, fmt.Errorf("grant succeeded but failed to update cache: %w", err)
+	}
+
+	s.log.Infow("Coins granted",
+		"grantID", grant.ID,
+		"userID", user.ID,
+		"amount", amount,
+		"grantedBy", adminID,
+	)
+
+	return grant, nil
+}
+
+// GrantCoinsBulk начисляет монеты по CSV (username,amount,reason). Строки проверяются
+// целиком до начала записи, а сама пачка применяется в одной транзакции: либо все, либо ничего.
+func (s *merchStoreServiceImp) GrantCoinsBulk(ctx context.Context, adminID int, csvData string) (*models.GrantBatch, error) {
+	rows, err := ParseGrantCSV(strings.NewReader(csvData))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArgument, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: csv contains no rows", ErrInvalidArgument)
+	}
+
+	var problems []string
+	usernames := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if problem := validateGrantRow(row); problem != "" {
+			problems = append(problems, fmt.Sprintf("line %d: %s", row.Line, problem))
+			continue
+		}
+		usernames = append(usernames, row.Username)
+	}
+
+	users, err := s.repo.GetUsersByUsernames(ctx, usernames)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make(map[string]int, len(users))
+	for _, u := range users {
+		userIDs[u.Username] = u.ID
+	}
+	for _, row := range rows {
+		if row.Username == "" {
+			continue
+		}
+		if _, ok := userIDs[row.Username]; !ok {
+			problems = append(problems, fmt.Sprintf("line %d: user %q not found", row.Line, row.Username))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("%w: %s", ErrInvalidArgument, strings.Join(problems, "; "))
+	}
+
+	now := time.Now()
+	batch := &models.GrantBatch{
+		GrantedBy: adminID,
+		RowCount:  len(rows),
+		CreatedAt: now,
+	}
+	perUser := make(map[int]int)
+	for _, row := range rows {
+		batch.TotalAmount += row.Amount
+		perUser[userIDs[row.Username]] += row.Amount
+	}
+
+	err = s.txManager.WithTx(ctx, pgx.Serializable, pgx.ReadWrite, func(txCtx context.Context) error {
+		batchID, err := s.repo.CreateGrantBatch(txCtx, batch)
+		if err != nil {
+			return err
+		}
+		batch.ID = batchID
+
+		for _, row := range rows {
+			grant := &models.CoinGrant{
+				UserID:    userIDs[row.Username],
+				Amount:    row.Amount,
+				Reason:    strings.TrimSpace(row.Reason),
+				GrantedBy: adminID,
+				BatchID:   &batchID,
+				CreatedAt: now,
+			}
+			if err := s.applyGrant(txCtx, grant); err != nil {
+				return fmt.Errorf("line %d: %w", row.Line, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cacheRepo.IncrementBalances(ctx, perUser); err != nil {
+		return nil, fmt.Errorf("bulk grant succeeded but failed to update cache: %w", err)
+	}
+
+	s.log.Infow("Bulk coin grant applied",
+		"batchID", batch.ID,
+		"rows", batch.RowCount,
+		"totalAmount", batch.TotalAmount,
+		"grantedBy", adminID,
+	)
+
+	return batch, nil
+}
+
+// applyGrant пополняет баланс, заводит лот и пишет запись в журнал начислений и в журнал аудита.
+// Вызывается внутри транзакции.
+func (s *merchStoreServiceImp) applyGrant(txCtx context.Context, grant *models.CoinGrant) error {
+	user, err := s.repo.GetUserByID(txCtx, grant.UserID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdateBalance(txCtx, grant.UserID, user.Balance+grant.Amount); err != nil {
+		return err
+	}
+	lot := s.newCoinLot(grant.UserID, models.CoinLotSourceGrant, grant.Amount, grant.CreatedAt)
+	if _, err := s.repo.CreateCoinLot(txCtx, lot); err != nil {
+		return err
+	}
+	grantID, err := s.repo.CreateCoinGrant(txCtx, grant)
+	if err != nil {
+		return err
+	}
+	grant.ID = grantID
+
+	details := map[string]string{"grant_id": strconv.Itoa(grantID), "reason": grant.Reason}
+	if grant.BatchID != nil {
+		details["batch_id"] = strconv.Itoa(*grant.BatchID)
+	}
+	after := user.Balance + grant.Amount
+	return s.auditInTx(txCtx, &models.AuditEvent{
+		EventType:     models.AuditGrant,
+		ActorID:       grant.GrantedBy,
+		TargetID:      grant.UserID,
+		Currency:      models.CurrencyCoin,
+		Amount:        grant.Amount,
+		BalanceBefore: &user.Balance,
+		BalanceAfter:  &after,
+		Details:       details,
+		CreatedAt:     grant.CreatedAt,
+	})
+}
+
+func validateGrantRow(row models.GrantRow) string {
+	switch {
+	case strings.TrimSpace(row.Username) == "":
+		return "username is required"
+	case row.Amount <= 0:
+		return "amount must be a positive integer"
+	case strings.TrimSpace(row.Reason) == "":
+		return "reason is required"
+	case len(row.Reason) > maxGrantReasonLength:
+		return fmt.Sprintf("reason is longer than %d characters", maxGrantReasonLength)
+	}
+	return ""
+}
+
+// ParseGrantCSV разбирает CSV с колонками username,amount,reason.
+// Первая строка пропускается, если это заголовок.
+func ParseGrantCSV(r io.Reader) ([]models.GrantRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	var rows []models.GrantRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse csv: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[1]), "amount") {
+			continue
+		}
+
+		row := models.GrantRow{
+			Line:     line,
+			Username: strings.TrimSpace(record[0]),
+			Reason:   record[2],
+		}
+		amount, err := strconv.Atoi(strings.TrimSpace(record[1]))
+		if err != nil {
+			// Оставляем Amount нулевым: строка не пройдёт валидацию и попадёт в отчёт.
+			amount = 0
+		}
+		row.Amount = amount
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
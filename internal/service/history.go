@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db/postgres"
+	"time"
+)
+
+// ListTransactions возвращает страницу переводов и курсор следующей страницы
+// (пустой, если страница последняя).
+func (s *merchStoreServiceImp) ListTransactions(ctx context.Context, filter *models.TransactionFilter, cursor string) ([]*models.Transaction, string, error) {
+	switch filter.Direction {
+	case "", models.DirectionSent, models.DirectionReceived:
+	default:
+		return nil, "", fmt.Errorf("%w: unknown direction %q", ErrInvalidArgument, filter.Direction)
+	}
+	if err := validateRange(filter.MinAmount, filter.MaxAmount, "amount"); err != nil {
+		return nil, "", err
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, "", fmt.Errorf("%w: from must be before to", ErrInvalidArgument)
+	}
+
+	limit, err := normalizePageSize(filter.Limit)
+	if err != nil {
+		return nil, "", err
+	}
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := *filter
+	query.After = after
+	query.Limit = limit + 1
+
+	var transactions []*models.Transaction
+	err = s.txManager.WithTx(ctx, postgres.IsolationLevelReadCommitted, postgres.AccessModeReadOnly, func(txCtx context.Context) error {
+		var err error
+		transactions, err = s.repo.ListTransactions(txCtx, &query)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(transactions) > limit {
+		transactions = transactions[:limit]
+		last := transactions[limit-1]
+		next = encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return transactions, next, nil
+}
+
+// ListPurchases возвращает страницу покупок и курсор следующей страницы.
+func (s *merchStoreServiceImp) ListPurchases(ctx context.Context, filter *models.PurchaseFilter, cursor string) ([]*models.Purchase, string, error) {
+	if err := validateRange(filter.MinPrice, filter.MaxPrice, "price"); err != nil {
+		return nil, "", err
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, "", fmt.Errorf("%w: from must be before to", ErrInvalidArgument)
+	}
+
+	limit, err := normalizePageSize(filter.Limit)
+	if err != nil {
+		return nil, "", err
+	}
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := *filter
+	query.After = after
+	query.Limit = limit + 1
+
+	var purchases []*models.Purchase
+	err = s.txManager.WithTx(ctx, postgres.IsolationLevelReadCommitted, postgres.AccessModeReadOnly, func(txCtx context.Context) error {
+		var err error
+		purchases, err = s.repo.ListPurchases(txCtx, &query)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(purchases) > limit {
+		purchases = purchases[:limit]
+		last := purchases[limit-1]
+		next = encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return purchases, next, nil
+}
+
+func validateRange(lo, hi int, name string) error {
+	if lo < 0 || hi < 0 {
+		return fmt.Errorf("%w: %s bounds must not be negative", ErrInvalidArgument, name)
+	}
+	if hi > 0 && lo > hi {
+		return fmt.Errorf("%w: min %s is greater than max %s", ErrInvalidArgument, name, name)
+	}
+	return nil
+}
+
+// ExportHistory передаёт в fn всю историю покупок и переводов пользователя
+// за период. Чтение идёт из одного снимка данных (REPEATABLE READ).
+func (s *merchStoreServiceImp) ExportHistory(ctx context.Context, userID int, from, to *time.Time, fn func(*models.HistoryRecord) error) error {
+	if from != nil && to != nil && !from.Before(*to) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidArgument)
+	}
+
+	return s.txManager.WithTx(ctx, postgres.IsolationLevelRepeatableRead, postgres.AccessModeReadOnly, func(txCtx context.Context) error {
+		return s.repo.StreamHistory(txCtx, userID, from, to, fn)
+	})
+}
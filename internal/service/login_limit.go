@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/cache"
+	"time"
+)
+
+// LoginThrottledError — вход временно запрещён из-за частых попыток или блокировки
+// после неверных паролей. Повторить можно через RetryAfter.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many login attempts, retry after %s", e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+// checkLoginAllowed проверяет блокировку username и учитывает попытку в лимитах
+// по IP и по username. Без Redis вход не выполняется.
+func (s *merchStoreServiceImp) checkLoginAllowed(ctx context.Context, username, clientIP string) error {
+	cfg := s.loginLimit
+	if !cfg.Enabled {
+		return nil
+	}
+
+	locked, err := s.cacheRepo.GetLoginLockout(ctx, username)
+	if err != nil {
+		return fmt.Errorf("check login lockout: %w", err)
+	}
+	if locked > 0 {
+		return &LoginThrottledError{RetryAfter: locked}
+	}
+
+	if clientIP != "" {
+		if err := s.hitLoginLimit(ctx, "login:ip:"+clientIP, cfg.IPMaxAttempts, cfg.IPWindow); err != nil {
+			return err
+		}
+	}
+	return s.hitLoginLimit(ctx, "login:user:"+username, cfg.UsernameMaxAttempts, cfg.UsernameWindow)
+}
+
+// checkRegisterAllowed учитывает регистрацию в том же лимите по IP, что и вход:
+// иначе перебор и массовое создание аккаунтов идут через Register без ограничений.
+func (s *merchStoreServiceImp) checkRegisterAllowed(ctx context.Context) error {
+	cfg := s.loginLimit
+	clientIP := clientInfo(ctx).IP
+	if !cfg.Enabled || clientIP == "" {
+		return nil
+	}
+	return s.hitLoginLimit(ctx, "login:ip:"+clientIP, cfg.IPMaxAttempts, cfg.IPWindow)
+}
+
+func (s *merchStoreServiceImp) hitLoginLimit(ctx context.Context, key string, maxAttempts, window int) error {
+	if maxAttempts <= 0 || window <= 0 {
+		return nil
+	}
+	n, retryAfter, err := s.cacheRepo.HitRateLimit(ctx, key, time.Duration(window)*time.Second)
+	if err != nil {
+		return fmt.Errorf("check login rate limit: %w", err)
+	}
+	if n > maxAttempts {
+		return &LoginThrottledError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// recordLoginFailure учитывает неверный пароль; после login_limit.max_failures
+// вход под username блокируется, каждая следующая блокировка дольше предыдущей.
+func (s *merchStoreServiceImp) recordLoginFailure(ctx context.Context, username string) {
+	cfg := s.loginLimit
+	if !cfg.Enabled || cfg.MaxFailures <= 0 {
+		return
+	}
+
+	locked, err := s.cacheRepo.RegisterLoginFailure(ctx, username, cache.LockoutPolicy{
+		MaxFailures:   cfg.MaxFailures,
+		FailureWindow: time.Duration(cfg.FailureWindow) * time.Second,
+		Base:          time.Duration(cfg.LockoutBase) * time.Second,
+		Max:           time.Duration(cfg.LockoutMax) * time.Second,
+		Reset:         time.Duration(cfg.LockoutReset) * time.Second,
+	})
+	if err != nil {
+		s.log.Errorw("register login failure", "username", username, "error", err)
+		return
+	}
+	if locked > 0 {
+		s.log.Warnw("Login locked after repeated failures", "username", username, "lockout", locked)
+	}
+}
+
+func (s *merchStoreServiceImp) recordLoginSuccess(ctx context.Context, username string) {
+	if !s.loginLimit.Enabled {
+		return
+	}
+	if err := s.cacheRepo.ResetLoginFailures(ctx, username); err != nil {
+		s.log.Errorw("reset login failures", "username", username, "error", err)
+	}
+}
+
+// UnlockLogin снимает блокировку входа, наложенную после неверных паролей.
+func (s *merchStoreServiceImp) UnlockLogin(ctx context.Context, adminID int, username string) error {
+	user, err := s.repo.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s", ErrUserNotFound, username)
+		}
+		return err
+	}
+
+	// Блокировка хранится в Redis, поэтому событие пишется до снятия: без записи снятия не будет.
+	err = s.auditInTx(ctx, &models.AuditEvent{
+		EventType: models.AuditLoginUnlock,
+		ActorID:   adminID,
+		TargetID:  user.ID,
+		Details:   map[string]string{"username": user.Username},
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.cacheRepo.UnlockLogin(ctx, user.Username); err != nil {
+		return fmt.Errorf("unlock login: %w", err)
+	}
+
+	s.log.Infow("Login unlocked", "userID", user.ID, "unlockedBy", adminID)
+	return nil
+}
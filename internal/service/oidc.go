@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db"
+	"merch-store-grpc/pkg/oidc"
+	"strings"
+	"time"
+)
+
+const oidcLoginTTL = 10 * time.Minute
+
+// BeginOIDCLogin выдаёт nonce для запроса авторизации. Nonce хранится в Redis под хэшем
+// handle и принимается один раз: клиент не может подставить nonce из чужого id_token.
+func (s *merchStoreServiceImp) BeginOIDCLogin(ctx context.Context) (*models.OIDCLogin, error) {
+	if s.oidc == nil {
+		return nil, ErrOIDCDisabled
+	}
+
+	handle, err := oidc.RandomString(32)
+	if err != nil {
+		return nil, fmt.Errorf("generate login handle: %w", err)
+	}
+	nonce, err := oidc.RandomString(24)
+	if err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	if err := s.cacheRepo.SaveOIDCNonce(ctx, hashChallengeToken(handle), nonce, oidcLoginTTL); err != nil {
+		return nil, fmt.Errorf("save oidc nonce: %w", err)
+	}
+
+	return &models.OIDCLogin{
+		Handle:    handle,
+		Nonce:     nonce,
+		ExpiresAt: time.Now().Add(oidcLoginTTL),
+	}, nil
+}
+
+// LoginWithOIDC впускает пользователя по id_token корпоративного SSO. Пользователь ищется
+// по issuer + sub, nonce берётся из BeginOIDCLogin по loginHandle; при первом входе создаётся с начальным балансом или, при
+// oidc.link_existing_users, привязывается к локальному пользователю (см. canLinkOIDC).
+// Если у пользователя включена 2FA, вместо токенов возвращается challenge.
+func (s *merchStoreServiceImp) LoginWithOIDC(ctx context.Context, rawIDToken, loginHandle string) (*models.AuthTokens, error) {
+	if s.oidc == nil {
+		return nil, ErrOIDCDisabled
+	}
+	if rawIDToken == "" || loginHandle == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	nonce, err := s.cacheRepo.TakeOIDCNonce(ctx, hashChallengeToken(loginHandle))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, fmt.Errorf("%w: login expired or already used", ErrInvalidCredentials)
+		}
+		return nil, err
+	}
+
+	token, err := s.oidc.VerifyIDToken(ctx, rawIDToken, nonce)
+	if err != nil {
+		if errors.Is(err, oidc.ErrInvalidIDToken) {
+			s.log.Warnw("OIDC id token rejected", "error", err)
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	var (
+		user    *models.User
+		created bool
+		linked  bool
+	)
+	now := time.Now()
+	err = s.txManager.WithTx(ctx, pgx.ReadCommitted, pgx.ReadWrite, func(txCtx context.Context) error {
+		identity, err := s.repo.GetUserIdentity(txCtx, token.Issuer, token.Subject)
+		if err == nil {
+			if err := s.repo.TouchUserIdentity(txCtx, token.Issuer, token.Subject, token.Email, now); err != nil {
+				return err
+			}
+			user, err = s.repo.GetUserByID(txCtx, identity.UserID)
+			return err
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		username, err := s.oidcUsername(token)
+		if err != nil {
+			return err
+		}
+
+		user, err = s.repo.GetUserByUsername(txCtx, username)
+		switch {
+		case err == nil:
+			if !s.canLinkOIDC(token, user) {
+				return fmt.Errorf("%w: %s", ErrUserAlreadyExists, username)
+			}
+			linked = true
+		case errors.Is(err, pgx.ErrNoRows):
+			user, err = s.createUserInTx(txCtx, username, "")
+			if err != nil {
+				return err
+			}
+			created = true
+		default:
+			return err
+		}
+
+		return s.repo.CreateUserIdentity(txCtx, &models.UserIdentity{
+			Issuer:      token.Issuer,
+			Subject:     token.Subject,
+			UserID:      user.ID,
+			Email:       token.Email,
+			CreatedAt:   now,
+			LastLoginAt: now,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrDuplicateKey) {
+			return nil, fmt.Errorf("%w: concurrent first login", ErrUserAlreadyExists)
+		}
+		return nil, err
+	}
+
+	if created {
+		if err := s.cacheNewUser(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		s.log.Infow("User registered via OIDC", "userID", user.ID, "username", user.Username)
+	} else {
+		s.warmCache(ctx, user)
+	}
+	if linked {
+		s.log.Infow("OIDC identity linked to existing user", "userID", user.ID, "username", user.Username)
+	}
+
+	return s.completeLogin(ctx, user, "oidc")
+}
+
+// canLinkOIDC решает, можно ли привязать новую внешнюю учётную запись к существующему
+// пользователю. Совпадения username мало: admin@любой-домен не должен становиться admin.
+// Нужен подтверждённый адрес ровно {username}@{oidc.link_email_domain}, а пользователи
+// с ролями сверх user и сервисные аккаунты не привязываются никогда.
+func (s *merchStoreServiceImp) canLinkOIDC(token *oidc.IDToken, user *models.User) bool {
+	if !s.oidcConfig.LinkExistingUsers || s.oidcConfig.LinkEmailDomain == "" {
+		return false
+	}
+	if user.IsServiceAccount {
+		return false
+	}
+	for _, role := range user.Roles {
+		if role != models.RoleUser {
+			return false
+		}
+	}
+	if !token.EmailVerified {
+		return false
+	}
+	return strings.EqualFold(token.Email, user.Username+"@"+s.oidcConfig.LinkEmailDomain)
+}
+
+// oidcUsername выбирает username нового пользователя по oidc.username_claim. Для email
+// берётся часть до @, и только если провайдер подтвердил адрес.
+func (s *merchStoreServiceImp) oidcUsername(token *oidc.IDToken) (string, error) {
+	var username string
+	switch claim := s.oidcConfig.UsernameClaim; claim {
+	case "", "email":
+		if token.Email == "" || !token.EmailVerified {
+			return "", fmt.Errorf("%w: email is missing or not verified", ErrPermissionDenied)
+		}
+		username, _, _ = strings.Cut(token.Email, "@")
+	case "sub":
+		username = token.Subject
+	default:
+		username = token.Claim(claim)
+	}
+
+	if err := validateUsername(username); err != nil {
+		return "", fmt.Errorf("%w: claim %q does not yield a valid username", ErrInvalidArgument, s.oidcConfig.UsernameClaim)
+	}
+	return username, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReverseTransaction откатывает перевод компенсирующей записью в transactions,
+// связанной с исходной через reversal_of. Если получатель уже потратил часть монет,
+// откат либо отклоняется, либо (при coins.allow_partial_reversal) выполняется на остаток.
+func (s *merchStoreServiceImp) ReverseTransaction(ctx context.Context, adminID, transactionID int, reason string) (*models.TransactionReversal, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidArgument)
+	}
+	if len(reason) > maxGrantReasonLength {
+		return nil, fmt.Errorf("%w: reason is too long", ErrInvalidArgument)
+	}
+
+	var (
+		reversal     models.TransactionReversal
+		original     *models.Transaction
+		compensating *models.Transaction
+	)
+
+	err := s.txManager.WithTx(ctx, pgx.Serializable, pgx.ReadWrite, func(txCtx context.Context) error {
+		var err error
+		original, err = s.repo.GetTransactionForUpdate(txCtx, transactionID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrTransactionNotFound
+			}
+			return err
+		}
+		if original.ReversalOf != nil {
+			return fmt.Errorf("%w: transaction %d is itself a reversal", ErrInvalidArgument, transactionID)
+		}
+		if original.SenderID == 0 || original.ReceiverID == 0 {
+			return fmt.Errorf("%w: transaction %d references a deleted user", ErrTransactionNotReversible, transactionID)
+		}
+
+		if _, err := s.repo.GetReversalOf(txCtx, transactionID); err == nil {
+			return ErrTransactionAlreadyReversed
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		if _, err := s.repo.LockUsersByIDs(txCtx, []int{original.SenderID, original.ReceiverID}); err != nil {
+			return err
+		}
+		available, err := s.balanceInTx(txCtx, original.ReceiverID, original.Currency)
+		if err != nil {
+			return err
+		}
+
+		amount := original.Amount
+		if available < amount {
+			if !s.coins.AllowPartialReversal {
+				return fmt.Errorf("%w: receiver has %d of %d coins left", ErrTransactionNotReversible, available, amount)
+			}
+			amount = available
+		}
+		if amount <= 0 {
+			return fmt.Errorf("%w: receiver has no coins left", ErrTransactionNotReversible)
+		}
+
+		// Часть, оплаченная из лимита на подарки, возвращается в лимит, а не на баланс, и только
+		// если перевод был в текущем месяце — не больше месячной суммы. Иначе (лимит отключён или
+		// месяц сменился) она сгорает: подарочные монеты не становятся тратимыми и не переносятся.
+		spendablePart := min(amount, original.Amount-original.AllowanceAmount)
+		allowancePart := amount - spendablePart
+
+		now := time.Now()
+		if err := s.debitInTx(txCtx, original.ReceiverID, original.Currency, amount); err != nil {
+			return err
+		}
+		if spendablePart > 0 {
+			if err := s.creditInTx(txCtx, original.SenderID, original.Currency, spendablePart, models.CoinLotSourceReversal, now); err != nil {
+				return err
+			}
+		}
+		samePeriod := allowancePeriod(original.CreatedAt).Equal(allowancePeriod(now))
+		if allowancePart > 0 && s.allowance.Enabled && samePeriod {
+			allowance, err := s.allowanceInTx(txCtx, original.SenderID, now)
+			if err != nil {
+				return err
+			}
+			restored := min(allowance+allowancePart, s.allowance.MonthlyAmount)
+			if err := s.repo.SetAllowance(txCtx, original.SenderID, restored, allowancePeriod(now)); err != nil {
+				return err
+			}
+		}
+
+		compensating = &models.Transaction{
+			SenderID:        original.ReceiverID,
+			ReceiverID:      original.SenderID,
+			Amount:          amount,
+			Currency:        original.Currency,
+			CreatedAt:       now,
+			ReversalOf:      &original.ID,
+			AllowanceAmount: allowancePart,
+		}
+		compensating.ID, err = s.repo.CreateTransaction(txCtx, compensating)
+		if err != nil {
+			return err
+		}
+
+		reversal = models.TransactionReversal{
+			OriginalID:      original.ID,
+			ReversalID:      compensating.ID,
+			RequestedAmount: original.Amount,
+			ReversedAmount:  amount,
+			Reason:          reason,
+			ReversedBy:      adminID,
+			CreatedAt:       now,
+		}
+		reversal.ID, err = s.repo.CreateTransactionReversal(txCtx, &reversal)
+		if err != nil {
+			return err
+		}
+
+		after := available - amount
+		return s.auditInTx(txCtx, &models.AuditEvent{
+			EventType:     models.AuditReversal,
+			ActorID:       adminID,
+			TargetID:      original.ReceiverID,
+			Currency:      original.Currency,
+			Amount:        amount,
+			BalanceBefore: &available,
+			BalanceAfter:  &after,
+			Details: map[string]string{
+				"transaction_id":   strconv.Itoa(original.ID),
+				"reversal_id":      strconv.Itoa(compensating.ID),
+				"sender_id":        strconv.Itoa(original.SenderID),
+				"requested_amount": strconv.Itoa(original.Amount),
+				"allowance_amount": strconv.Itoa(allowancePart),
+				"reason":           reason,
+			},
+			CreatedAt: now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transferInCache(ctx, compensating); err != nil {
+		return nil, fmt.Errorf("reversal succeeded but failed to update cache: %w", err)
+	}
+
+	s.log.Infow("Transaction reversed",
+		"transactionID", original.ID,
+		"reversalID", reversal.ReversalID,
+		"amount", reversal.ReversedAmount,
+		"reversedBy", adminID,
+	)
+
+	return &reversal, nil
+}
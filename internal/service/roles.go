@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/models"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SetUserRoles заменяет набор ролей пользователя. Роль user есть у всех и добавляется
+// автоматически. Роли записаны в access-токенах, поэтому все сессии пользователя
+// завершаются: после понижения старые права не действуют до истечения токенов.
+func (s *merchStoreServiceImp) SetUserRoles(ctx context.Context, adminID int, username string, roles []string) (*models.User, error) {
+	normalized, err := normalizeRoles(roles)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		user    *models.User
+		revoked []*models.RefreshToken
+	)
+	err = s.txManager.WithTx(ctx, pgx.ReadCommitted, pgx.ReadWrite, func(txCtx context.Context) error {
+		var err error
+		user, err = s.repo.GetUserByUsername(txCtx, username)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: %s", ErrUserNotFound, username)
+			}
+			return err
+		}
+		// Иначе можно остаться без единого администратора ролей.
+		if user.ID == adminID && !slices.Contains(normalized, models.RoleStoreAdmin) {
+			return fmt.Errorf("%w: cannot remove own %s role", ErrInvalidArgument, models.RoleStoreAdmin)
+		}
+		if err := s.repo.SetUserRoles(txCtx, user.ID, normalized); err != nil {
+			return err
+		}
+		revoked, err = s.repo.RevokeUserSessions(txCtx, user.ID, time.Now())
+		if err != nil {
+			return err
+		}
+		return s.auditInTx(txCtx, &models.AuditEvent{
+			EventType: models.AuditRoleChange,
+			ActorID:   adminID,
+			TargetID:  user.ID,
+			Details: map[string]string{
+				"previous_roles": strings.Join(user.Roles, ","),
+				"roles":          strings.Join(normalized, ","),
+				"revoked_tokens": strconv.Itoa(len(revoked)),
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	user.Roles = normalized
+
+	if err := s.revokeAccessTokens(ctx, revoked); err != nil {
+		return nil, fmt.Errorf("roles changed but failed to revoke sessions: %w", err)
+	}
+
+	s.log.Infow("User roles changed",
+		"userID", user.ID,
+		"roles", normalized,
+		"changedBy", adminID,
+		"revokedTokens", len(revoked),
+	)
+
+	return user, nil
+}
+
+// normalizeRoles проверяет роли, убирает повторы и добавляет роль user.
+func normalizeRoles(roles []string) ([]string, error) {
+	normalized := []string{models.RoleUser}
+	for _, role := range roles {
+		role = strings.TrimSpace(role)
+		if !models.IsValidRole(role) {
+			return nil, fmt.Errorf("%w: unknown role %q", ErrInvalidArgument, role)
+		}
+		if !slices.Contains(normalized, role) {
+			normalized = append(normalized, role)
+		}
+	}
+	return normalized, nil
+}
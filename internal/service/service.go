@@ -4,23 +4,67 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/go-redis/redis/v8"
 	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/config"
 	"merch-store-grpc/internal/models"
 	"merch-store-grpc/internal/storage/cache"
 	"merch-store-grpc/internal/storage/db"
 	"merch-store-grpc/internal/storage/db/postgres"
 	"merch-store-grpc/pkg/jwt"
 	"merch-store-grpc/pkg/logger"
+	"merch-store-grpc/pkg/oidc"
 	"merch-store-grpc/pkg/password"
+	"strconv"
 	"time"
 )
 
 type MerchStoreService interface {
-	Authenticate(ctx context.Context, username, password string) (string, error)
-	PurchaseMerch(ctx context.Context, userID int, merchName string) error
-	TransferCoins(ctx context.Context, fromUser, toUser, amount int) error
+	Authenticate(ctx context.Context, username, password, clientIP string) (*models.AuthTokens, error)
+	Register(ctx context.Context, username, password string) (*models.AuthTokens, error)
+	Login(ctx context.Context, username, password, clientIP string) (*models.AuthTokens, error)
+	ChangePassword(ctx context.Context, userID int, oldPassword, newPassword string) (*models.AuthTokens, error)
+	VerifyTwoFactor(ctx context.Context, challengeToken, code string) (*models.AuthTokens, error)
+	EnrollTOTP(ctx context.Context, userID int) (*models.TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, userID int, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, userID int, password, code string) error
+	RefreshToken(ctx context.Context, refreshToken string) (*models.AuthTokens, error)
+	Logout(ctx context.Context, claims *jwt.Claims) error
+	ListSessions(ctx context.Context, userID int) ([]*models.Session, error)
+	RevokeSession(ctx context.Context, userID int, sessionID string) error
+	RevokeAllSessions(ctx context.Context, userID int, keepSessionID string) (int, error)
+	TouchSession(ctx context.Context, sessionID string)
+	PurchaseMerch(ctx context.Context, userID int, merchName, currency string) error
+	TransferCoins(ctx context.Context, fromUser, toUser int, currency string, amount int) error
+	TransferCoinsBatch(ctx context.Context, fromUser int, currency string, transfers []models.TransferItem) (int, error)
 	GetInfo(ctx context.Context, userID int) (*models.UserInfo, error)
+	GetGiftAllowance(ctx context.Context, userID int) (*models.GiftAllowance, error)
+	ResetGiftAllowances(ctx context.Context, now time.Time) (int, error)
+	ListTransactions(ctx context.Context, filter *models.TransactionFilter, cursor string) ([]*models.Transaction, string, error)
+	ListPurchases(ctx context.Context, filter *models.PurchaseFilter, cursor string) ([]*models.Purchase, string, error)
+	ExportHistory(ctx context.Context, userID int, from, to *time.Time, fn func(*models.HistoryRecord) error) error
+	GetStatement(ctx context.Context, userID int, period time.Time) (*models.Statement, error)
+	GenerateStatements(ctx context.Context, now time.Time, batchSize int) (int, error)
+	GetExpiringCoins(ctx context.Context, userID int) ([]*models.CoinLot, error)
+	ExpireCoins(ctx context.Context, now time.Time, batchSize int) (int, error)
+	GrantCoins(ctx context.Context, adminID int, username string, amount int, reason string) (*models.CoinGrant, error)
+	GrantCoinsBulk(ctx context.Context, adminID int, csvData string) (*models.GrantBatch, error)
+	ReverseTransaction(ctx context.Context, adminID, transactionID int, reason string) (*models.TransactionReversal, error)
+	SetUserRoles(ctx context.Context, adminID int, username string, roles []string) (*models.User, error)
+	UnlockLogin(ctx context.Context, adminID int, username string) error
+	QueryAuditLog(ctx context.Context, filter *models.AuditFilter, cursor string) ([]*models.AuditEvent, string, error)
+	CreateServiceAccount(ctx context.Context, adminID int, username string, roles []string) (*models.User, error)
+	CreateAPIKey(ctx context.Context, adminID int, username, name string, scopes []string, expiresInDays int) (*models.APIKey, string, error)
+	ListAPIKeys(ctx context.Context, username string) ([]*models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, adminID, keyID int) error
+	AuthenticateAPIKey(ctx context.Context, key string) (*models.Principal, error)
+	AuthenticateClientCert(ctx context.Context, identities []string) (*models.Principal, error)
+	BeginOIDCLogin(ctx context.Context) (*models.OIDCLogin, error)
+	LoginWithOIDC(ctx context.Context, rawIDToken, loginHandle string) (*models.AuthTokens, error)
+	RequestCoins(ctx context.Context, requesterID, payerID, amount int, note string) (*models.CoinRequest, error)
+	ListCoinRequests(ctx context.Context, userID int, direction, status string) ([]*models.CoinRequest, error)
+	ApproveCoinRequest(ctx context.Context, payerID, requestID int) (*models.CoinRequest, error)
+	RejectCoinRequest(ctx context.Context, payerID, requestID int) (*models.CoinRequest, error)
+	ExpireCoinRequests(ctx context.Context, now time.Time) (int, error)
 }
 
 type merchStoreServiceImp struct {
@@ -29,7 +73,19 @@ type merchStoreServiceImp struct {
 	txManager      db.TxManager
 	tokenService   jwt.TokenService
 	passwordHasher password.PasswordHasher
+	passwordPolicy *password.Policy
+	dummyHash      string
 	initialBalance int
+	auth           config.AuthConfig
+	loginLimit     config.LoginLimitConfig
+	twoFactor      config.TwoFactorConfig
+	apiKeys        config.APIKeysConfig
+	oidc           *oidc.Provider
+	oidcConfig     config.OIDCConfig
+	clientCerts    []config.ClientCertConfig
+	coins          config.CoinsConfig
+	currencies     []config.CurrencyConfig
+	allowance      config.AllowanceConfig
 	log            logger.Logger
 }
 
@@ -39,31 +95,66 @@ func NewMerchStoreService(
 	txManager db.TxManager,
 	tokenService jwt.TokenService,
 	passwordHasher password.PasswordHasher,
+	passwordPolicy *password.Policy,
 	initialBalance int,
+	auth config.AuthConfig,
+	loginLimit config.LoginLimitConfig,
+	twoFactor config.TwoFactorConfig,
+	apiKeys config.APIKeysConfig,
+	oidcProvider *oidc.Provider,
+	oidcConfig config.OIDCConfig,
+	clientCerts []config.ClientCertConfig,
+	coins config.CoinsConfig,
+	currencies []config.CurrencyConfig,
+	allowance config.AllowanceConfig,
 	log logger.Logger,
 ) MerchStoreService {
+	// Хэш, с которым сравнивается пароль неизвестного пользователя: так ответ занимает
+	// столько же времени, сколько для существующего. Считается текущим алгоритмом.
+	dummyHash, err := passwordHasher.Hash("dummy-password-for-unknown-users")
+	if err != nil {
+		log.Errorw("hash dummy password", "error", err)
+	}
+
 	return &merchStoreServiceImp{
 		repo:           repo,
 		cacheRepo:      cacheRepo,
 		txManager:      txManager,
 		tokenService:   tokenService,
 		passwordHasher: passwordHasher,
+		passwordPolicy: passwordPolicy,
+		dummyHash:      dummyHash,
 		initialBalance: initialBalance,
+		auth:           auth,
+		loginLimit:     loginLimit,
+		twoFactor:      twoFactor,
+		apiKeys:        apiKeys,
+		oidc:           oidcProvider,
+		oidcConfig:     oidcConfig,
+		clientCerts:    clientCerts,
+		coins:          coins,
+		currencies:     newCurrencyList(initialBalance, currencies),
+		allowance:      allowance,
 		log:            log,
 	}
 }
 
-func (s *merchStoreServiceImp) Authenticate(ctx context.Context, username, password string) (string, error) {
-	user, err := s.getOrCreateUser(ctx, username, password)
-	if err != nil {
-		return "", err
+// Authenticate — прежний вход по /api/auth. При auth.auto_register неизвестный
+// username регистрируется автоматически, иначе работает как Login.
+func (s *merchStoreServiceImp) Authenticate(ctx context.Context, username, password, clientIP string) (*models.AuthTokens, error) {
+	if !s.auth.AutoRegister {
+		return s.Login(ctx, username, password, clientIP)
+	}
+	if err := s.checkLoginAllowed(ctx, username, clientIP); err != nil {
+		s.auditThrottledLogin(ctx, username, err)
+		return nil, err
 	}
 
-	token, err := s.tokenService.GenerateToken(user.ID)
+	user, err := s.getOrCreateUser(ctx, username, password)
 	if err != nil {
-		return "", fmt.Errorf("generate token: %w", err)
+		return nil, err
 	}
-	return token, nil
+	return s.completeLogin(ctx, user, "password")
 }
 
 func (s *merchStoreServiceImp) getOrCreateUser(ctx context.Context, username, password string) (*models.User, error) {
@@ -73,24 +164,24 @@ func (s *merchStoreServiceImp) getOrCreateUser(ctx context.Context, username, pa
 	}
 
 	if user == nil {
+		if err := validateUsername(username); err != nil {
+			return nil, err
+		}
+		if err := s.validatePassword(password, username); err != nil {
+			return nil, err
+		}
 		return s.createNewUser(ctx, username, password)
 	}
 
 	if !s.passwordHasher.Check(user.PasswordHash, password) {
-		return nil, errors.New("invalid credentials")
-	}
-
-	_, err = s.cacheRepo.GetBalance(ctx, user.ID)
-	if err != nil {
-		if errors.Is(err, redis.Nil) {
-			if setErr := s.cacheRepo.SetBalance(ctx, user.ID, user.Balance); setErr != nil {
-				s.log.Errorw("set balance in cache", "userID", user.ID, "error", setErr)
-			}
-		} else {
-			s.log.Errorw("get balance from cache", "userID", user.ID, "error", err)
-		}
+		s.recordLoginFailure(ctx, username)
+		s.auditLoginFailure(ctx, user.ID, username, loginFailureInvalidPassword)
+		return nil, ErrInvalidCredentials
 	}
+	s.recordLoginSuccess(ctx, username)
+	s.upgradePasswordHash(ctx, user, password)
 
+	s.warmCache(ctx, user)
 	return user, nil
 }
 
@@ -100,36 +191,83 @@ func (s *merchStoreServiceImp) createNewUser(ctx context.Context, username, pass
 		return nil, fmt.Errorf("hash password: %w", err)
 	}
 
+	var user *models.User
+	err = s.txManager.WithTx(ctx, pgx.ReadCommitted, pgx.ReadWrite, func(txCtx context.Context) error {
+		var err error
+		user, err = s.createUserInTx(txCtx, username, hashedPwd)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cacheNewUser(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// createUserInTx создаёт пользователя с начальными балансами. Пустой passwordHash —
+// пользователь без пароля (вход только через SSO).
+func (s *merchStoreServiceImp) createUserInTx(txCtx context.Context, username, passwordHash string) (*models.User, error) {
 	newUser := &models.User{
 		Username:     username,
-		PasswordHash: hashedPwd,
+		PasswordHash: passwordHash,
 		Balance:      s.initialBalance,
 		CreatedAt:    time.Now(),
 	}
 
-	userID, err := s.repo.CreateUser(ctx, newUser)
+	userID, err := s.repo.CreateUser(txCtx, newUser)
 	if err != nil {
 		return nil, err
 	}
+	newUser.ID = userID
 
-	if err := s.cacheRepo.SetBalance(ctx, userID, s.initialBalance); err != nil {
-		return nil, fmt.Errorf("set balance in cache: %w", err)
+	for _, c := range s.currencies[1:] {
+		if c.InitialBalance <= 0 {
+			continue
+		}
+		if err := s.repo.AddBalance(txCtx, userID, c.Code, c.InitialBalance); err != nil {
+			return nil, err
+		}
+	}
+	if s.initialBalance > 0 {
+		lot := s.newCoinLot(userID, models.CoinLotSourceInitial, s.initialBalance, newUser.CreatedAt)
+		if _, err := s.repo.CreateCoinLot(txCtx, lot); err != nil {
+			return nil, err
+		}
 	}
 
-	return &models.User{
-		ID:           userID,
-		Username:     username,
-		PasswordHash: hashedPwd,
-		Balance:      s.initialBalance,
-	}, nil
+	return newUser, nil
+}
+
+// cacheNewUser кладёт в кэш начальные балансы только что созданного пользователя.
+func (s *merchStoreServiceImp) cacheNewUser(ctx context.Context, userID int) error {
+	if err := s.cacheRepo.SetBalance(ctx, userID, s.initialBalance); err != nil {
+		return fmt.Errorf("set balance in cache: %w", err)
+	}
+	for _, c := range s.currencies[1:] {
+		if err := s.cacheRepo.SetCurrencyBalance(ctx, userID, c.Code, c.InitialBalance); err != nil {
+			return fmt.Errorf("set balance in cache: %w", err)
+		}
+	}
+	return nil
 }
 
-func (s *merchStoreServiceImp) PurchaseMerch(ctx context.Context, userID int, merchName string) error {
+func (s *merchStoreServiceImp) PurchaseMerch(ctx context.Context, userID int, merchName, currency string) error {
+	cur, err := s.resolveCurrency(currency)
+	if err != nil {
+		return err
+	}
+	if !cur.Spendable {
+		return fmt.Errorf("%w: %s", ErrCurrencyNotSpendable, cur.Code)
+	}
+
 	price, err := s.cacheRepo.GetPrice(ctx, merchName)
 	if err != nil {
 		return fmt.Errorf("get merch price: %w", err)
 	}
-	currentBalance, err := s.cacheRepo.GetBalance(ctx, userID)
+	currentBalance, err := s.cachedBalance(ctx, userID, cur.Code)
 	if err != nil {
 		return fmt.Errorf("get balance: %w", err)
 	}
@@ -138,39 +276,49 @@ func (s *merchStoreServiceImp) PurchaseMerch(ctx context.Context, userID int, me
 	}
 
 	err = s.txManager.WithTx(ctx, pgx.Serializable, pgx.ReadWrite, func(txCtx context.Context) error {
-		user, err := s.repo.GetUserByID(txCtx, userID)
+		before, err := s.balanceInTx(txCtx, userID, cur.Code)
 		if err != nil {
 			return err
 		}
-		if user.Balance < price {
-			return errors.New("insufficient funds (DB)")
-		}
-		newBalance := user.Balance - price
-		if err := s.repo.UpdateBalance(txCtx, userID, newBalance); err != nil {
+		if err := s.debitInTx(txCtx, userID, cur.Code, price); err != nil {
 			return err
 		}
 		purchase := &models.Purchase{
 			UserID:    userID,
 			MerchName: merchName,
 			Price:     price,
+			Currency:  cur.Code,
 			CreatedAt: time.Now(),
 		}
 
-		if _, err := s.repo.CreatePurchase(txCtx, purchase); err != nil {
+		purchaseID, err := s.repo.CreatePurchase(txCtx, purchase)
+		if err != nil {
 			return err
 		}
-		return nil
+
+		after := before - price
+		return s.auditInTx(txCtx, &models.AuditEvent{
+			EventType:     models.AuditPurchase,
+			ActorID:       userID,
+			TargetID:      userID,
+			Currency:      cur.Code,
+			Amount:        price,
+			BalanceBefore: &before,
+			BalanceAfter:  &after,
+			Details:       map[string]string{"purchase_id": strconv.Itoa(purchaseID), "merch_name": merchName},
+			CreatedAt:     purchase.CreatedAt,
+		})
 	})
 	if err != nil {
 		return err
 	}
-	if err := s.cacheRepo.DeductBalance(ctx, userID, price); err != nil {
+	if err := s.cacheRepo.DeductCurrencyBalance(ctx, userID, cur.Code, price); err != nil {
 		return fmt.Errorf("purchase succeeded but failed to update cache: %w", err)
 	}
 	return nil
 }
 
-func (s *merchStoreServiceImp) TransferCoins(ctx context.Context, fromUser, toUser, amount int) error {
+func (s *merchStoreServiceImp) TransferCoins(ctx context.Context, fromUser, toUser int, currency string, amount int) error {
 	if amount <= 0 {
 		return fmt.Errorf("transfer amount: amount must be positive")
 	}
@@ -178,54 +326,118 @@ func (s *merchStoreServiceImp) TransferCoins(ctx context.Context, fromUser, toUs
 	if fromUser == toUser {
 		return fmt.Errorf("cannot transfer to yourself")
 	}
-	senderBalance, err := s.cacheRepo.GetBalance(ctx, fromUser)
+	cur, err := s.resolveCurrency(currency)
+	if err != nil {
+		return err
+	}
+	senderBalance, err := s.cachedBalance(ctx, fromUser, cur.Code)
 	if err != nil {
 		return fmt.Errorf("get sender balance: %w", err)
 	}
+	if cur.Code == models.CurrencyCoin {
+		allowance, err := s.GetGiftAllowance(ctx, fromUser)
+		if err != nil {
+			return fmt.Errorf("get gift allowance: %w", err)
+		}
+		senderBalance += allowance.Balance
+	}
 	if senderBalance < amount {
 		return errors.New("insufficient funds for transfer")
 	}
 
+	var txRecord *models.Transaction
 	err = s.txManager.WithTx(ctx, pgx.Serializable, pgx.ReadWrite, func(txCtx context.Context) error {
-		sender, err := s.repo.GetUserByID(txCtx, fromUser)
-		if err != nil {
-			return err
-		}
-		if sender.Balance < amount {
-			return errors.New("insufficient funds in DB for sender")
-		}
-		receiver, err := s.repo.GetUserByID(txCtx, toUser)
-		if err != nil {
-			return err
-		}
-		newSenderBal := sender.Balance - amount
-		newReceiverBal := receiver.Balance + amount
-		if err := s.repo.UpdateBalance(txCtx, fromUser, newSenderBal); err != nil {
-			return err
-		}
-		if err := s.repo.UpdateBalance(txCtx, toUser, newReceiverBal); err != nil {
-			return err
-		}
-		txRecord := &models.Transaction{
-			SenderID:   fromUser,
-			ReceiverID: toUser,
-			Amount:     amount,
-			CreatedAt:  time.Now(),
-		}
-		if _, err := s.repo.CreateTransaction(txCtx, txRecord); err != nil {
-			return err
-		}
-		return nil
+		var err error
+		txRecord, err = s.transferInTx(txCtx, fromUser, toUser, cur.Code, amount)
+		return err
 	})
 	if err != nil {
 		return err
 	}
-	if err := s.cacheRepo.TransferCoins(ctx, fromUser, toUser, amount); err != nil {
+	if err := s.transferInCache(ctx, txRecord); err != nil {
 		return fmt.Errorf("transfer succeeded but failed to update cache: %w", err)
 	}
 	return nil
 }
 
+// transferInTx переводит amount в валюте currency между пользователями внутри уже открытой
+// транзакции. Монеты сначала берутся из лимита на подарки отправителя, затем с его баланса;
+// получатель всегда получает их на обычный баланс.
+func (s *merchStoreServiceImp) transferInTx(txCtx context.Context, fromUser, toUser int, currency string, amount int) (*models.Transaction, error) {
+	return s.transferWithDetailsInTx(txCtx, fromUser, toUser, currency, amount, nil)
+}
+
+// transferWithDetailsInTx — transferInTx с дополнительными полями details в журнале аудита.
+func (s *merchStoreServiceImp) transferWithDetailsInTx(txCtx context.Context, fromUser, toUser int, currency string, amount int, extra map[string]string) (*models.Transaction, error) {
+	if _, err := s.repo.GetUserByID(txCtx, toUser); err != nil {
+		return nil, err
+	}
+	txRecord := &models.Transaction{
+		SenderID:   fromUser,
+		ReceiverID: toUser,
+		Amount:     amount,
+		Currency:   currency,
+		CreatedAt:  time.Now(),
+	}
+
+	if currency == models.CurrencyCoin {
+		allowance, err := s.allowanceInTx(txCtx, fromUser, txRecord.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if fromAllowance := min(allowance, amount); fromAllowance > 0 {
+			if err := s.repo.SetAllowance(txCtx, fromUser, allowance-fromAllowance, allowancePeriod(txRecord.CreatedAt)); err != nil {
+				return nil, err
+			}
+			txRecord.AllowanceAmount = fromAllowance
+		}
+	}
+
+	before, err := s.balanceInTx(txCtx, fromUser, currency)
+	if err != nil {
+		return nil, err
+	}
+	rest := amount - txRecord.AllowanceAmount
+	if rest > 0 {
+		if err := s.debitInTx(txCtx, fromUser, currency, rest); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.creditInTx(txCtx, toUser, currency, amount, models.CoinLotSourceTransfer, txRecord.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	id, err := s.repo.CreateTransaction(txCtx, txRecord)
+	if err != nil {
+		return nil, err
+	}
+	txRecord.ID = id
+
+	after := before - max(rest, 0)
+	details := map[string]string{
+		"transaction_id":   strconv.Itoa(id),
+		"allowance_amount": strconv.Itoa(txRecord.AllowanceAmount),
+	}
+	for k, v := range extra {
+		details[k] = v
+	}
+	err = s.auditInTx(txCtx, &models.AuditEvent{
+		EventType:     models.AuditTransfer,
+		ActorID:       fromUser,
+		TargetID:      toUser,
+		Currency:      currency,
+		Amount:        amount,
+		BalanceBefore: &before,
+		BalanceAfter:  &after,
+		Details:       details,
+		CreatedAt:     txRecord.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return txRecord, nil
+}
+
 func (s *merchStoreServiceImp) GetInfo(ctx context.Context, userID int) (*models.UserInfo, error) {
 	var result *models.UserInfo
 
@@ -245,12 +457,51 @@ func (s *merchStoreServiceImp) GetInfo(ctx context.Context, userID int) (*models
 			return err
 		}
 
+		inventory, err := s.repo.GetInventoryByUserID(txCtx, userID)
+		if err != nil {
+			return err
+		}
+
+		received, err := s.repo.GetReceivedByCounterparty(txCtx, userID)
+		if err != nil {
+			return err
+		}
+
+		sent, err := s.repo.GetSentByCounterparty(txCtx, userID)
+		if err != nil {
+			return err
+		}
+
+		allowance, err := s.GetGiftAllowance(txCtx, userID)
+		if err != nil {
+			return err
+		}
+
+		extra, err := s.repo.GetBalancesByUserID(txCtx, userID)
+		if err != nil {
+			return err
+		}
+		balances := make([]*models.CurrencyBalance, 0, len(s.currencies))
+		for _, c := range s.currencies {
+			balance := extra[c.Code]
+			if c.Code == models.CurrencyCoin {
+				balance = user.Balance
+			}
+			balances = append(balances, &models.CurrencyBalance{Currency: c.Code, Balance: balance, Spendable: c.Spendable})
+		}
+
 		result = &models.UserInfo{
 			UserID:       user.ID,
 			Username:     user.Username,
+			Roles:        user.Roles,
 			Balance:      user.Balance,
+			Balances:     balances,
+			Allowance:    allowance,
 			Purchases:    purchases,
 			Transactions: transactions,
+			Inventory:    inventory,
+			Received:     received,
+			Sent:         sent,
 		}
 		return nil
 	})
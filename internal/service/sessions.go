@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/models"
+	"strconv"
+	"time"
+)
+
+const (
+	maxUserAgentLength   = 256
+	sessionTouchInterval = time.Minute
+)
+
+type clientInfoKey struct{}
+
+// WithClientInfo сохраняет в контексте адрес и User-Agent клиента: они записываются
+// в сессию, открытую при входе.
+func WithClientInfo(ctx context.Context, info *models.ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// ClientInfoFromContext возвращает данные клиента, сохранённые WithClientInfo, или пустые.
+func ClientInfoFromContext(ctx context.Context) *models.ClientInfo {
+	if info, ok := ctx.Value(clientInfoKey{}).(*models.ClientInfo); ok {
+		return info
+	}
+	return &models.ClientInfo{}
+}
+
+func clientInfo(ctx context.Context) *models.ClientInfo {
+	info := *ClientInfoFromContext(ctx)
+	if len(info.UserAgent) > maxUserAgentLength {
+		info.UserAgent = info.UserAgent[:maxUserAgentLength]
+	}
+	return &info
+}
+
+// TouchSession обновляет last_seen_at, адрес и User-Agent сессии при запросе с access-токеном,
+// не чаще раза в минуту. Ошибки только логируются: запрос они не прерывают.
+func (s *merchStoreServiceImp) TouchSession(ctx context.Context, sessionID string) {
+	acquired, err := s.cacheRepo.AcquireSessionTouch(ctx, sessionID, sessionTouchInterval)
+	if err != nil {
+		s.log.Errorw("throttle session touch", "sessionID", sessionID, "error", err)
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	client := clientInfo(ctx)
+	if err := s.repo.TouchSession(ctx, sessionID, time.Now(), client.IP, client.UserAgent); err != nil {
+		s.log.Errorw("touch session", "sessionID", sessionID, "error", err)
+	}
+}
+
+// ListSessions возвращает действующие сессии пользователя.
+func (s *merchStoreServiceImp) ListSessions(ctx context.Context, userID int) ([]*models.Session, error) {
+	return s.repo.ListActiveSessions(ctx, userID, time.Now())
+}
+
+// RevokeSession завершает одну сессию пользователя: её refresh-токены отзываются,
+// а access-токены перестают приниматься сразу, не дожидаясь истечения срока.
+func (s *merchStoreServiceImp) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	session, err := s.repo.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	// Чужая сессия неотличима от несуществующей.
+	if session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+
+	var revoked []*models.RefreshToken
+	err = s.txManager.WithTx(ctx, pgx.ReadCommitted, pgx.ReadWrite, func(txCtx context.Context) error {
+		var err error
+		revoked, err = s.repo.RevokeSession(txCtx, sessionID, time.Now())
+		if err != nil {
+			return err
+		}
+		return s.auditInTx(txCtx, &models.AuditEvent{
+			EventType: models.AuditSessionRevoke,
+			ActorID:   userID,
+			TargetID:  userID,
+			Details:   map[string]string{"session_id": sessionID},
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.revokeAccessTokens(ctx, revoked); err != nil {
+		return err
+	}
+
+	s.log.Infow("Session revoked", "userID", userID, "sessionID", sessionID)
+	return nil
+}
+
+// RevokeAllSessions завершает все сессии пользователя, кроме keepSessionID (если задан),
+// и возвращает их число.
+func (s *merchStoreServiceImp) RevokeAllSessions(ctx context.Context, userID int, keepSessionID string) (int, error) {
+	var (
+		revoked []*models.RefreshToken
+		count   int
+	)
+
+	err := s.txManager.WithTx(ctx, pgx.ReadCommitted, pgx.ReadWrite, func(txCtx context.Context) error {
+		now := time.Now()
+		sessions, err := s.repo.ListActiveSessions(txCtx, userID, now)
+		if err != nil {
+			return err
+		}
+		for _, session := range sessions {
+			if session.ID == keepSessionID {
+				continue
+			}
+			tokens, err := s.repo.RevokeSession(txCtx, session.ID, now)
+			if err != nil {
+				return err
+			}
+			revoked = append(revoked, tokens...)
+			count++
+		}
+		return s.auditInTx(txCtx, &models.AuditEvent{
+			EventType: models.AuditSessionRevoke,
+			ActorID:   userID,
+			TargetID:  userID,
+			Details: map[string]string{
+				"all":          "true",
+				"sessions":     strconv.Itoa(count),
+				"kept_current": strconv.FormatBool(keepSessionID != ""),
+			},
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.revokeAccessTokens(ctx, revoked); err != nil {
+		return 0, fmt.Errorf("sessions revoked but failed to revoke access tokens: %w", err)
+	}
+
+	s.log.Infow("All sessions revoked", "userID", userID, "sessions", count, "keptCurrent", keepSessionID != "")
+	return count, nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db/postgres"
+	"time"
+)
+
+const statementPeriodLayout = "2006-01"
+
+// ParseStatementPeriod разбирает период выписки в формате YYYY-MM и возвращает
+// начало месяца в UTC.
+func ParseStatementPeriod(period string) (time.Time, error) {
+	t, err := time.Parse(statementPeriodLayout, period)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: period must be in YYYY-MM format", ErrInvalidArgument)
+	}
+	return t.UTC(), nil
+}
+
+// GetStatement возвращает выписку за календарный месяц. Для прошедших месяцев
+// используется сохранённая выписка, если она уже сгенерирована.
+func (s *merchStoreServiceImp) GetStatement(ctx context.Context, userID int, period time.Time) (*models.Statement, error) {
+	now := time.Now().UTC()
+	if period.After(now) {
+		return nil, fmt.Errorf("%w: period is in the future", ErrInvalidArgument)
+	}
+
+	periodEnd := period.AddDate(0, 1, 0)
+	if !periodEnd.After(now) {
+		stored, err := s.repo.GetStatement(ctx, userID, period)
+		if err == nil {
+			return stored, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	return s.buildStatement(ctx, userID, period)
+}
+
+// buildStatement считает выписку из покупок, переводов, начислений и сгораний.
+// Остаток на конец периода выводится из текущего users.balance за вычетом движений
+// после периода, поэтому выписка всегда сходится с балансом пользователя.
+func (s *merchStoreServiceImp) buildStatement(ctx context.Context, userID int, period time.Time) (*models.Statement, error) {
+	periodEnd := period.AddDate(0, 1, 0)
+	st := &models.Statement{
+		UserID: userID,
+		Period: period,
+		Totals: make(map[string]int),
+	}
+
+	err := s.txManager.WithTx(ctx, postgres.IsolationLevelRepeatableRead, postgres.AccessModeReadOnly, func(txCtx context.Context) error {
+		user, err := s.repo.GetUserByID(txCtx, userID)
+		if err != nil {
+			return err
+		}
+
+		after, err := s.repo.GetNetMovementSince(txCtx, userID, periodEnd)
+		if err != nil {
+			return err
+		}
+
+		movements, err := s.repo.GetMovements(txCtx, userID, period, periodEnd)
+		if err != nil {
+			return err
+		}
+
+		st.ClosingBalance = user.Balance - after
+		st.OpeningBalance = st.ClosingBalance
+		for _, m := range movements {
+			st.OpeningBalance -= m.Amount
+			st.Totals[m.Category] += m.Amount
+		}
+
+		// Стартовые монеты не хранятся отдельной операцией: если пользователь
+		// зарегистрирован в этом периоде, показываем их как первое движение.
+		createdAt := user.CreatedAt.UTC()
+		if !createdAt.Before(period) && createdAt.Before(periodEnd) && st.OpeningBalance != 0 {
+			initial := &models.StatementMovement{
+				Category:  models.MovementInitial,
+				CreatedAt: createdAt,
+				Amount:    st.OpeningBalance,
+			}
+			movements = append([]*models.StatementMovement{initial}, movements...)
+			st.Totals[models.MovementInitial] += st.OpeningBalance
+			st.OpeningBalance = 0
+		}
+
+		st.Movements = movements
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	st.GeneratedAt = time.Now().UTC()
+	return st, nil
+}
+
+// GenerateStatements сохраняет выписки за прошлый месяц для всех пользователей,
+// у которых их ещё нет. Возвращает количество обработанных пользователей.
+func (s *merchStoreServiceImp) GenerateStatements(ctx context.Context, now time.Time, batchSize int) (int, error) {
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	period := current.AddDate(0, -1, 0)
+
+	processed := 0
+	afterID := 0
+	for {
+		users, err := s.repo.ListUsers(ctx, afterID, batchSize)
+		if err != nil {
+			return processed, err
+		}
+		if len(users) == 0 {
+			break
+		}
+		afterID = users[len(users)-1].ID
+
+		for _, u := range users {
+			if !u.CreatedAt.UTC().Before(current) {
+				continue
+			}
+			if _, err := s.repo.GetStatement(ctx, u.ID, period); err == nil {
+				continue
+			} else if !errors.Is(err, pgx.ErrNoRows) {
+				return processed, err
+			}
+
+			st, err := s.buildStatement(ctx, u.ID, period)
+			if err != nil {
+				return processed, fmt.Errorf("build statement for user %d: %w", u.ID, err)
+			}
+			if err := s.repo.SaveStatement(ctx, st); err != nil {
+				return processed, err
+			}
+			processed++
+		}
+	}
+
+	if processed > 0 {
+		s.log.Infow("Generated statements",
+			"period", period.Format(statementPeriodLayout),
+			"count", processed,
+		)
+	}
+
+	return processed, nil
+}
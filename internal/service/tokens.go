@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/pkg/jwt"
+	"time"
+)
+
+const defaultRefreshTokenExpiry = 30 * 24 * time.Hour
+
+// issueTokens открывает новую сессию и выдаёт пару access/refresh. Устройство и адрес
+// сессии берутся из контекста запроса (см. WithClientInfo).
+func (s *merchStoreServiceImp) issueTokens(ctx context.Context, userID int) (*models.AuthTokens, error) {
+	sessionID, err := jwt.NewTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	client := clientInfo(ctx)
+	now := time.Now()
+	session := &models.Session{
+		ID:         sessionID,
+		UserID:     userID,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.refreshTokenExpiry()),
+	}
+
+	var tokens *models.AuthTokens
+	err = s.txManager.WithTx(ctx, pgx.ReadCommitted, pgx.ReadWrite, func(txCtx context.Context) error {
+		if err := s.repo.CreateSession(txCtx, session); err != nil {
+			return err
+		}
+		var err error
+		tokens, _, err = s.createSessionTokens(txCtx, userID, sessionID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (s *merchStoreServiceImp) createSessionTokens(ctx context.Context, userID int, sessionID string) (*models.AuthTokens, *models.RefreshToken, error) {
+	// Роли читаются при каждой выдаче токена, поэтому их изменение вступает в силу со следующим refresh.
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	access, claims, err := s.tokenService.GenerateToken(userID, sessionID, user.Roles)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate token: %w", err)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, nil, fmt.Errorf("generate refresh token: %w", err)
+	}
+	refresh := base64.RawURLEncoding.EncodeToString(raw)
+
+	expiry := s.refreshTokenExpiry()
+	now := time.Now()
+	record := &models.RefreshToken{
+		UserID:          userID,
+		SessionID:       sessionID,
+		TokenHash:       hashRefreshToken(refresh),
+		AccessJTI:       claims.ID,
+		AccessExpiresAt: claims.ExpiresAt,
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(expiry),
+	}
+	record.ID, err = s.repo.CreateRefreshToken(ctx, record)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &models.AuthTokens{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresAt:    claims.ExpiresAt,
+	}, record, nil
+}
+
+func (s *merchStoreServiceImp) refreshTokenExpiry() time.Duration {
+	if s.auth.RefreshTokenExpiry > 0 {
+		return time.Duration(s.auth.RefreshTokenExpiry) * time.Second
+	}
+	return defaultRefreshTokenExpiry
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RefreshToken обменивает refresh-токен на новую пару (ротация). Повторное предъявление
+// уже использованного токена означает его утечку: вся сессия отзывается.
+func (s *merchStoreServiceImp) RefreshToken(ctx context.Context, refreshToken string) (*models.AuthTokens, error) {
+	if refreshToken == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	var (
+		tokens  *models.AuthTokens
+		revoked []*models.RefreshToken
+		reused  bool
+	)
+
+	err := s.txManager.WithTx(ctx, pgx.ReadCommitted, pgx.ReadWrite, func(txCtx context.Context) error {
+		current, err := s.repo.GetRefreshTokenForUpdate(txCtx, hashRefreshToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrInvalidCredentials
+			}
+			return err
+		}
+
+		now := time.Now()
+		if current.RevokedAt != nil {
+			reused = true
+			revoked, err = s.repo.RevokeSession(txCtx, current.SessionID, now)
+			return err
+		}
+		if !now.Before(current.ExpiresAt) {
+			return ErrInvalidCredentials
+		}
+
+		var next *models.RefreshToken
+		tokens, next, err = s.createSessionTokens(txCtx, current.UserID, current.SessionID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.ExtendSession(txCtx, current.SessionID, now, next.ExpiresAt); err != nil {
+			return err
+		}
+		return s.repo.RevokeRefreshToken(txCtx, current.ID, &next.ID, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if reused {
+		s.log.Warnw("Refresh token reuse detected, session revoked", "revokedTokens", len(revoked))
+		if err := s.revokeAccessTokens(ctx, revoked); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	return tokens, nil
+}
+
+// Logout отзывает текущий access-токен и всю его сессию.
+func (s *merchStoreServiceImp) Logout(ctx context.Context, claims *jwt.Claims) error {
+	if claims.ID != "" {
+		if err := s.cacheRepo.RevokeToken(ctx, claims.ID, time.Until(claims.ExpiresAt)); err != nil {
+			return fmt.Errorf("revoke token: %w", err)
+		}
+	}
+	if claims.SessionID == "" {
+		return nil
+	}
+
+	revoked, err := s.repo.RevokeSession(ctx, claims.SessionID, time.Now())
+	if err != nil {
+		return err
+	}
+	return s.revokeAccessTokens(ctx, revoked)
+}
+
+// revokeAccessTokens заносит в список отзыва access-токены, выданные вместе с refresh-токенами,
+// и сами их сессии: access-токены, выданные до последнего обновления, тоже перестают действовать.
+func (s *merchStoreServiceImp) revokeAccessTokens(ctx context.Context, tokens []*models.RefreshToken) error {
+	sessions := make(map[string]time.Time)
+	for _, t := range tokens {
+		if err := s.cacheRepo.RevokeToken(ctx, t.AccessJTI, time.Until(t.AccessExpiresAt)); err != nil {
+			return fmt.Errorf("revoke token: %w", err)
+		}
+		if t.AccessExpiresAt.After(sessions[t.SessionID]) {
+			sessions[t.SessionID] = t.AccessExpiresAt
+		}
+	}
+	for sessionID, expiresAt := range sessions {
+		if err := s.cacheRepo.RevokeSession(ctx, sessionID, time.Until(expiresAt)); err != nil {
+			return fmt.Errorf("revoke session: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/models"
+	"sort"
+	"time"
+)
+
+const maxBatchRecipients = 100
+
+// TransferCoinsBatch переводит монеты или другую валюту нескольким получателям атомарно.
+// Строки пользователей блокируются в порядке возрастания id, после чего баланс отправителя
+// (для coin — вместе с лимитом на подарки) проверяется один раз на общую сумму.
+func (s *merchStoreServiceImp) TransferCoinsBatch(ctx context.Context, fromUser int, currency string, transfers []models.TransferItem) (int, error) {
+	cur, err := s.resolveCurrency(currency)
+	if err != nil {
+		return 0, err
+	}
+	if len(transfers) == 0 {
+		return 0, fmt.Errorf("%w: no recipients", ErrInvalidArgument)
+	}
+	if len(transfers) > maxBatchRecipients {
+		return 0, fmt.Errorf("%w: too many recipients, max %d", ErrInvalidArgument, maxBatchRecipients)
+	}
+
+	amounts := make(map[int]int, len(transfers))
+	total := 0
+	for _, t := range transfers {
+		if t.Amount <= 0 {
+			return 0, fmt.Errorf("%w: amount for user %d must be positive", ErrInvalidArgument, t.ToUser)
+		}
+		if t.ToUser == fromUser {
+			return 0, fmt.Errorf("%w: cannot transfer to yourself", ErrInvalidArgument)
+		}
+		if _, dup := amounts[t.ToUser]; dup {
+			return 0, fmt.Errorf("%w: duplicate recipient %d", ErrInvalidArgument, t.ToUser)
+		}
+		amounts[t.ToUser] = t.Amount
+		total += t.Amount
+	}
+
+	// Быстрая проверка по кэшу; окончательная — по заблокированным строкам в транзакции.
+	senderBalance, err := s.cachedBalance(ctx, fromUser, cur.Code)
+	if err != nil {
+		return 0, fmt.Errorf("get sender balance: %w", err)
+	}
+	if cur.Code == models.CurrencyCoin {
+		allowance, err := s.GetGiftAllowance(ctx, fromUser)
+		if err != nil {
+			return 0, fmt.Errorf("get gift allowance: %w", err)
+		}
+		senderBalance += allowance.Balance
+	}
+	if senderBalance < total {
+		return 0, errors.New("insufficient funds for transfer")
+	}
+
+	userIDs := make([]int, 0, len(amounts)+1)
+	userIDs = append(userIDs, fromUser)
+	for id := range amounts {
+		userIDs = append(userIDs, id)
+	}
+	sort.Ints(userIDs)
+
+	// Каждый перевод проходит через transferInTx: для coin лимит на подарки расходуется
+	// по очереди, пока не кончится, остаток списывается с баланса.
+	fromAllowance := 0
+	err = s.txManager.WithTx(ctx, pgx.Serializable, pgx.ReadWrite, func(txCtx context.Context) error {
+		users, err := s.repo.LockUsersByIDs(txCtx, userIDs)
+		if err != nil {
+			return err
+		}
+		if len(users) != len(userIDs) {
+			return fmt.Errorf("%w: one or more recipients do not exist", ErrUserNotFound)
+		}
+
+		available, err := s.balanceInTx(txCtx, fromUser, cur.Code)
+		if err != nil {
+			return err
+		}
+		if cur.Code == models.CurrencyCoin {
+			allowance, err := s.allowanceInTx(txCtx, fromUser, time.Now())
+			if err != nil {
+				return err
+			}
+			available += allowance
+		}
+		if available < total {
+			return errors.New("insufficient funds for transfer")
+		}
+
+		fromAllowance = 0
+		for _, t := range transfers {
+			txRecord, err := s.transferWithDetailsInTx(txCtx, fromUser, t.ToUser, cur.Code, t.Amount,
+				map[string]string{"batch": "true"})
+			if err != nil {
+				return err
+			}
+			fromAllowance += txRecord.AllowanceAmount
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if fromAllowance == 0 {
+		err = s.cacheRepo.TransferCurrencyBatch(ctx, cur.Code, fromUser, amounts)
+	} else {
+		deltas := make(map[int]int, len(amounts)+1)
+		for id, amount := range amounts {
+			deltas[id] = amount
+		}
+		deltas[fromUser] = fromAllowance - total
+		err = s.cacheRepo.IncrementCurrencyBalances(ctx, cur.Code, deltas)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("transfer succeeded but failed to update cache: %w", err)
+	}
+	return total, nil
+}
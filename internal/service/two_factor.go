@@ -0,0 +1,362 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/pkg/totp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTOTPIssuer        = "Merch Store"
+	defaultChallengeTTL      = 5 * time.Minute
+	defaultChallengeAttempts = 5
+	defaultRecoveryCodes     = 10
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// EnrollTOTP выдаёт новый секрет для приложения-аутентификатора. 2FA включится только
+// после ConfirmTOTP; повторный вызов до подтверждения заменяет секрет.
+func (s *merchStoreServiceImp) EnrollTOTP(ctx context.Context, userID int) (*models.TOTPEnrollment, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if user.IsServiceAccount {
+		return nil, fmt.Errorf("%w: service accounts do not use two-factor authentication", ErrPermissionDenied)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	saved, err := s.repo.SaveTOTPSecret(ctx, userID, secret, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	issuer := s.twoFactor.Issuer
+	if issuer == "" {
+		issuer = defaultTOTPIssuer
+	}
+	return &models.TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, issuer, user.Username),
+	}, nil
+}
+
+// ConfirmTOTP включает 2FA после первого верного кода и возвращает коды восстановления.
+// В открытом виде они показываются только здесь, в базе хранится SHA-256.
+func (s *merchStoreServiceImp) ConfirmTOTP(ctx context.Context, userID int, code string) ([]string, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	t, err := s.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: call enroll first", ErrTwoFactorNotEnabled)
+		}
+		return nil, err
+	}
+	if t.Enabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	if err := s.limitTwoFactorAttempts(ctx, userID); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	counter, ok := totp.Validate(t.Secret, code, now, s.twoFactor.Skew)
+	if !ok {
+		s.recordTwoFactorFailure(ctx, user, loginFailureInvalidCode)
+		return nil, fmt.Errorf("%w: invalid code", ErrInvalidArgument)
+	}
+
+	codes, hashes, err := s.newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithTx(ctx, pgx.ReadCommitted, pgx.ReadWrite, func(txCtx context.Context) error {
+		if _, err := s.repo.UseTOTPCounter(txCtx, userID, counter); err != nil {
+			return err
+		}
+		if err := s.repo.EnableTOTP(txCtx, userID, now); err != nil {
+			return err
+		}
+		if err := s.repo.ReplaceRecoveryCodes(txCtx, userID, hashes, now); err != nil {
+			return err
+		}
+		return s.auditInTx(txCtx, &models.AuditEvent{
+			EventType: models.AuditTOTPEnable,
+			ActorID:   userID,
+			TargetID:  userID,
+			CreatedAt: now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Infow("Two-factor authentication enabled", "userID", userID)
+	return codes, nil
+}
+
+// DisableTOTP отключает 2FA по текущему паролю и действующему коду или коду восстановления.
+// У пользователей без пароля (вошедших через OIDC) проверяется только код.
+func (s *merchStoreServiceImp) DisableTOTP(ctx context.Context, userID int, password, code string) error {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	t, err := s.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTwoFactorNotEnabled
+		}
+		return err
+	}
+	if !t.Enabled() {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if err := s.limitTwoFactorAttempts(ctx, userID); err != nil {
+		return err
+	}
+	if user.PasswordHash != "" && !s.passwordHasher.Check(user.PasswordHash, password) {
+		s.recordTwoFactorFailure(ctx, user, loginFailureInvalidPassword)
+		return ErrInvalidCredentials
+	}
+	if err := s.checkSecondFactor(ctx, t, code); err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			s.recordTwoFactorFailure(ctx, user, loginFailureInvalidCode)
+		}
+		return err
+	}
+	err = s.txManager.WithTx(ctx, pgx.ReadCommitted, pgx.ReadWrite, func(txCtx context.Context) error {
+		if err := s.repo.DeleteTOTP(txCtx, userID); err != nil {
+			return err
+		}
+		return s.auditInTx(txCtx, &models.AuditEvent{
+			EventType: models.AuditTOTPDisable,
+			ActorID:   userID,
+			TargetID:  userID,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.log.Infow("Two-factor authentication disabled", "userID", userID)
+	return nil
+}
+
+// completeLogin завершает вход после проверки пароля или id_token: без 2FA выдаёт токены, с 2FA —
+// одноразовый challenge, который обменивается на токены в VerifyTwoFactor.
+func (s *merchStoreServiceImp) completeLogin(ctx context.Context, user *models.User, method string) (*models.AuthTokens, error) {
+	t, err := s.repo.GetTOTP(ctx, user.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if t == nil || !t.Enabled() {
+		s.auditLogin(ctx, user.ID, method)
+		return s.issueTokens(ctx, user.ID)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("generate challenge token: %w", err)
+	}
+	challenge := base64.RawURLEncoding.EncodeToString(raw)
+
+	ttl := s.challengeTTL()
+	if err := s.cacheRepo.SaveLoginChallenge(ctx, hashChallengeToken(challenge), user.ID, ttl); err != nil {
+		return nil, fmt.Errorf("save login challenge: %w", err)
+	}
+
+	return &models.AuthTokens{
+		ChallengeToken:     challenge,
+		ChallengeExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// VerifyTwoFactor обменивает challenge и код второго фактора (TOTP или код восстановления)
+// на токены. На один challenge даётся two_factor.max_attempts попыток.
+func (s *merchStoreServiceImp) VerifyTwoFactor(ctx context.Context, challengeToken, code string) (*models.AuthTokens, error) {
+	if challengeToken == "" || code == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	challengeID := hashChallengeToken(challengeToken)
+	userID, err := s.cacheRepo.GetLoginChallenge(ctx, challengeID)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, fmt.Errorf("%w: challenge expired or already used", ErrInvalidCredentials)
+		}
+		return nil, err
+	}
+
+	attempts, _, err := s.cacheRepo.HitRateLimit(ctx, "2fa:"+challengeID, s.challengeTTL())
+	if err != nil {
+		return nil, err
+	}
+	if attempts > s.maxTwoFactorAttempts() {
+		if _, err := s.cacheRepo.DeleteLoginChallenge(ctx, challengeID); err != nil {
+			s.log.Errorw("delete login challenge", "userID", userID, "error", err)
+		}
+		return nil, ErrTooManyAttempts
+	}
+
+	t, err := s.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	if !t.Enabled() {
+		return nil, ErrInvalidCredentials
+	}
+	if err := s.checkSecondFactor(ctx, t, code); err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			s.log.Warnw("Invalid two-factor code", "userID", userID, "attempt", attempts)
+			// Неверные коды считаются как неверные пароли: перебор по новым challenge блокирует вход
+			if user, uerr := s.repo.GetUserByID(ctx, userID); uerr == nil {
+				s.recordLoginFailure(ctx, user.Username)
+				s.auditLoginFailure(ctx, userID, user.Username, loginFailureInvalidCode)
+			}
+		}
+		return nil, err
+	}
+
+	deleted, err := s.cacheRepo.DeleteLoginChallenge(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, fmt.Errorf("%w: challenge expired or already used", ErrInvalidCredentials)
+	}
+
+	s.auditLogin(ctx, userID, "password+totp")
+	return s.issueTokens(ctx, userID)
+}
+
+// checkSecondFactor принимает TOTP-код (каждый шаг — один раз) или неиспользованный код восстановления.
+func (s *merchStoreServiceImp) checkSecondFactor(ctx context.Context, t *models.TOTP, code string) error {
+	now := time.Now()
+	code = strings.TrimSpace(code)
+
+	if counter, ok := totp.Validate(t.Secret, code, now, s.twoFactor.Skew); ok {
+		fresh, err := s.repo.UseTOTPCounter(ctx, t.UserID, counter)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return fmt.Errorf("%w: code already used", ErrInvalidCredentials)
+		}
+		return nil
+	}
+
+	used, err := s.repo.UseRecoveryCode(ctx, t.UserID, hashRecoveryCode(code), now)
+	if err != nil {
+		return err
+	}
+	if used {
+		s.log.Infow("Recovery code used", "userID", t.UserID)
+		return nil
+	}
+
+	return fmt.Errorf("%w: invalid two-factor code", ErrInvalidCredentials)
+}
+
+// newRecoveryCodes возвращает коды вида xxxxx-xxxxx (50 бит) и их хэши.
+func (s *merchStoreServiceImp) newRecoveryCodes() ([]string, []string, error) {
+	n := s.twoFactor.RecoveryCodes
+	if n <= 0 {
+		n = defaultRecoveryCodes
+	}
+
+	codes := make([]string, n)
+	hashes := make([]string, n)
+	raw := make([]byte, 10)
+	for i := range codes {
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, fmt.Errorf("generate recovery code: %w", err)
+		}
+		encoded := strings.ToLower(recoveryCodeEncoding.EncodeToString(raw))[:10]
+		codes[i] = encoded[:5] + "-" + encoded[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// limitTwoFactorAttempts ограничивает ввод кодов вошедшим пользователем (подтверждение
+// и отключение 2FA): иначе по украденному access-токену 6-значный код перебирается.
+func (s *merchStoreServiceImp) limitTwoFactorAttempts(ctx context.Context, userID int) error {
+	attempts, _, err := s.cacheRepo.HitRateLimit(ctx, "2fa:user:"+strconv.Itoa(userID), s.challengeTTL())
+	if err != nil {
+		return err
+	}
+	if attempts > s.maxTwoFactorAttempts() {
+		s.log.Warnw("Too many two-factor attempts", "userID", userID)
+		return ErrTooManyAttempts
+	}
+	return nil
+}
+
+// recordTwoFactorFailure учитывает неверный код или пароль в блокировке входа и журнале аудита.
+func (s *merchStoreServiceImp) recordTwoFactorFailure(ctx context.Context, user *models.User, reason string) {
+	s.recordLoginFailure(ctx, user.Username)
+	s.auditLoginFailure(ctx, user.ID, user.Username, reason)
+}
+
+func (s *merchStoreServiceImp) maxTwoFactorAttempts() int {
+	if s.twoFactor.MaxAttempts > 0 {
+		return s.twoFactor.MaxAttempts
+	}
+	return defaultChallengeAttempts
+}
+
+func (s *merchStoreServiceImp) challengeTTL() time.Duration {
+	if s.twoFactor.ChallengeTTL > 0 {
+		return time.Duration(s.twoFactor.ChallengeTTL) * time.Second
+	}
+	return defaultChallengeTTL
+}
+
+// hashRecoveryCode не учитывает регистр, пробелы и дефисы: код вводят вручную.
+func hashRecoveryCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func hashChallengeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
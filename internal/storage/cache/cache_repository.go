@@ -2,15 +2,56 @@ package cache
 
 import (
 	"context"
+	"time"
 )
 
 type CacheRepository interface {
 	SetBalance(ctx context.Context, userID int, balance int) error
 	GetBalance(ctx context.Context, userID int) (int, error)
+	GetBalances(ctx context.Context, userIDs []int) (map[int]int, error)
+	RepairBalance(ctx context.Context, userID int, expected *int, balance int) (bool, error)
 	DeductBalance(ctx context.Context, userID int, amount int) error
 	IncrementBalance(ctx context.Context, userID int, amount int) error
+	IncrementBalances(ctx context.Context, amounts map[int]int) error
 	TransferCoins(ctx context.Context, fromUser, toUser int, amount int) error
+	TransferCoinsBatch(ctx context.Context, fromUser int, amounts map[int]int) error
+
+	SetCurrencyBalance(ctx context.Context, userID int, currency string, balance int) error
+	GetCurrencyBalance(ctx context.Context, userID int, currency string) (int, error)
+	GetCurrencyBalances(ctx context.Context, currency string, userIDs []int) (map[int]int, error)
+	RepairCurrencyBalance(ctx context.Context, userID int, currency string, expected *int, balance int) (bool, error)
+	DeductCurrencyBalance(ctx context.Context, userID int, currency string, amount int) error
+	IncrementCurrencyBalances(ctx context.Context, currency string, amounts map[int]int) error
+	TransferCurrency(ctx context.Context, currency string, fromUser, toUser int, amount int) error
+	TransferCurrencyBatch(ctx context.Context, currency string, fromUser int, amounts map[int]int) error
+
+	RevokeToken(ctx context.Context, tokenID string, ttl time.Duration) error
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+	RevokeSession(ctx context.Context, sessionID string, ttl time.Duration) error
+	IsSessionRevoked(ctx context.Context, sessionID string) (bool, error)
+	AcquireSessionTouch(ctx context.Context, sessionID string, interval time.Duration) (bool, error)
+
+	HitRateLimit(ctx context.Context, key string, window time.Duration) (int, time.Duration, error)
+	GetLoginLockout(ctx context.Context, username string) (time.Duration, error)
+	RegisterLoginFailure(ctx context.Context, username string, policy LockoutPolicy) (time.Duration, error)
+	ResetLoginFailures(ctx context.Context, username string) error
+	UnlockLogin(ctx context.Context, username string) error
+
+	SaveLoginChallenge(ctx context.Context, challengeID string, userID int, ttl time.Duration) error
+	GetLoginChallenge(ctx context.Context, challengeID string) (int, error)
+	DeleteLoginChallenge(ctx context.Context, challengeID string) (bool, error)
+	SaveOIDCNonce(ctx context.Context, handleID, nonce string, ttl time.Duration) error
+	TakeOIDCNonce(ctx context.Context, handleID string) (string, error)
 
 	LoadCatalog(ctx context.Context, catalog map[string]interface{}) error
 	GetPrice(ctx context.Context, merchName string) (int, error)
 }
+
+// LockoutPolicy — правила блокировки входа после неверных паролей.
+type LockoutPolicy struct {
+	MaxFailures   int
+	FailureWindow time.Duration
+	Base          time.Duration
+	Max           time.Duration
+	Reset         time.Duration
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"github.com/go-redis/redis/v8"
+	"merch-store-grpc/internal/models"
 	"merch-store-grpc/internal/storage/cache"
 	"merch-store-grpc/pkg/logger"
+	"strconv"
+	"time"
 )
 
 type RedisCacheRepository struct {
@@ -37,20 +40,155 @@ var (
 	redis.call("INCRBY", KEYS[2], ARGV[1])
 	return 1
 	`)
+
+	// Скрипт для перевода монет нескольким получателям (используется в TransferCurrencyBatch).
+	// KEYS[1] — отправитель, KEYS[2..] — получатели; ARGV[1] — общая сумма, ARGV[2..] — суммы получателям.
+	transferCoinsBatchScript = redis.NewScript(`
+	local from_balance = tonumber(redis.call("GET", KEYS[1]) or "0")
+	if from_balance < tonumber(ARGV[1]) then
+		return -1
+	end
+	redis.call("DECRBY", KEYS[1], ARGV[1])
+	for i = 2, #KEYS do
+		redis.call("INCRBY", KEYS[i], ARGV[i])
+	end
+	return 1
+	`)
+
+	// Скрипт для исправления баланса (используется в RepairBalance): значение
+	// перезаписывается, только если с момента проверки оно не изменилось.
+	// ARGV[1] — ожидаемое значение ("" — ключа не было), ARGV[2] — новое значение.
+	repairBalanceScript = redis.NewScript(`
+	local current = redis.call("GET", KEYS[1])
+	if (current or "") ~= ARGV[1] then
+		return 0
+	end
+	redis.call("SET", KEYS[1], ARGV[2])
+	return 1
+	`)
+
+	// Скрипт счётчика попыток в фиксированном окне (используется в HitRateLimit).
+	// Возвращает число попыток и оставшееся время окна в миллисекундах.
+	rateLimitScript = redis.NewScript(`
+	local n = redis.call("INCR", KEYS[1])
+	local ttl = redis.call("PTTL", KEYS[1])
+	if ttl < 0 then
+		redis.call("PEXPIRE", KEYS[1], ARGV[1])
+		ttl = tonumber(ARGV[1])
+	end
+	return {n, ttl}
+	`)
+
+	// Скрипт учёта неверного пароля (используется в RegisterLoginFailure).
+	// KEYS: счётчик неудач, блокировка, число блокировок; ARGV: max_failures, failure_window,
+	// lockout_base, lockout_max, lockout_reset (мс). Возвращает длительность новой блокировки или 0.
+	loginFailureScript = redis.NewScript(`
+	local n = redis.call("INCR", KEYS[1])
+	if n == 1 then
+		redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	end
+	if n < tonumber(ARGV[1]) then
+		return 0
+	end
+	redis.call("DEL", KEYS[1])
+	local level = redis.call("INCR", KEYS[3])
+	redis.call("PEXPIRE", KEYS[3], ARGV[5])
+	local lock = tonumber(ARGV[3]) * 2 ^ (level - 1)
+	if lock > tonumber(ARGV[4]) then
+		lock = tonumber(ARGV[4])
+	end
+	lock = math.floor(lock)
+	redis.call("SET", KEYS[2], level, "PX", lock)
+	return lock
+	`)
 )
 
+// balanceKey — ключ баланса в Redis: balance:{id} для основной валюты
+// и balance:{id}:{currency} для остальных.
+func balanceKey(userID int, currency string) string {
+	if currency == models.CurrencyCoin {
+		return fmt.Sprintf("balance:%d", userID)
+	}
+	return fmt.Sprintf("balance:%d:%s", userID, currency)
+}
+
 func (r *RedisCacheRepository) SetBalance(ctx context.Context, userID int, balance int) error {
-	key := fmt.Sprintf("balance:%d", userID)
-	return r.rdb.Set(ctx, key, balance, 0).Err()
+	return r.SetCurrencyBalance(ctx, userID, models.CurrencyCoin, balance)
 }
 
 func (r *RedisCacheRepository) GetBalance(ctx context.Context, userID int) (int, error) {
-	key := fmt.Sprintf("balance:%d", userID)
-	return r.rdb.Get(ctx, key).Int()
+	return r.GetCurrencyBalance(ctx, userID, models.CurrencyCoin)
+}
+
+func (r *RedisCacheRepository) SetCurrencyBalance(ctx context.Context, userID int, currency string, balance int) error {
+	return r.rdb.Set(ctx, balanceKey(userID, currency), balance, 0).Err()
+}
+
+func (r *RedisCacheRepository) GetCurrencyBalance(ctx context.Context, userID int, currency string) (int, error) {
+	return r.rdb.Get(ctx, balanceKey(userID, currency)).Int()
+}
+
+func (r *RedisCacheRepository) GetBalances(ctx context.Context, userIDs []int) (map[int]int, error) {
+	return r.GetCurrencyBalances(ctx, models.CurrencyCoin, userIDs)
+}
+
+// GetCurrencyBalances читает балансы одним MGET. Пользователи без ключа в результат не попадают.
+func (r *RedisCacheRepository) GetCurrencyBalances(ctx context.Context, currency string, userIDs []int) (map[int]int, error) {
+	if len(userIDs) == 0 {
+		return map[int]int{}, nil
+	}
+
+	keys := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		keys[i] = balanceKey(id, currency)
+	}
+
+	values, err := r.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[int]int, len(userIDs))
+	for i, v := range values {
+		str, ok := v.(string)
+		if !ok {
+			continue
+		}
+		balance, err := strconv.Atoi(str)
+		if err != nil {
+			return nil, fmt.Errorf("parse balance for user %d: %w", userIDs[i], err)
+		}
+		balances[userIDs[i]] = balance
+	}
+	return balances, nil
+}
+
+func (r *RedisCacheRepository) RepairBalance(ctx context.Context, userID int, expected *int, balance int) (bool, error) {
+	return r.RepairCurrencyBalance(ctx, userID, models.CurrencyCoin, expected, balance)
+}
+
+// RepairCurrencyBalance выставляет баланс, если в кэше всё ещё лежит expected (nil — ключа нет).
+// Возвращает false, если значение успело измениться.
+func (r *RedisCacheRepository) RepairCurrencyBalance(ctx context.Context, userID int, currency string, expected *int, balance int) (bool, error) {
+	key := balanceKey(userID, currency)
+	expectedArg := ""
+	if expected != nil {
+		expectedArg = strconv.Itoa(*expected)
+	}
+
+	res, err := repairBalanceScript.Run(ctx, r.rdb, []string{key}, expectedArg, balance).Result()
+	if err != nil {
+		return false, err
+	}
+	return res.(int64) == 1, nil
 }
 
 func (r *RedisCacheRepository) DeductBalance(ctx context.Context, userID int, amount int) error {
-	key := fmt.Sprintf("balance:%d", userID)
+	return r.DeductCurrencyBalance(ctx, userID, models.CurrencyCoin, amount)
+}
+
+func (r *RedisCacheRepository) DeductCurrencyBalance(ctx context.Context, userID int, currency string, amount int) error {
+	key := balanceKey(userID, currency)
 	res, err := deductBalanceScript.Run(ctx, r.rdb, []string{key}, amount).Result()
 	if err != nil {
 		return err
@@ -63,13 +201,32 @@ func (r *RedisCacheRepository) DeductBalance(ctx context.Context, userID int, am
 }
 
 func (r *RedisCacheRepository) IncrementBalance(ctx context.Context, userID int, amount int) error {
-	key := fmt.Sprintf("balance:%d", userID)
+	key := balanceKey(userID, models.CurrencyCoin)
 	return r.rdb.IncrBy(ctx, key, int64(amount)).Err()
 }
 
+func (r *RedisCacheRepository) IncrementBalances(ctx context.Context, amounts map[int]int) error {
+	return r.IncrementCurrencyBalances(ctx, models.CurrencyCoin, amounts)
+}
+
+// IncrementCurrencyBalances изменяет балансы нескольких пользователей одной транзакцией MULTI/EXEC.
+func (r *RedisCacheRepository) IncrementCurrencyBalances(ctx context.Context, currency string, amounts map[int]int) error {
+	_, err := r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for userID, amount := range amounts {
+			pipe.IncrBy(ctx, balanceKey(userID, currency), int64(amount))
+		}
+		return nil
+	})
+	return err
+}
+
 func (r *RedisCacheRepository) TransferCoins(ctx context.Context, fromUser, toUser int, amount int) error {
-	fromKey := fmt.Sprintf("balance:%d", fromUser)
-	toKey := fmt.Sprintf("balance:%d", toUser)
+	return r.TransferCurrency(ctx, models.CurrencyCoin, fromUser, toUser, amount)
+}
+
+func (r *RedisCacheRepository) TransferCurrency(ctx context.Context, currency string, fromUser, toUser int, amount int) error {
+	fromKey := balanceKey(fromUser, currency)
+	toKey := balanceKey(toUser, currency)
 	res, err := transferCoinsScript.Run(ctx, r.rdb, []string{fromKey, toKey}, amount).Result()
 	if err != nil {
 		return err
@@ -80,6 +237,169 @@ func (r *RedisCacheRepository) TransferCoins(ctx context.Context, fromUser, toUs
 	return nil
 }
 
+func (r *RedisCacheRepository) TransferCoinsBatch(ctx context.Context, fromUser int, amounts map[int]int) error {
+	return r.TransferCurrencyBatch(ctx, models.CurrencyCoin, fromUser, amounts)
+}
+
+func (r *RedisCacheRepository) TransferCurrencyBatch(ctx context.Context, currency string, fromUser int, amounts map[int]int) error {
+	keys := []string{balanceKey(fromUser, currency)}
+	args := []interface{}{0}
+
+	total := 0
+	for toUser, amount := range amounts {
+		keys = append(keys, balanceKey(toUser, currency))
+		args = append(args, amount)
+		total += amount
+	}
+	args[0] = total
+
+	res, err := transferCoinsBatchScript.Run(ctx, r.rdb, keys, args...).Result()
+	if err != nil {
+		return err
+	}
+	if res.(int64) < 0 {
+		return fmt.Errorf("insufficient funds for transfer")
+	}
+	return nil
+}
+
+// RevokeToken заносит jti в список отзыва до истечения срока действия токена.
+func (r *RedisCacheRepository) RevokeToken(ctx context.Context, tokenID string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return r.rdb.Set(ctx, fmt.Sprintf("revoked:%s", tokenID), 1, ttl).Err()
+}
+
+func (r *RedisCacheRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	n, err := r.rdb.Exists(ctx, fmt.Sprintf("revoked:%s", tokenID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// RevokeSession отзывает все access-токены сессии (по claim sid), включая выданные до
+// последнего обновления; ttl — до истечения самого позднего из них.
+func (r *RedisCacheRepository) RevokeSession(ctx context.Context, sessionID string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return r.rdb.Set(ctx, fmt.Sprintf("revoked:session:%s", sessionID), 1, ttl).Err()
+}
+
+func (r *RedisCacheRepository) IsSessionRevoked(ctx context.Context, sessionID string) (bool, error) {
+	n, err := r.rdb.Exists(ctx, fmt.Sprintf("revoked:session:%s", sessionID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// AcquireSessionTouch возвращает true не чаще раза в interval для одной сессии (SETNX с TTL).
+func (r *RedisCacheRepository) AcquireSessionTouch(ctx context.Context, sessionID string, interval time.Duration) (bool, error) {
+	return r.rdb.SetNX(ctx, fmt.Sprintf("session:touch:%s", sessionID), 1, interval).Result()
+}
+
+// HitRateLimit учитывает попытку в окне window и возвращает число попыток в текущем окне
+// и время до его окончания.
+func (r *RedisCacheRepository) HitRateLimit(ctx context.Context, key string, window time.Duration) (int, time.Duration, error) {
+	res, err := rateLimitScript.Run(ctx, r.rdb, []string{"ratelimit:" + key}, window.Milliseconds()).Result()
+	if err != nil {
+		return 0, 0, err
+	}
+	values := res.([]interface{})
+	return int(values[0].(int64)), time.Duration(values[1].(int64)) * time.Millisecond, nil
+}
+
+// GetLoginLockout возвращает, сколько ещё заблокирован вход под username (0 — не заблокирован).
+func (r *RedisCacheRepository) GetLoginLockout(ctx context.Context, username string) (time.Duration, error) {
+	ttl, err := r.rdb.PTTL(ctx, loginLockKey(username)).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (r *RedisCacheRepository) RegisterLoginFailure(ctx context.Context, username string, policy cache.LockoutPolicy) (time.Duration, error) {
+	keys := []string{loginFailuresKey(username), loginLockKey(username), loginLockoutsKey(username)}
+	res, err := loginFailureScript.Run(ctx, r.rdb, keys,
+		policy.MaxFailures,
+		policy.FailureWindow.Milliseconds(),
+		policy.Base.Milliseconds(),
+		policy.Max.Milliseconds(),
+		policy.Reset.Milliseconds(),
+	).Result()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(res.(int64)) * time.Millisecond, nil
+}
+
+func (r *RedisCacheRepository) ResetLoginFailures(ctx context.Context, username string) error {
+	return r.rdb.Del(ctx, loginFailuresKey(username)).Err()
+}
+
+// UnlockLogin снимает блокировку и сбрасывает все счётчики неудачных входов username.
+func (r *RedisCacheRepository) UnlockLogin(ctx context.Context, username string) error {
+	return r.rdb.Del(ctx,
+		loginFailuresKey(username),
+		loginLockKey(username),
+		loginLockoutsKey(username),
+	).Err()
+}
+
+func loginFailuresKey(username string) string {
+	return fmt.Sprintf("login:failures:%s", username)
+}
+
+func loginLockKey(username string) string {
+	return fmt.Sprintf("login:lock:%s", username)
+}
+
+func loginLockoutsKey(username string) string {
+	return fmt.Sprintf("login:lockouts:%s", username)
+}
+
+// SaveLoginChallenge запоминает, что пользователь прошёл проверку пароля и ждёт второго фактора.
+func (r *RedisCacheRepository) SaveLoginChallenge(ctx context.Context, challengeID string, userID int, ttl time.Duration) error {
+	return r.rdb.Set(ctx, loginChallengeKey(challengeID), userID, ttl).Err()
+}
+
+func (r *RedisCacheRepository) GetLoginChallenge(ctx context.Context, challengeID string) (int, error) {
+	return r.rdb.Get(ctx, loginChallengeKey(challengeID)).Int()
+}
+
+// DeleteLoginChallenge гасит challenge. false — его уже нет (истёк или использован).
+func (r *RedisCacheRepository) DeleteLoginChallenge(ctx context.Context, challengeID string) (bool, error) {
+	n, err := r.rdb.Del(ctx, loginChallengeKey(challengeID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func loginChallengeKey(challengeID string) string {
+	return fmt.Sprintf("login:challenge:%s", challengeID)
+}
+
+// SaveOIDCNonce запоминает nonce начатого входа через OIDC.
+func (r *RedisCacheRepository) SaveOIDCNonce(ctx context.Context, handleID, nonce string, ttl time.Duration) error {
+	return r.rdb.Set(ctx, oidcNonceKey(handleID), nonce, ttl).Err()
+}
+
+// TakeOIDCNonce возвращает nonce и сразу удаляет его (GETDEL): один вход — одна попытка.
+func (r *RedisCacheRepository) TakeOIDCNonce(ctx context.Context, handleID string) (string, error) {
+	return r.rdb.GetDel(ctx, oidcNonceKey(handleID)).Result()
+}
+
+func oidcNonceKey(handleID string) string {
+	return fmt.Sprintf("oidc:nonce:%s", handleID)
+}
+
 func (r *RedisCacheRepository) LoadCatalog(ctx context.Context, catalog map[string]interface{}) error {
 	return r.rdb.HSet(ctx, "merch_catalog", catalog).Err()
 }
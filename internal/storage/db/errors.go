@@ -0,0 +1,6 @@
+package db
+
+import "errors"
+
+// ErrDuplicateKey возвращается репозиториями при нарушении уникального ограничения.
+var ErrDuplicateKey = errors.New("duplicate key")
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db"
+	"merch-store-grpc/pkg/logger"
+	"time"
+)
+
+type postgresAllowanceRepository struct {
+	conn   db.TxManager
+	logger logger.Logger
+}
+
+func NewAllowanceRepository(conn db.TxManager, log logger.Logger) db.AllowanceRepository {
+	return &postgresAllowanceRepository{conn: conn, logger: log}
+}
+
+func (r *postgresAllowanceRepository) GetAllowance(ctx context.Context, userID int) (*models.GiftAllowance, error) {
+	return r.getAllowance(ctx, userID, "")
+}
+
+// GetAllowanceForUpdate блокирует строку лимита до конца транзакции.
+func (r *postgresAllowanceRepository) GetAllowanceForUpdate(ctx context.Context, userID int) (*models.GiftAllowance, error) {
+	return r.getAllowance(ctx, userID, "FOR UPDATE")
+}
+
+func (r *postgresAllowanceRepository) getAllowance(ctx context.Context, userID int, lock string) (*models.GiftAllowance, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT user_id, balance, period, updated_at
+		FROM gift_allowances
+		WHERE user_id = $1
+	` + lock
+
+	var allowance models.GiftAllowance
+	err := pool.QueryRow(ctx, query, userID).Scan(
+		&allowance.UserID,
+		&allowance.Balance,
+		&allowance.Period,
+		&allowance.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get gift allowance: %w", err)
+	}
+
+	return &allowance, nil
+}
+
+func (r *postgresAllowanceRepository) SetAllowance(ctx context.Context, userID, balance int, period time.Time) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		INSERT INTO gift_allowances (user_id, balance, period, updated_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (user_id)
+		DO UPDATE SET balance = EXCLUDED.balance, period = EXCLUDED.period, updated_at = now()
+	`
+
+	_, err := pool.Exec(ctx, query, userID, balance, period)
+	if err != nil {
+		r.logger.Errorw("setting gift allowance",
+			"error", err,
+			"userID", userID,
+		)
+		return fmt.Errorf("set gift allowance: %w", err)
+	}
+
+	return nil
+}
+
+// ResetAllowances выставляет amount всем пользователям, у которых лимит относится
+// к периоду раньше period (или ещё не заведён). Возвращает число обновлённых строк.
+func (r *postgresAllowanceRepository) ResetAllowances(ctx context.Context, amount int, period time.Time) (int, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		INSERT INTO gift_allowances (user_id, balance, period, updated_at)
+		SELECT id, $1, $2, now()
+		FROM users
+		ON CONFLICT (user_id)
+		DO UPDATE SET balance = EXCLUDED.balance, period = EXCLUDED.period, updated_at = now()
+		WHERE gift_allowances.period < EXCLUDED.period
+	`
+
+	tag, err := pool.Exec(ctx, query, amount, period)
+	if err != nil {
+		r.logger.Errorw("resetting gift allowances",
+			"error", err,
+			"period", period,
+		)
+		return 0, fmt.Errorf("reset gift allowances: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
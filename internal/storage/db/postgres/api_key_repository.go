@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db"
+	"merch-store-grpc/pkg/logger"
+	"time"
+)
+
+type postgresAPIKeyRepository struct {
+	conn   db.TxManager
+	logger logger.Logger
+}
+
+func NewAPIKeyRepository(conn db.TxManager, log logger.Logger) db.APIKeyRepository {
+	return &postgresAPIKeyRepository{conn: conn, logger: log}
+}
+
+func (r *postgresAPIKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) (int, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, created_by, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	var keyID int
+	err := pool.QueryRow(ctx, query,
+		key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scopes, key.CreatedBy, key.CreatedAt, key.ExpiresAt,
+	).Scan(&keyID)
+	if err != nil {
+		r.logger.Errorw("creating api key",
+			"error", err,
+			"userID", key.UserID,
+		)
+		return 0, fmt.Errorf("create api key: %w", err)
+	}
+
+	return keyID, nil
+}
+
+func (r *postgresAPIKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT k.id, k.user_id, u.username, k.name, k.prefix, k.key_hash, k.scopes, COALESCE(k.created_by, 0),
+		       k.created_at, k.expires_at, k.last_used_at, k.revoked_at
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id
+		WHERE k.prefix = $1
+	`
+
+	var key models.APIKey
+	err := pool.QueryRow(ctx, query, prefix).Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Username,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.Scopes,
+		&key.CreatedBy,
+		&key.CreatedAt,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get api key by prefix: %w", err)
+	}
+
+	return &key, nil
+}
+
+func (r *postgresAPIKeyRepository) ListAPIKeys(ctx context.Context, userID int) ([]*models.APIKey, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT k.id, k.user_id, u.username, k.name, k.prefix, k.key_hash, k.scopes, COALESCE(k.created_by, 0),
+		       k.created_at, k.expires_at, k.last_used_at, k.revoked_at
+		FROM api_keys k
+		JOIN users u ON u.id = k.user_id
+		WHERE k.user_id = $1
+		ORDER BY k.created_at DESC, k.id DESC
+	`
+
+	rows, err := pool.Query(ctx, query, userID)
+	if err != nil {
+		r.logger.Errorw("retrieving api keys",
+			"error", err,
+			"userID", userID,
+		)
+		return nil, fmt.Errorf("retrieve api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*models.APIKey
+	for rows.Next() {
+		var key models.APIKey
+		err := rows.Scan(
+			&key.ID,
+			&key.UserID,
+			&key.Username,
+			&key.Name,
+			&key.Prefix,
+			&key.KeyHash,
+			&key.Scopes,
+			&key.CreatedBy,
+			&key.CreatedAt,
+			&key.ExpiresAt,
+			&key.LastUsedAt,
+			&key.RevokedAt,
+		)
+		if err != nil {
+			r.logger.Errorw("scanning api key",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading api key: %w", err)
+		}
+		keys = append(keys, &key)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey отзывает ключ и возвращает id его владельца; false — ключа нет или он уже отозван.
+func (r *postgresAPIKeyRepository) RevokeAPIKey(ctx context.Context, keyID int, revokedAt time.Time) (int, bool, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		UPDATE api_keys
+		SET revoked_at = $2
+		WHERE id = $1 AND revoked_at IS NULL
+		RETURNING user_id
+	`
+
+	var userID int
+	err := pool.QueryRow(ctx, query, keyID, revokedAt).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+		r.logger.Errorw("revoking api key",
+			"error", err,
+			"keyID", keyID,
+		)
+		return 0, false, fmt.Errorf("revoke api key: %w", err)
+	}
+
+	return userID, true, nil
+}
+
+// TouchAPIKey обновляет last_used_at не чаще раза в минуту, чтобы не писать в базу на каждый запрос.
+func (r *postgresAPIKeyRepository) TouchAPIKey(ctx context.Context, keyID int, usedAt time.Time) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		UPDATE api_keys
+		SET last_used_at = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $2 - INTERVAL '1 minute')
+	`
+
+	if _, err := pool.Exec(ctx, query, keyID, usedAt); err != nil {
+		return fmt.Errorf("update api key last used: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db"
+	"merch-store-grpc/pkg/logger"
+	"strings"
+)
+
+type postgresAuditRepository struct {
+	conn   db.TxManager
+	logger logger.Logger
+}
+
+func NewAuditRepository(conn db.TxManager, log logger.Logger) db.AuditRepository {
+	return &postgresAuditRepository{conn: conn, logger: log}
+}
+
+// CreateAuditEvent добавляет запись в журнал. Внутри транзакции запись появится только
+// вместе с самой операцией.
+func (r *postgresAuditRepository) CreateAuditEvent(ctx context.Context, event *models.AuditEvent) (int, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	details := event.Details
+	if details == nil {
+		details = map[string]string{}
+	}
+
+	query := `
+		INSERT INTO audit_log
+			(event_type, actor_id, target_id, ip, user_agent, request_id,
+			 currency, amount, balance_before, balance_after, details, created_at)
+		VALUES ($1, NULLIF($2, 0), NULLIF($3, 0), $4, $5, $6,
+		        NULLIF($7, ''), NULLIF($8, 0), $9, $10, $11, $12)
+		RETURNING id
+	`
+
+	var eventID int
+	err := pool.QueryRow(ctx, query,
+		event.EventType, event.ActorID, event.TargetID, event.IP, event.UserAgent, event.RequestID,
+		event.Currency, event.Amount, event.BalanceBefore, event.BalanceAfter, details, event.CreatedAt,
+	).Scan(&eventID)
+	if err != nil {
+		r.logger.Errorw("creating audit event",
+			"error", err,
+			"eventType", event.EventType,
+		)
+		return 0, fmt.Errorf("create audit event: %w", err)
+	}
+
+	return eventID, nil
+}
+
+// ListAuditEvents возвращает записи журнала по фильтру, от новых к старым.
+// Пагинация keyset по (created_at, id).
+func (r *postgresAuditRepository) ListAuditEvents(ctx context.Context, filter *models.AuditFilter) ([]*models.AuditEvent, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	var (
+		args  []any
+		conds = []string{"true"}
+	)
+	addArg := func(cond string, v any) {
+		args = append(args, v)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.EventType != "" {
+		addArg("event_type = $%d", filter.EventType)
+	}
+	if filter.ActorID > 0 {
+		addArg("actor_id = $%d", filter.ActorID)
+	}
+	if filter.TargetID > 0 {
+		addArg("target_id = $%d", filter.TargetID)
+	}
+	if filter.UserID > 0 {
+		args = append(args, filter.UserID)
+		n := len(args)
+		conds = append(conds, fmt.Sprintf("(actor_id = $%d OR target_id = $%d)", n, n))
+	}
+	if filter.IP != "" {
+		addArg("ip = $%d", filter.IP)
+	}
+	if filter.RequestID != "" {
+		addArg("request_id = $%d", filter.RequestID)
+	}
+	if filter.From != nil {
+		addArg("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addArg("created_at < $%d", *filter.To)
+	}
+	if filter.After != nil {
+		args = append(args, filter.After.CreatedAt, filter.After.ID)
+		conds = append(conds, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf(`
+		SELECT id, event_type, COALESCE(actor_id, 0), COALESCE(target_id, 0), ip, user_agent, request_id,
+		       COALESCE(currency, ''), COALESCE(amount, 0), balance_before, balance_after, details, created_at
+		FROM audit_log
+		WHERE %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d
+	`, strings.Join(conds, " AND "), len(args))
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorw("listing audit events",
+			"error", err,
+		)
+		return nil, fmt.Errorf("list audit events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.AuditEvent
+	for rows.Next() {
+		var event models.AuditEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.EventType,
+			&event.ActorID,
+			&event.TargetID,
+			&event.IP,
+			&event.UserAgent,
+			&event.RequestID,
+			&event.Currency,
+			&event.Amount,
+			&event.BalanceBefore,
+			&event.BalanceAfter,
+			&event.Details,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			r.logger.Errorw("scanning audit event",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading audit event: %w", err)
+		}
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return events, nil
+}
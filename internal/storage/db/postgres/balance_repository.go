@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"merch-store-grpc/internal/storage/db"
+	"merch-store-grpc/pkg/logger"
+)
+
+type postgresBalanceRepository struct {
+	conn   db.TxManager
+	logger logger.Logger
+}
+
+func NewBalanceRepository(conn db.TxManager, log logger.Logger) db.BalanceRepository {
+	return &postgresBalanceRepository{conn: conn, logger: log}
+}
+
+// GetBalancesByUserID возвращает балансы пользователя в дополнительных валютах.
+func (r *postgresBalanceRepository) GetBalancesByUserID(ctx context.Context, userID int) (map[string]int, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT currency, balance
+		FROM user_balances
+		WHERE user_id = $1
+	`
+
+	rows, err := pool.Query(ctx, query, userID)
+	if err != nil {
+		r.logger.Errorw("retrieving user balances",
+			"error", err,
+			"userID", userID,
+		)
+		return nil, fmt.Errorf("retrieve user balances: %w", err)
+	}
+	defer rows.Close()
+
+	balances := make(map[string]int)
+	for rows.Next() {
+		var (
+			currency string
+			balance  int
+		)
+		if err := rows.Scan(&currency, &balance); err != nil {
+			r.logger.Errorw("scanning user balance",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading user balance: %w", err)
+		}
+		balances[currency] = balance
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return balances, nil
+}
+
+// GetBalancesByUserIDs возвращает балансы нескольких пользователей в одной валюте.
+// Пользователи без строки в результат не попадают — их баланс нулевой.
+func (r *postgresBalanceRepository) GetBalancesByUserIDs(ctx context.Context, userIDs []int, currency string) (map[int]int, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT user_id, balance
+		FROM user_balances
+		WHERE user_id = ANY($1) AND currency = $2
+	`
+
+	rows, err := pool.Query(ctx, query, userIDs, currency)
+	if err != nil {
+		r.logger.Errorw("retrieving balances by user ids",
+			"error", err,
+			"currency", currency,
+		)
+		return nil, fmt.Errorf("retrieve balances by user ids: %w", err)
+	}
+	defer rows.Close()
+
+	balances := make(map[int]int, len(userIDs))
+	for rows.Next() {
+		var userID, balance int
+		if err := rows.Scan(&userID, &balance); err != nil {
+			r.logger.Errorw("scanning user balance",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading user balance: %w", err)
+		}
+		balances[userID] = balance
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return balances, nil
+}
+
+// GetBalanceForUpdate блокирует баланс пользователя в валюте до конца транзакции.
+// Отсутствующая строка означает нулевой баланс.
+func (r *postgresBalanceRepository) GetBalanceForUpdate(ctx context.Context, userID int, currency string) (int, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT balance
+		FROM user_balances
+		WHERE user_id = $1 AND currency = $2
+		FOR UPDATE
+	`
+
+	var balance int
+	err := pool.QueryRow(ctx, query, userID, currency).Scan(&balance)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		r.logger.Errorw("locking user balance",
+			"error", err,
+			"userID", userID,
+			"currency", currency,
+		)
+		return 0, fmt.Errorf("lock user balance: %w", err)
+	}
+
+	return balance, nil
+}
+
+// AddBalance изменяет баланс на delta, создавая строку при первом поступлении.
+// Уход в минус отсекает CHECK (balance >= 0).
+func (r *postgresBalanceRepository) AddBalance(ctx context.Context, userID int, currency string, delta int) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		INSERT INTO user_balances (user_id, currency, balance)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, currency)
+		DO UPDATE SET balance = user_balances.balance + EXCLUDED.balance
+	`
+
+	_, err := pool.Exec(ctx, query, userID, currency, delta)
+	if err != nil {
+		r.logger.Errorw("updating user balance",
+			"error", err,
+			"userID", userID,
+			"currency", currency,
+		)
+		return fmt.Errorf("update user balance: %w", err)
+	}
+
+	return nil
+}
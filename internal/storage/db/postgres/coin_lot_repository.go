@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db"
+	"merch-store-grpc/pkg/logger"
+	"time"
+)
+
+type postgresCoinLotRepository struct {
+	conn   db.TxManager
+	logger logger.Logger
+}
+
+func NewCoinLotRepository(conn db.TxManager, log logger.Logger) db.CoinLotRepository {
+	return &postgresCoinLotRepository{conn: conn, logger: log}
+}
+
+func (r *postgresCoinLotRepository) CreateCoinLot(ctx context.Context, lot *models.CoinLot) (int, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		INSERT INTO coin_lots (user_id, source, amount, remaining, received_at, expires_at)
+		VALUES ($1, $2, $3, $3, $4, $5)
+		RETURNING id
+	`
+
+	var lotID int
+	err := pool.QueryRow(ctx, query, lot.UserID, lot.Source, lot.Amount, lot.ReceivedAt, lot.ExpiresAt).Scan(&lotID)
+	if err != nil {
+		r.logger.Errorw("creating coin lot",
+			"error", err,
+			"userID", lot.UserID,
+			"source", lot.Source,
+		)
+		return 0, fmt.Errorf("create coin lot: %w", err)
+	}
+
+	return lotID, nil
+}
+
+// ConsumeCoinLots списывает amount монет из открытых лотов пользователя в порядке FIFO.
+// Должен вызываться внутри транзакции: лоты блокируются до её завершения.
+func (r *postgresCoinLotRepository) ConsumeCoinLots(ctx context.Context, userID int, amount int) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT id, remaining
+		FROM coin_lots
+		WHERE user_id = $1 AND remaining > 0
+		ORDER BY received_at, id
+		FOR UPDATE
+	`
+
+	rows, err := pool.Query(ctx, query, userID)
+	if err != nil {
+		r.logger.Errorw("retrieving open coin lots",
+			"error", err,
+			"userID", userID,
+		)
+		return fmt.Errorf("retrieve open coin lots: %w", err)
+	}
+
+	type lotPart struct {
+		id    int
+		taken int
+	}
+
+	var parts []lotPart
+	left := amount
+	for rows.Next() && left > 0 {
+		var id, remaining int
+		if err := rows.Scan(&id, &remaining); err != nil {
+			rows.Close()
+			r.logger.Errorw("scanning coin lot data",
+				"error", err,
+			)
+			return fmt.Errorf("reading coin lot data: %w", err)
+		}
+		taken := min(remaining, left)
+		parts = append(parts, lotPart{id: id, taken: taken})
+		left -= taken
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return fmt.Errorf("processing query result: %w", err)
+	}
+
+	if left > 0 {
+		r.logger.Errorw("coin lots do not cover the requested amount",
+			"userID", userID,
+			"amount", amount,
+			"missing", left,
+		)
+		return fmt.Errorf("not enough coins in lots: missing %d", left)
+	}
+
+	for _, p := range parts {
+		_, err := pool.Exec(ctx, `UPDATE coin_lots SET remaining = remaining - $1 WHERE id = $2`, p.taken, p.id)
+		if err != nil {
+			r.logger.Errorw("consuming coin lot",
+				"error", err,
+				"lotID", p.id,
+			)
+			return fmt.Errorf("consume coin lot: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *postgresCoinLotRepository) GetOpenCoinLots(ctx context.Context, userID int) ([]*models.CoinLot, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT id, user_id, source, amount, remaining, received_at, expires_at, expired_at
+		FROM coin_lots
+		WHERE user_id = $1 AND remaining > 0
+		ORDER BY expires_at, id
+	`
+
+	return r.queryLots(ctx, pool, query, userID)
+}
+
+// GetExpiredCoinLots возвращает просроченные лоты с ненулевым остатком и блокирует их.
+// Лоты, уже заблокированные другой транзакцией, пропускаются.
+func (r *postgresCoinLotRepository) GetExpiredCoinLots(ctx context.Context, now time.Time, limit int) ([]*models.CoinLot, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT id, user_id, source, amount, remaining, received_at, expires_at, expired_at
+		FROM coin_lots
+		WHERE expires_at <= $1 AND remaining > 0
+		ORDER BY expires_at, id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`
+
+	return r.queryLots(ctx, pool, query, now, limit)
+}
+
+func (r *postgresCoinLotRepository) MarkCoinLotExpired(ctx context.Context, lotID int, expiredAt time.Time) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		UPDATE coin_lots
+		SET remaining = 0, expired_at = $1
+		WHERE id = $2
+	`
+
+	result, err := pool.Exec(ctx, query, expiredAt, lotID)
+	if err != nil {
+		r.logger.Errorw("expiring coin lot",
+			"error", err,
+			"lotID", lotID,
+		)
+		return fmt.Errorf("expire coin lot: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("coin lot with ID %d not found", lotID)
+	}
+
+	return nil
+}
+
+func (r *postgresCoinLotRepository) queryLots(ctx context.Context, pool db.Executor, query string, args ...any) ([]*models.CoinLot, error) {
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorw("retrieving coin lots",
+			"error", err,
+		)
+		return nil, fmt.Errorf("retrieve coin lots: %w", err)
+	}
+	defer rows.Close()
+
+	var lots []*models.CoinLot
+	for rows.Next() {
+		var lot models.CoinLot
+		err := rows.Scan(
+			&lot.ID,
+			&lot.UserID,
+			&lot.Source,
+			&lot.Amount,
+			&lot.Remaining,
+			&lot.ReceivedAt,
+			&lot.ExpiresAt,
+			&lot.ExpiredAt,
+		)
+		if err != nil {
+			r.logger.Errorw("scanning coin lot data",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading coin lot data: %w", err)
+		}
+		lots = append(lots, &lot)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return lots, nil
+}
@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db"
+	"merch-store-grpc/pkg/logger"
+	"time"
+)
+
+type postgresCoinRequestRepository struct {
+	conn   db.TxManager
+	logger logger.Logger
+}
+
+func NewCoinRequestRepository(conn db.TxManager, log logger.Logger) db.CoinRequestRepository {
+	return &postgresCoinRequestRepository{conn: conn, logger: log}
+}
+
+const coinRequestColumns = `id, requester_id, payer_id, amount, note, status, transaction_id, created_at, expires_at, resolved_at`
+
+func (r *postgresCoinRequestRepository) CreateCoinRequest(ctx context.Context, req *models.CoinRequest) (int, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		INSERT INTO coin_requests (requester_id, payer_id, amount, note, status, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	var requestID int
+	err := pool.QueryRow(ctx, query,
+		req.RequesterID, req.PayerID, req.Amount, req.Note, req.Status, req.CreatedAt, req.ExpiresAt,
+	).Scan(&requestID)
+	if err != nil {
+		r.logger.Errorw("creating coin request",
+			"error", err,
+			"requesterID", req.RequesterID,
+			"payerID", req.PayerID,
+		)
+		return 0, fmt.Errorf("create coin request: %w", err)
+	}
+
+	return requestID, nil
+}
+
+// GetCoinRequestForUpdate возвращает запрос и блокирует его строку до конца транзакции.
+func (r *postgresCoinRequestRepository) GetCoinRequestForUpdate(ctx context.Context, requestID int) (*models.CoinRequest, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `SELECT ` + coinRequestColumns + `
+		FROM coin_requests
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	var req models.CoinRequest
+	err := pool.QueryRow(ctx, query, requestID).Scan(
+		&req.ID,
+		&req.RequesterID,
+		&req.PayerID,
+		&req.Amount,
+		&req.Note,
+		&req.Status,
+		&req.TransactionID,
+		&req.CreatedAt,
+		&req.ExpiresAt,
+		&req.ResolvedAt,
+	)
+	if err != nil {
+		r.logger.Warnw("getting a coin request by ID",
+			"error", err,
+			"requestID", requestID,
+		)
+		return nil, fmt.Errorf("get a coin request by ID: %w", err)
+	}
+
+	return &req, nil
+}
+
+func (r *postgresCoinRequestRepository) ListCoinRequests(ctx context.Context, userID int, direction, status string) ([]*models.CoinRequest, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	var userFilter string
+	switch direction {
+	case models.CoinRequestsIncoming:
+		userFilter = "payer_id = $1"
+	case models.CoinRequestsOutgoing:
+		userFilter = "requester_id = $1"
+	default:
+		userFilter = "(payer_id = $1 OR requester_id = $1)"
+	}
+
+	query := `SELECT ` + coinRequestColumns + `
+		FROM coin_requests
+		WHERE ` + userFilter + ` AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := pool.Query(ctx, query, userID, status)
+	if err != nil {
+		r.logger.Errorw("retrieving coin request list",
+			"error", err,
+			"userID", userID,
+		)
+		return nil, fmt.Errorf("retrieve coin request list: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []*models.CoinRequest
+	for rows.Next() {
+		var req models.CoinRequest
+		err := rows.Scan(
+			&req.ID,
+			&req.RequesterID,
+			&req.PayerID,
+			&req.Amount,
+			&req.Note,
+			&req.Status,
+			&req.TransactionID,
+			&req.CreatedAt,
+			&req.ExpiresAt,
+			&req.ResolvedAt,
+		)
+		if err != nil {
+			r.logger.Errorw("scanning coin request data",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading coin request data: %w", err)
+		}
+		requests = append(requests, &req)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return requests, nil
+}
+
+func (r *postgresCoinRequestRepository) ResolveCoinRequest(ctx context.Context, requestID int, status string, transactionID *int, resolvedAt time.Time) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		UPDATE coin_requests
+		SET status = $1, transaction_id = $2, resolved_at = $3
+		WHERE id = $4
+	`
+
+	result, err := pool.Exec(ctx, query, status, transactionID, resolvedAt, requestID)
+	if err != nil {
+		r.logger.Errorw("resolving coin request",
+			"error", err,
+			"requestID", requestID,
+			"status", status,
+		)
+		return fmt.Errorf("resolve coin request: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("coin request with ID %d not found", requestID)
+	}
+
+	return nil
+}
+
+func (r *postgresCoinRequestRepository) ExpireCoinRequests(ctx context.Context, now time.Time) (int, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		UPDATE coin_requests
+		SET status = 'expired', resolved_at = $1
+		WHERE status = 'pending' AND expires_at <= $1
+	`
+
+	result, err := pool.Exec(ctx, query, now)
+	if err != nil {
+		r.logger.Errorw("expiring coin requests",
+			"error", err,
+		)
+		return 0, fmt.Errorf("expire coin requests: %w", err)
+	}
+
+	return int(result.RowsAffected()), nil
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db"
+	"merch-store-grpc/pkg/logger"
+)
+
+type postgresGrantRepository struct {
+	conn   db.TxManager
+	logger logger.Logger
+}
+
+func NewGrantRepository(conn db.TxManager, log logger.Logger) db.GrantRepository {
+	return &postgresGrantRepository{conn: conn, logger: log}
+}
+
+func (r *postgresGrantRepository) CreateGrantBatch(ctx context.Context, batch *models.GrantBatch) (int, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		INSERT INTO grant_batches (granted_by, row_count, total_amount, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	var batchID int
+	err := pool.QueryRow(ctx, query, batch.GrantedBy, batch.RowCount, batch.TotalAmount, batch.CreatedAt).Scan(&batchID)
+	if err != nil {
+		r.logger.Errorw("creating grant batch",
+			"error", err,
+			"grantedBy", batch.GrantedBy,
+		)
+		return 0, fmt.Errorf("create grant batch: %w", err)
+	}
+
+	return batchID, nil
+}
+
+func (r *postgresGrantRepository) CreateCoinGrant(ctx context.Context, grant *models.CoinGrant) (int, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		INSERT INTO coin_grants (user_id, amount, reason, granted_by, batch_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	var grantID int
+	err := pool.QueryRow(ctx, query,
+		grant.UserID, grant.Amount, grant.Reason, grant.GrantedBy, grant.BatchID, grant.CreatedAt,
+	).Scan(&grantID)
+	if err != nil {
+		r.logger.Errorw("creating coin grant",
+			"error", err,
+			"userID", grant.UserID,
+			"grantedBy", grant.GrantedBy,
+		)
+		return 0, fmt.Errorf("create coin grant: %w", err)
+	}
+
+	return grantID, nil
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db"
+	"merch-store-grpc/pkg/logger"
+	"time"
+)
+
+type postgresHistoryRepository struct {
+	conn   db.TxManager
+	logger logger.Logger
+}
+
+func NewHistoryRepository(conn db.TxManager, log logger.Logger) db.HistoryRepository {
+	return &postgresHistoryRepository{conn: conn, logger: log}
+}
+
+// StreamHistory построчно отдаёт покупки и переводы пользователя в хронологическом порядке,
+// не загружая всю историю в память.
+func (r *postgresHistoryRepository) StreamHistory(ctx context.Context, userID int, from, to *time.Time, fn func(*models.HistoryRecord) error) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT kind, id, created_at, merch_name, price, sender_id, receiver_id, amount, currency
+		FROM (
+			SELECT 'purchase' AS kind, id, created_at, merch_name, COALESCE(price, 0) AS price,
+			       0 AS sender_id, 0 AS receiver_id, 0 AS amount, currency
+			FROM purchases
+			WHERE user_id = $1
+			UNION ALL
+			SELECT 'transaction', id, created_at, '', 0,
+			       COALESCE(sender_id, 0), COALESCE(receiver_id, 0), amount, currency
+			FROM transactions
+			WHERE sender_id = $1 OR receiver_id = $1
+		) h
+		WHERE ($2::timestamp IS NULL OR created_at >= $2)
+		  AND ($3::timestamp IS NULL OR created_at < $3)
+		ORDER BY created_at, kind, id
+	`
+
+	rows, err := pool.Query(ctx, query, userID, from, to)
+	if err != nil {
+		r.logger.Errorw("streaming history",
+			"error", err,
+			"userID", userID,
+		)
+		return fmt.Errorf("stream history: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record models.HistoryRecord
+		err := rows.Scan(
+			&record.Kind,
+			&record.ID,
+			&record.CreatedAt,
+			&record.MerchName,
+			&record.Price,
+			&record.SenderID,
+			&record.ReceiverID,
+			&record.Amount,
+			&record.Currency,
+		)
+		if err != nil {
+			r.logger.Errorw("scanning history data",
+				"error", err,
+			)
+			return fmt.Errorf("reading history data: %w", err)
+		}
+		if err := fn(&record); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return fmt.Errorf("processing query result: %w", err)
+	}
+
+	return nil
+}
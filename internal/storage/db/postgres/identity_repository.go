@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db"
+	"merch-store-grpc/pkg/logger"
+	"time"
+)
+
+type postgresIdentityRepository struct {
+	conn   db.TxManager
+	logger logger.Logger
+}
+
+func NewIdentityRepository(conn db.TxManager, log logger.Logger) db.IdentityRepository {
+	return &postgresIdentityRepository{conn: conn, logger: log}
+}
+
+func (r *postgresIdentityRepository) GetUserIdentity(ctx context.Context, issuer, subject string) (*models.UserIdentity, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT issuer, subject, user_id, email, created_at, last_login_at
+		FROM user_identities
+		WHERE issuer = $1 AND subject = $2
+	`
+
+	var identity models.UserIdentity
+	err := pool.QueryRow(ctx, query, issuer, subject).Scan(
+		&identity.Issuer,
+		&identity.Subject,
+		&identity.UserID,
+		&identity.Email,
+		&identity.CreatedAt,
+		&identity.LastLoginAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get user identity: %w", err)
+	}
+
+	return &identity, nil
+}
+
+func (r *postgresIdentityRepository) CreateUserIdentity(ctx context.Context, identity *models.UserIdentity) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		INSERT INTO user_identities (issuer, subject, user_id, email, created_at, last_login_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := pool.Exec(ctx, query,
+		identity.Issuer, identity.Subject, identity.UserID, identity.Email, identity.CreatedAt, identity.LastLoginAt,
+	)
+	if err != nil {
+		r.logger.Errorw("creating user identity",
+			"error", err,
+			"userID", identity.UserID,
+		)
+		return fmt.Errorf("create user identity: %w", err)
+	}
+
+	return nil
+}
+
+// TouchUserIdentity обновляет время входа и email, если он сменился у провайдера.
+func (r *postgresIdentityRepository) TouchUserIdentity(ctx context.Context, issuer, subject, email string, at time.Time) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		UPDATE user_identities
+		SET email = $3, last_login_at = $4
+		WHERE issuer = $1 AND subject = $2
+	`
+
+	if _, err := pool.Exec(ctx, query, issuer, subject, email, at); err != nil {
+		return fmt.Errorf("touch user identity: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db"
+	"merch-store-grpc/pkg/logger"
+)
+
+type postgresLedgerRepository struct {
+	conn   db.TxManager
+	logger logger.Logger
+}
+
+func NewLedgerRepository(conn db.TxManager, log logger.Logger) db.LedgerRepository {
+	return &postgresLedgerRepository{conn: conn, logger: log}
+}
+
+func (r *postgresLedgerRepository) CreateLedgerEntry(ctx context.Context, entry *models.LedgerEntry) (int, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		INSERT INTO ledger_entries (user_id, entry_type, amount, lot_id, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	var entryID int
+	err := pool.QueryRow(ctx, query, entry.UserID, entry.EntryType, entry.Amount, entry.LotID, entry.CreatedAt).Scan(&entryID)
+	if err != nil {
+		r.logger.Errorw("creating ledger entry",
+			"error", err,
+			"userID", entry.UserID,
+			"entryType", entry.EntryType,
+		)
+		return 0, fmt.Errorf("create ledger entry: %w", err)
+	}
+
+	return entryID, nil
+}
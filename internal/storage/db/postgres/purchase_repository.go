@@ -6,6 +6,7 @@ import (
 	"merch-store-grpc/internal/models"
 	"merch-store-grpc/internal/storage/db"
 	"merch-store-grpc/pkg/logger"
+	"strings"
 )
 
 type postgresPurchaseRepository struct {
@@ -21,13 +22,13 @@ func (r *postgresPurchaseRepository) CreatePurchase(ctx context.Context, purchas
 	pool := r.conn.GetExecutor(ctx)
 
 	query := `
-        INSERT INTO purchases (user_id, merch_name, price)
-        VALUES ($1, $2, $3)
+        INSERT INTO purchases (user_id, merch_name, price, currency)
+        VALUES ($1, $2, $3, $4)
         RETURNING id
     `
 
 	var purchaseID int
-	err := pool.QueryRow(ctx, query, purchase.UserID, purchase.MerchName, purchase.Price).Scan(&purchaseID)
+	err := pool.QueryRow(ctx, query, purchase.UserID, purchase.MerchName, purchase.Price, purchase.Currency).Scan(&purchaseID)
 	if err != nil {
 		r.logger.Errorw("creating purchase",
 			"error", err,
@@ -44,7 +45,7 @@ func (r *postgresPurchaseRepository) GetPurchaseByUserID(ctx context.Context, us
 	pool := r.conn.GetExecutor(ctx)
 
 	query := `
-        SELECT id, user_id, merch_name, price, created_at
+        SELECT id, user_id, merch_name, price, currency, created_at
         FROM purchases
         WHERE user_id = $1
     `
@@ -67,6 +68,133 @@ func (r *postgresPurchaseRepository) GetPurchaseByUserID(ctx context.Context, us
 			&purchase.UserID,
 			&purchase.MerchName,
 			&purchase.Price,
+			&purchase.Currency,
+			&purchase.CreatedAt,
+		)
+		if err != nil {
+			r.logger.Errorw("scanning purchase data",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading purchase data: %w", err)
+		}
+		purchases = append(purchases, &purchase)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return purchases, nil
+}
+
+// GetInventoryByUserID возвращает количество купленных единиц каждого товара.
+func (r *postgresPurchaseRepository) GetInventoryByUserID(ctx context.Context, userID int) ([]*models.InventoryItem, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+        SELECT merch_name, COUNT(*)
+        FROM purchases
+        WHERE user_id = $1
+        GROUP BY merch_name
+        ORDER BY merch_name
+    `
+
+	rows, err := pool.Query(ctx, query, userID)
+	if err != nil {
+		r.logger.Errorw("retrieving inventory",
+			"error", err,
+			"userID", userID,
+		)
+		return nil, fmt.Errorf("retrieve inventory: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*models.InventoryItem
+	for rows.Next() {
+		var item models.InventoryItem
+		if err := rows.Scan(&item.MerchName, &item.Quantity); err != nil {
+			r.logger.Errorw("scanning inventory data",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading inventory data: %w", err)
+		}
+		items = append(items, &item)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return items, nil
+}
+
+// ListPurchases возвращает покупки пользователя по фильтру, от новых к старым.
+// Пагинация keyset по (created_at, id).
+func (r *postgresPurchaseRepository) ListPurchases(ctx context.Context, filter *models.PurchaseFilter) ([]*models.Purchase, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	args := []any{filter.UserID}
+	conds := []string{"user_id = $1"}
+
+	addArg := func(cond string, v any) {
+		args = append(args, v)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.MerchName != "" {
+		addArg("merch_name = $%d", filter.MerchName)
+	}
+	if filter.MinPrice > 0 {
+		addArg("price >= $%d", filter.MinPrice)
+	}
+	if filter.MaxPrice > 0 {
+		addArg("price <= $%d", filter.MaxPrice)
+	}
+	if filter.From != nil {
+		addArg("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addArg("created_at < $%d", *filter.To)
+	}
+	if filter.After != nil {
+		args = append(args, filter.After.CreatedAt, filter.After.ID)
+		conds = append(conds, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf(`
+        SELECT id, user_id, merch_name, price, currency, created_at
+        FROM purchases
+        WHERE %s
+        ORDER BY created_at DESC, id DESC
+        LIMIT $%d
+    `, strings.Join(conds, " AND "), len(args))
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorw("listing purchases",
+			"error", err,
+			"userID", filter.UserID,
+		)
+		return nil, fmt.Errorf("list purchases: %w", err)
+	}
+	defer rows.Close()
+
+	var purchases []*models.Purchase
+	for rows.Next() {
+		var purchase models.Purchase
+		err := rows.Scan(
+			&purchase.ID,
+			&purchase.UserID,
+			&purchase.MerchName,
+			&purchase.Price,
+			&purchase.Currency,
 			&purchase.CreatedAt,
 		)
 		if err != nil {
@@ -0,0 +1,333 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db"
+	"merch-store-grpc/pkg/logger"
+	"time"
+)
+
+type postgresRefreshTokenRepository struct {
+	conn   db.TxManager
+	logger logger.Logger
+}
+
+func NewRefreshTokenRepository(conn db.TxManager, log logger.Logger) db.RefreshTokenRepository {
+	return &postgresRefreshTokenRepository{conn: conn, logger: log}
+}
+
+func (r *postgresRefreshTokenRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) (int, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		INSERT INTO refresh_tokens
+			(user_id, session_id, token_hash, access_jti, access_expires_at, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	var tokenID int
+	err := pool.QueryRow(ctx, query,
+		token.UserID, token.SessionID, token.TokenHash, token.AccessJTI,
+		token.AccessExpiresAt, token.CreatedAt, token.ExpiresAt,
+	).Scan(&tokenID)
+	if err != nil {
+		r.logger.Errorw("creating refresh token",
+			"error", err,
+			"userID", token.UserID,
+		)
+		return 0, fmt.Errorf("create refresh token: %w", err)
+	}
+
+	return tokenID, nil
+}
+
+// GetRefreshTokenForUpdate ищет токен по хэшу и блокирует строку до конца транзакции.
+func (r *postgresRefreshTokenRepository) GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT id, user_id, session_id, token_hash, access_jti, access_expires_at,
+		       created_at, expires_at, revoked_at, replaced_by
+		FROM refresh_tokens
+		WHERE token_hash = $1
+		FOR UPDATE
+	`
+
+	var token models.RefreshToken
+	err := pool.QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.SessionID,
+		&token.TokenHash,
+		&token.AccessJTI,
+		&token.AccessExpiresAt,
+		&token.CreatedAt,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.ReplacedBy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get refresh token: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (r *postgresRefreshTokenRepository) RevokeRefreshToken(ctx context.Context, tokenID int, replacedBy *int, revokedAt time.Time) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $2, replaced_by = $3
+		WHERE id = $1
+	`
+
+	_, err := pool.Exec(ctx, query, tokenID, revokedAt, replacedBy)
+	if err != nil {
+		r.logger.Errorw("revoking refresh token",
+			"error", err,
+			"tokenID", tokenID,
+		)
+		return fmt.Errorf("revoke refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeSession отзывает все активные токены сессии и возвращает их, чтобы
+// вызывающий код мог занести выданные по ним access-токены в список отзыва.
+// Сама сессия в user_sessions помечается отозванной тем же запросом.
+func (r *postgresRefreshTokenRepository) RevokeSession(ctx context.Context, sessionID string, revokedAt time.Time) ([]*models.RefreshToken, error) {
+	query := `
+		WITH revoked_sessions AS (
+			UPDATE user_sessions
+			SET revoked_at = $2
+			WHERE id = $1 AND revoked_at IS NULL
+		)
+		UPDATE refresh_tokens
+		SET revoked_at = $2
+		WHERE session_id = $1 AND revoked_at IS NULL
+		RETURNING id, user_id, session_id, token_hash, access_jti, access_expires_at,
+		          created_at, expires_at, revoked_at, replaced_by
+	`
+
+	return r.revoke(ctx, query, sessionID, revokedAt)
+}
+
+// RevokeUserSessions отзывает активные токены всех сессий пользователя.
+func (r *postgresRefreshTokenRepository) RevokeUserSessions(ctx context.Context, userID int, revokedAt time.Time) ([]*models.RefreshToken, error) {
+	query := `
+		WITH revoked_sessions AS (
+			UPDATE user_sessions
+			SET revoked_at = $2
+			WHERE user_id = $1 AND revoked_at IS NULL
+		)
+		UPDATE refresh_tokens
+		SET revoked_at = $2
+		WHERE user_id = $1 AND revoked_at IS NULL
+		RETURNING id, user_id, session_id, token_hash, access_jti, access_expires_at,
+		          created_at, expires_at, revoked_at, replaced_by
+	`
+
+	return r.revoke(ctx, query, userID, revokedAt)
+}
+
+func (r *postgresRefreshTokenRepository) revoke(ctx context.Context, query string, key any, revokedAt time.Time) ([]*models.RefreshToken, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	rows, err := pool.Query(ctx, query, key, revokedAt)
+	if err != nil {
+		r.logger.Errorw("revoking refresh tokens",
+			"error", err,
+			"key", key,
+		)
+		return nil, fmt.Errorf("revoke refresh tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*models.RefreshToken
+	for rows.Next() {
+		var token models.RefreshToken
+		err := rows.Scan(
+			&token.ID,
+			&token.UserID,
+			&token.SessionID,
+			&token.TokenHash,
+			&token.AccessJTI,
+			&token.AccessExpiresAt,
+			&token.CreatedAt,
+			&token.ExpiresAt,
+			&token.RevokedAt,
+			&token.ReplacedBy,
+		)
+		if err != nil {
+			r.logger.Errorw("scanning refresh token",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading refresh token: %w", err)
+		}
+		tokens = append(tokens, &token)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (r *postgresRefreshTokenRepository) CreateSession(ctx context.Context, session *models.Session) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		INSERT INTO user_sessions (id, user_id, user_agent, ip, created_at, last_seen_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := pool.Exec(ctx, query,
+		session.ID, session.UserID, session.UserAgent, session.IP,
+		session.CreatedAt, session.LastSeenAt, session.ExpiresAt,
+	)
+	if err != nil {
+		r.logger.Errorw("creating session",
+			"error", err,
+			"userID", session.UserID,
+		)
+		return fmt.Errorf("create session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postgresRefreshTokenRepository) GetSession(ctx context.Context, sessionID string) (*models.Session, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT id, user_id, user_agent, ip, created_at, last_seen_at, expires_at, revoked_at
+		FROM user_sessions
+		WHERE id = $1
+	`
+
+	var session models.Session
+	err := pool.QueryRow(ctx, query, sessionID).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.UserAgent,
+		&session.IP,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// ListActiveSessions возвращает неотозванные и неистёкшие сессии, недавно активные — первыми.
+func (r *postgresRefreshTokenRepository) ListActiveSessions(ctx context.Context, userID int, now time.Time) ([]*models.Session, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT id, user_id, user_agent, ip, created_at, last_seen_at, expires_at, revoked_at
+		FROM user_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_seen_at DESC
+	`
+
+	rows, err := pool.Query(ctx, query, userID, now)
+	if err != nil {
+		r.logger.Errorw("listing sessions",
+			"error", err,
+			"userID", userID,
+		)
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*models.Session
+	for rows.Next() {
+		var session models.Session
+		err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.UserAgent,
+			&session.IP,
+			&session.CreatedAt,
+			&session.LastSeenAt,
+			&session.ExpiresAt,
+			&session.RevokedAt,
+		)
+		if err != nil {
+			r.logger.Errorw("scanning session",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading session: %w", err)
+		}
+		sessions = append(sessions, &session)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// ExtendSession отмечает активность сессии при обновлении токенов и продлевает её до срока нового refresh-токена.
+func (r *postgresRefreshTokenRepository) ExtendSession(ctx context.Context, sessionID string, seenAt, expiresAt time.Time) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		UPDATE user_sessions
+		SET last_seen_at = $2, expires_at = $3
+		WHERE id = $1
+	`
+
+	_, err := pool.Exec(ctx, query, sessionID, seenAt, expiresAt)
+	if err != nil {
+		r.logger.Errorw("extending session",
+			"error", err,
+			"sessionID", sessionID,
+		)
+		return fmt.Errorf("extend session: %w", err)
+	}
+
+	return nil
+}
+
+// TouchSession отмечает активность сессии по запросу с access-токеном и запоминает последние
+// адрес и User-Agent клиента (пустые значения не затирают сохранённые).
+func (r *postgresRefreshTokenRepository) TouchSession(ctx context.Context, sessionID string, seenAt time.Time, ip, userAgent string) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		UPDATE user_sessions
+		SET last_seen_at = GREATEST(last_seen_at, $2),
+		    ip = COALESCE(NULLIF($3, ''), ip),
+		    user_agent = COALESCE(NULLIF($4, ''), user_agent)
+		WHERE id = $1 AND revoked_at IS NULL
+	`
+
+	_, err := pool.Exec(ctx, query, sessionID, seenAt, ip, userAgent)
+	if err != nil {
+		r.logger.Errorw("touching session",
+			"error", err,
+			"sessionID", sessionID,
+		)
+		return fmt.Errorf("touch session: %w", err)
+	}
+
+	return nil
+}
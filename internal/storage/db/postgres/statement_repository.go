@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db"
+	"merch-store-grpc/pkg/logger"
+	"time"
+)
+
+type postgresStatementRepository struct {
+	conn   db.TxManager
+	logger logger.Logger
+}
+
+func NewStatementRepository(conn db.TxManager, log logger.Logger) db.StatementRepository {
+	return &postgresStatementRepository{conn: conn, logger: log}
+}
+
+// userMovementsQuery — все изменения баланса пользователя $1 со знаком.
+// Учитывается только основная валюта: выписка сходится с users.balance. Часть перевода,
+// оплаченная из лимита на подарки, баланс отправителя не меняет.
+const userMovementsQuery = `
+	SELECT 'purchase' AS category, id, created_at, -COALESCE(price, 0) AS amount,
+	       merch_name AS description, 0 AS counterparty_id
+	FROM purchases
+	WHERE user_id = $1 AND currency = 'coin'
+	UNION ALL
+	SELECT 'transfer_out', id, created_at,
+	       -(amount - CASE WHEN reversal_of IS NULL THEN allowance_amount ELSE 0 END), '', COALESCE(receiver_id, 0)
+	FROM transactions
+	WHERE sender_id = $1 AND currency = 'coin'
+	UNION ALL
+	SELECT 'transfer_in', id, created_at,
+	       amount - CASE WHEN reversal_of IS NOT NULL THEN allowance_amount ELSE 0 END, '', COALESCE(sender_id, 0)
+	FROM transactions
+	WHERE receiver_id = $1 AND currency = 'coin'
+	UNION ALL
+	SELECT 'grant', id, created_at, amount, reason, COALESCE(granted_by, 0)
+	FROM coin_grants
+	WHERE user_id = $1
+	UNION ALL
+	SELECT 'expiration', id, created_at, amount, '', 0
+	FROM ledger_entries
+	WHERE user_id = $1 AND entry_type = 'expiration'
+`
+
+func (r *postgresStatementRepository) GetMovements(ctx context.Context, userID int, from, to time.Time) ([]*models.StatementMovement, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT category, id, created_at, amount, description, counterparty_id
+		FROM (` + userMovementsQuery + `) m
+		WHERE created_at >= $2 AND created_at < $3
+		ORDER BY created_at, category, id
+	`
+
+	rows, err := pool.Query(ctx, query, userID, from, to)
+	if err != nil {
+		r.logger.Errorw("retrieving statement movements",
+			"error", err,
+			"userID", userID,
+		)
+		return nil, fmt.Errorf("retrieve statement movements: %w", err)
+	}
+	defer rows.Close()
+
+	var movements []*models.StatementMovement
+	for rows.Next() {
+		var m models.StatementMovement
+		err := rows.Scan(
+			&m.Category,
+			&m.ID,
+			&m.CreatedAt,
+			&m.Amount,
+			&m.Description,
+			&m.CounterpartyID,
+		)
+		if err != nil {
+			r.logger.Errorw("scanning statement movement",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading statement movement: %w", err)
+		}
+		movements = append(movements, &m)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return movements, nil
+}
+
+// GetNetMovementSince возвращает суммарное изменение баланса начиная с since.
+func (r *postgresStatementRepository) GetNetMovementSince(ctx context.Context, userID int, since time.Time) (int, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM (` + userMovementsQuery + `) m
+		WHERE created_at >= $2
+	`
+
+	var net int
+	if err := pool.QueryRow(ctx, query, userID, since).Scan(&net); err != nil {
+		r.logger.Errorw("calculating net movement",
+			"error", err,
+			"userID", userID,
+		)
+		return 0, fmt.Errorf("calculate net movement: %w", err)
+	}
+
+	return net, nil
+}
+
+func (r *postgresStatementRepository) GetStatement(ctx context.Context, userID int, period time.Time) (*models.Statement, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT user_id, period, opening_balance, closing_balance, totals, movements, generated_at
+		FROM statements
+		WHERE user_id = $1 AND period = $2
+	`
+
+	var (
+		st        models.Statement
+		totals    []byte
+		movements []byte
+	)
+	err := pool.QueryRow(ctx, query, userID, period).Scan(
+		&st.UserID,
+		&st.Period,
+		&st.OpeningBalance,
+		&st.ClosingBalance,
+		&totals,
+		&movements,
+		&st.GeneratedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get statement: %w", err)
+	}
+
+	if err := json.Unmarshal(totals, &st.Totals); err != nil {
+		return nil, fmt.Errorf("decode statement totals: %w", err)
+	}
+	if err := json.Unmarshal(movements, &st.Movements); err != nil {
+		return nil, fmt.Errorf("decode statement movements: %w", err)
+	}
+
+	return &st, nil
+}
+
+// SaveStatement сохраняет выписку. Уже сохранённая выписка за период не перезаписывается.
+func (r *postgresStatementRepository) SaveStatement(ctx context.Context, st *models.Statement) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	totals, err := json.Marshal(st.Totals)
+	if err != nil {
+		return fmt.Errorf("encode statement totals: %w", err)
+	}
+	movements, err := json.Marshal(st.Movements)
+	if err != nil {
+		return fmt.Errorf("encode statement movements: %w", err)
+	}
+
+	query := `
+		INSERT INTO statements (user_id, period, opening_balance, closing_balance, totals, movements, generated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, period) DO NOTHING
+	`
+
+	_, err = pool.Exec(ctx, query,
+		st.UserID, st.Period, st.OpeningBalance, st.ClosingBalance, totals, movements, st.GeneratedAt,
+	)
+	if err != nil {
+		r.logger.Errorw("saving statement",
+			"error", err,
+			"userID", st.UserID,
+			"period", st.Period,
+		)
+		return fmt.Errorf("save statement: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"merch-store-grpc/internal/models"
+	"merch-store-grpc/internal/storage/db"
+	"merch-store-grpc/pkg/logger"
+	"time"
+)
+
+type postgresTOTPRepository struct {
+	conn   db.TxManager
+	logger logger.Logger
+}
+
+func NewTOTPRepository(conn db.TxManager, log logger.Logger) db.TOTPRepository {
+	return &postgresTOTPRepository{conn: conn, logger: log}
+}
+
+func (r *postgresTOTPRepository) GetTOTP(ctx context.Context, userID int) (*models.TOTP, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT user_id, secret, created_at, enabled_at, last_counter
+		FROM user_totp
+		WHERE user_id = $1
+	`
+
+	var t models.TOTP
+	err := pool.QueryRow(ctx, query, userID).Scan(
+		&t.UserID,
+		&t.Secret,
+		&t.CreatedAt,
+		&t.EnabledAt,
+		&t.LastCounter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get totp: %w", err)
+	}
+
+	return &t, nil
+}
+
+// SaveTOTPSecret сохраняет новый неподтверждённый секрет. Включённый второй фактор
+// не перезаписывается, тогда возвращается false.
+func (r *postgresTOTPRepository) SaveTOTPSecret(ctx context.Context, userID int, secret string, at time.Time) (bool, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		INSERT INTO user_totp (user_id, secret, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, created_at = EXCLUDED.created_at, last_counter = 0
+		WHERE user_totp.enabled_at IS NULL
+	`
+
+	tag, err := pool.Exec(ctx, query, userID, secret, at)
+	if err != nil {
+		r.logger.Errorw("saving totp secret",
+			"error", err,
+			"userID", userID,
+		)
+		return false, fmt.Errorf("save totp secret: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// UseTOTPCounter запоминает принятый шаг. Шаг не больше уже принятого — повтор кода, тогда false.
+func (r *postgresTOTPRepository) UseTOTPCounter(ctx context.Context, userID int, counter int64) (bool, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		UPDATE user_totp
+		SET last_counter = $2
+		WHERE user_id = $1 AND last_counter < $2
+	`
+
+	tag, err := pool.Exec(ctx, query, userID, counter)
+	if err != nil {
+		return false, fmt.Errorf("use totp counter: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *postgresTOTPRepository) EnableTOTP(ctx context.Context, userID int, at time.Time) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		UPDATE user_totp
+		SET enabled_at = $2
+		WHERE user_id = $1 AND enabled_at IS NULL
+	`
+
+	if _, err := pool.Exec(ctx, query, userID, at); err != nil {
+		r.logger.Errorw("enabling totp",
+			"error", err,
+			"userID", userID,
+		)
+		return fmt.Errorf("enable totp: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTOTP отключает второй фактор вместе с кодами восстановления.
+func (r *postgresTOTPRepository) DeleteTOTP(ctx context.Context, userID int) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	if _, err := pool.Exec(ctx, `DELETE FROM totp_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("delete recovery codes: %w", err)
+	}
+	if _, err := pool.Exec(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("delete totp: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes заменяет коды восстановления пользователя новыми.
+func (r *postgresTOTPRepository) ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string, at time.Time) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	if _, err := pool.Exec(ctx, `DELETE FROM totp_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("delete recovery codes: %w", err)
+	}
+
+	query := `
+		INSERT INTO totp_recovery_codes (user_id, code_hash, created_at)
+		SELECT $1, h, $3
+		FROM unnest($2::text[]) AS h
+	`
+
+	if _, err := pool.Exec(ctx, query, userID, codeHashes, at); err != nil {
+		r.logger.Errorw("saving recovery codes",
+			"error", err,
+			"userID", userID,
+		)
+		return fmt.Errorf("save recovery codes: %w", err)
+	}
+
+	return nil
+}
+
+// UseRecoveryCode гасит код восстановления. Неизвестный или уже использованный код — false.
+func (r *postgresTOTPRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string, at time.Time) (bool, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		UPDATE totp_recovery_codes
+		SET used_at = $3
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	tag, err := pool.Exec(ctx, query, userID, codeHash, at)
+	if err != nil {
+		return false, fmt.Errorf("use recovery code: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
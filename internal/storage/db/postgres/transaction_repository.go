@@ -6,6 +6,7 @@ import (
 	"merch-store-grpc/internal/models"
 	"merch-store-grpc/internal/storage/db"
 	"merch-store-grpc/pkg/logger"
+	"strings"
 )
 
 type postgresTransactionRepository struct {
@@ -21,13 +22,16 @@ func (r *postgresTransactionRepository) CreateTransaction(ctx context.Context, t
 	pool := r.conn.GetExecutor(ctx)
 
 	query := `
-        INSERT INTO transactions (sender_id, receiver_id, amount, created_at)
-        VALUES ($1, $2, $3, $4)
+        INSERT INTO transactions (sender_id, receiver_id, amount, currency, created_at, reversal_of, allowance_amount)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
         RETURNING id
     `
 
 	var transactionID int
-	err := pool.QueryRow(ctx, query, transaction.SenderID, transaction.ReceiverID, transaction.Amount, transaction.CreatedAt).Scan(&transactionID)
+	err := pool.QueryRow(ctx, query,
+		transaction.SenderID, transaction.ReceiverID, transaction.Amount, transaction.Currency, transaction.CreatedAt, transaction.ReversalOf,
+		transaction.AllowanceAmount,
+	).Scan(&transactionID)
 	if err != nil {
 		r.logger.Errorw("creating transaction",
 			"error", err,
@@ -44,7 +48,7 @@ func (r *postgresTransactionRepository) GetTransactionByUserID(ctx context.Conte
 	pool := r.conn.GetExecutor(ctx)
 
 	query := `
-        SELECT id, sender_id, receiver_id, amount, created_at
+        SELECT id, sender_id, receiver_id, amount, currency, created_at, reversal_of, allowance_amount
         FROM transactions
         WHERE sender_id = $1 OR receiver_id = $1
     `
@@ -67,7 +71,10 @@ func (r *postgresTransactionRepository) GetTransactionByUserID(ctx context.Conte
 			&transaction.SenderID,
 			&transaction.ReceiverID,
 			&transaction.Amount,
+			&transaction.Currency,
 			&transaction.CreatedAt,
+			&transaction.ReversalOf,
+			&transaction.AllowanceAmount,
 		)
 		if err != nil {
 			r.logger.Errorw("scanning transaction data",
@@ -87,3 +94,258 @@ func (r *postgresTransactionRepository) GetTransactionByUserID(ctx context.Conte
 
 	return transactions, nil
 }
+
+// GetReceivedByCounterparty суммирует входящие переводы пользователя по отправителям.
+func (r *postgresTransactionRepository) GetReceivedByCounterparty(ctx context.Context, userID int) ([]*models.CoinMovement, error) {
+	query := `
+        SELECT COALESCE(u.username, ''), SUM(t.amount), t.currency
+        FROM transactions t
+        LEFT JOIN users u ON u.id = t.sender_id
+        WHERE t.receiver_id = $1
+        GROUP BY u.username, t.currency
+        ORDER BY t.currency, SUM(t.amount) DESC, u.username
+    `
+
+	return r.queryMovements(ctx, query, userID)
+}
+
+// GetSentByCounterparty суммирует исходящие переводы пользователя по получателям.
+func (r *postgresTransactionRepository) GetSentByCounterparty(ctx context.Context, userID int) ([]*models.CoinMovement, error) {
+	query := `
+        SELECT COALESCE(u.username, ''), SUM(t.amount), t.currency
+        FROM transactions t
+        LEFT JOIN users u ON u.id = t.receiver_id
+        WHERE t.sender_id = $1
+        GROUP BY u.username, t.currency
+        ORDER BY t.currency, SUM(t.amount) DESC, u.username
+    `
+
+	return r.queryMovements(ctx, query, userID)
+}
+
+func (r *postgresTransactionRepository) queryMovements(ctx context.Context, query string, userID int) ([]*models.CoinMovement, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	rows, err := pool.Query(ctx, query, userID)
+	if err != nil {
+		r.logger.Errorw("retrieving grouped transactions",
+			"error", err,
+			"userID", userID,
+		)
+		return nil, fmt.Errorf("retrieve grouped transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var movements []*models.CoinMovement
+	for rows.Next() {
+		var movement models.CoinMovement
+		if err := rows.Scan(&movement.Username, &movement.Amount, &movement.Currency); err != nil {
+			r.logger.Errorw("scanning grouped transaction data",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading grouped transaction data: %w", err)
+		}
+		movements = append(movements, &movement)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return movements, nil
+}
+
+// ListTransactions возвращает переводы пользователя по фильтру, от новых к старым.
+// Пагинация keyset по (created_at, id).
+func (r *postgresTransactionRepository) ListTransactions(ctx context.Context, filter *models.TransactionFilter) ([]*models.Transaction, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	args := []any{filter.UserID}
+	var conds []string
+
+	switch filter.Direction {
+	case models.DirectionSent:
+		conds = append(conds, "sender_id = $1")
+	case models.DirectionReceived:
+		conds = append(conds, "receiver_id = $1")
+	default:
+		conds = append(conds, "(sender_id = $1 OR receiver_id = $1)")
+	}
+
+	addArg := func(cond string, v any) {
+		args = append(args, v)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.CounterpartyID > 0 {
+		switch filter.Direction {
+		case models.DirectionSent:
+			addArg("receiver_id = $%d", filter.CounterpartyID)
+		case models.DirectionReceived:
+			addArg("sender_id = $%d", filter.CounterpartyID)
+		default:
+			args = append(args, filter.CounterpartyID)
+			n := len(args)
+			conds = append(conds, fmt.Sprintf("(sender_id = $%d OR receiver_id = $%d)", n, n))
+		}
+	}
+	if filter.Currency != "" {
+		addArg("currency = $%d", filter.Currency)
+	}
+	if filter.MinAmount > 0 {
+		addArg("amount >= $%d", filter.MinAmount)
+	}
+	if filter.MaxAmount > 0 {
+		addArg("amount <= $%d", filter.MaxAmount)
+	}
+	if filter.From != nil {
+		addArg("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addArg("created_at < $%d", *filter.To)
+	}
+	if filter.After != nil {
+		args = append(args, filter.After.CreatedAt, filter.After.ID)
+		conds = append(conds, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	args = append(args, filter.Limit)
+	query := fmt.Sprintf(`
+        SELECT id, sender_id, receiver_id, amount, currency, created_at, reversal_of, allowance_amount
+        FROM transactions
+        WHERE %s
+        ORDER BY created_at DESC, id DESC
+        LIMIT $%d
+    `, strings.Join(conds, " AND "), len(args))
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Errorw("listing transactions",
+			"error", err,
+			"userID", filter.UserID,
+		)
+		return nil, fmt.Errorf("list transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []*models.Transaction
+	for rows.Next() {
+		var transaction models.Transaction
+		err := rows.Scan(
+			&transaction.ID,
+			&transaction.SenderID,
+			&transaction.ReceiverID,
+			&transaction.Amount,
+			&transaction.Currency,
+			&transaction.CreatedAt,
+			&transaction.ReversalOf,
+			&transaction.AllowanceAmount,
+		)
+		if err != nil {
+			r.logger.Errorw("scanning transaction data",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading transaction data: %w", err)
+		}
+		transactions = append(transactions, &transaction)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// GetTransactionForUpdate возвращает перевод и блокирует его строку до конца транзакции.
+func (r *postgresTransactionRepository) GetTransactionForUpdate(ctx context.Context, transactionID int) (*models.Transaction, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+        SELECT id, COALESCE(sender_id, 0), COALESCE(receiver_id, 0), amount, currency, created_at, reversal_of, allowance_amount
+        FROM transactions
+        WHERE id = $1
+        FOR UPDATE
+    `
+
+	var transaction models.Transaction
+	err := pool.QueryRow(ctx, query, transactionID).Scan(
+		&transaction.ID,
+		&transaction.SenderID,
+		&transaction.ReceiverID,
+		&transaction.Amount,
+		&transaction.Currency,
+		&transaction.CreatedAt,
+		&transaction.ReversalOf,
+		&transaction.AllowanceAmount,
+	)
+	if err != nil {
+		r.logger.Warnw("getting a transaction by ID",
+			"error", err,
+			"transactionID", transactionID,
+		)
+		return nil, fmt.Errorf("get a transaction by ID: %w", err)
+	}
+
+	return &transaction, nil
+}
+
+// GetReversalOf возвращает компенсирующий перевод для transactionID, если он есть.
+func (r *postgresTransactionRepository) GetReversalOf(ctx context.Context, transactionID int) (*models.Transaction, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+        SELECT id, COALESCE(sender_id, 0), COALESCE(receiver_id, 0), amount, currency, created_at, reversal_of, allowance_amount
+        FROM transactions
+        WHERE reversal_of = $1
+    `
+
+	var transaction models.Transaction
+	err := pool.QueryRow(ctx, query, transactionID).Scan(
+		&transaction.ID,
+		&transaction.SenderID,
+		&transaction.ReceiverID,
+		&transaction.Amount,
+		&transaction.Currency,
+		&transaction.CreatedAt,
+		&transaction.ReversalOf,
+		&transaction.AllowanceAmount,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get reversal of transaction: %w", err)
+	}
+
+	return &transaction, nil
+}
+
+func (r *postgresTransactionRepository) CreateTransactionReversal(ctx context.Context, reversal *models.TransactionReversal) (int, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+        INSERT INTO transaction_reversals
+            (original_id, reversal_id, requested_amount, reversed_amount, reason, reversed_by, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING id
+    `
+
+	var reversalID int
+	err := pool.QueryRow(ctx, query,
+		reversal.OriginalID, reversal.ReversalID, reversal.RequestedAmount, reversal.ReversedAmount,
+		reversal.Reason, reversal.ReversedBy, reversal.CreatedAt,
+	).Scan(&reversalID)
+	if err != nil {
+		r.logger.Errorw("creating transaction reversal",
+			"error", err,
+			"originalID", reversal.OriginalID,
+		)
+		return 0, fmt.Errorf("create transaction reversal: %w", err)
+	}
+
+	return reversalID, nil
+}
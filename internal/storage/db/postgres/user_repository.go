@@ -2,13 +2,18 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
 	"merch-store-grpc/internal/models"
 	"merch-store-grpc/internal/storage/db"
 	"merch-store-grpc/pkg/logger"
 	"time"
 )
 
+// uniqueViolationCode — SQLSTATE нарушения уникального ограничения.
+const uniqueViolationCode = "23505"
+
 type postgresUserRepository struct {
 	conn   db.TxManager
 	logger logger.Logger
@@ -22,16 +27,20 @@ func (r *postgresUserRepository) CreateUser(ctx context.Context, user *models.Us
 	pool := r.conn.GetExecutor(ctx)
 
 	query := `
-		INSERT INTO users (username, password_hash, balance, created_at)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id
+		INSERT INTO users (username, password_hash, balance, is_service_account, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, roles
 	`
 
 	user.CreatedAt = time.Now()
 
 	var userID int
-	err := pool.QueryRow(ctx, query, user.Username, user.PasswordHash, user.Balance, user.CreatedAt).Scan(&userID)
+	err := pool.QueryRow(ctx, query, user.Username, user.PasswordHash, user.Balance, user.IsServiceAccount, user.CreatedAt).Scan(&userID, &user.Roles)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			return 0, fmt.Errorf("create user: %w", db.ErrDuplicateKey)
+		}
 		r.logger.Errorw("creating a user",
 			"error", err,
 			"username", user.Username,
@@ -46,7 +55,7 @@ func (r *postgresUserRepository) GetUserByID(ctx context.Context, userID int) (*
 	pool := r.conn.GetExecutor(ctx)
 
 	query := `
-		SELECT id, username, password_hash, balance, created_at
+		SELECT id, username, password_hash, balance, roles, is_service_account, created_at
 		FROM users
 		WHERE id = $1
 	`
@@ -57,6 +66,8 @@ func (r *postgresUserRepository) GetUserByID(ctx context.Context, userID int) (*
 		&user.Username,
 		&user.PasswordHash,
 		&user.Balance,
+		&user.Roles,
+		&user.IsServiceAccount,
 		&user.CreatedAt,
 	)
 	if err != nil {
@@ -74,7 +85,7 @@ func (r *postgresUserRepository) GetUserByUsername(ctx context.Context, username
 	pool := r.conn.GetExecutor(ctx)
 
 	query := `
-		SELECT id, username, password_hash, balance, created_at
+		SELECT id, username, password_hash, balance, roles, is_service_account, created_at
 		FROM users
 		WHERE username = $1
 	`
@@ -85,6 +96,8 @@ func (r *postgresUserRepository) GetUserByUsername(ctx context.Context, username
 		&user.Username,
 		&user.PasswordHash,
 		&user.Balance,
+		&user.Roles,
+		&user.IsServiceAccount,
 		&user.CreatedAt,
 	)
 	if err != nil {
@@ -98,6 +111,259 @@ func (r *postgresUserRepository) GetUserByUsername(ctx context.Context, username
 	return &user, nil
 }
 
+func (r *postgresUserRepository) GetUsersByUsernames(ctx context.Context, usernames []string) ([]*models.User, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT id, username, password_hash, balance, roles, is_service_account, created_at
+		FROM users
+		WHERE username = ANY($1)
+	`
+
+	rows, err := pool.Query(ctx, query, usernames)
+	if err != nil {
+		r.logger.Errorw("retrieving users by usernames",
+			"error", err,
+		)
+		return nil, fmt.Errorf("retrieve users by usernames: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.PasswordHash,
+			&user.Balance,
+			&user.Roles,
+			&user.IsServiceAccount,
+			&user.CreatedAt,
+		)
+		if err != nil {
+			r.logger.Errorw("scanning user data",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading user data: %w", err)
+		}
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *postgresUserRepository) GetUsersByIDs(ctx context.Context, userIDs []int) ([]*models.User, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT id, username, password_hash, balance, roles, is_service_account, created_at
+		FROM users
+		WHERE id = ANY($1)
+	`
+
+	rows, err := pool.Query(ctx, query, userIDs)
+	if err != nil {
+		r.logger.Errorw("retrieving users by IDs",
+			"error", err,
+		)
+		return nil, fmt.Errorf("retrieve users by IDs: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.PasswordHash,
+			&user.Balance,
+			&user.Roles,
+			&user.IsServiceAccount,
+			&user.CreatedAt,
+		)
+		if err != nil {
+			r.logger.Errorw("scanning user data",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading user data: %w", err)
+		}
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return users, nil
+}
+
+// LockUsersByIDs блокирует строки пользователей в порядке возрастания id,
+// чтобы параллельные транзакции брали блокировки в одном порядке и не попадали в deadlock.
+func (r *postgresUserRepository) LockUsersByIDs(ctx context.Context, userIDs []int) ([]*models.User, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT id, username, password_hash, balance, roles, is_service_account, created_at
+		FROM users
+		WHERE id = ANY($1)
+		ORDER BY id
+		FOR UPDATE
+	`
+
+	rows, err := pool.Query(ctx, query, userIDs)
+	if err != nil {
+		r.logger.Errorw("locking users",
+			"error", err,
+			"userIDs", userIDs,
+		)
+		return nil, fmt.Errorf("lock users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.PasswordHash,
+			&user.Balance,
+			&user.Roles,
+			&user.IsServiceAccount,
+			&user.CreatedAt,
+		)
+		if err != nil {
+			r.logger.Errorw("scanning user data",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading user data: %w", err)
+		}
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return users, nil
+}
+
+// ListUsers возвращает пользователей с id больше afterID, упорядоченных по id (keyset-пагинация).
+func (r *postgresUserRepository) ListUsers(ctx context.Context, afterID, limit int) ([]*models.User, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		SELECT id, username, password_hash, balance, roles, is_service_account, created_at
+		FROM users
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+
+	rows, err := pool.Query(ctx, query, afterID, limit)
+	if err != nil {
+		r.logger.Errorw("retrieving user list",
+			"error", err,
+			"afterID", afterID,
+		)
+		return nil, fmt.Errorf("retrieve user list: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.PasswordHash,
+			&user.Balance,
+			&user.Roles,
+			&user.IsServiceAccount,
+			&user.CreatedAt,
+		)
+		if err != nil {
+			r.logger.Errorw("scanning user data",
+				"error", err,
+			)
+			return nil, fmt.Errorf("reading user data: %w", err)
+		}
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Errorw("processing query result",
+			"error", err,
+		)
+		return nil, fmt.Errorf("processing query result: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *postgresUserRepository) UpdatePassword(ctx context.Context, userID int, passwordHash string) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		UPDATE users
+		SET password_hash = $1
+		WHERE id = $2
+	`
+
+	result, err := pool.Exec(ctx, query, passwordHash, userID)
+	if err != nil {
+		r.logger.Errorw("updating a user password",
+			"error", err,
+			"userID", userID,
+		)
+		return fmt.Errorf("update user password: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user with ID %d not found", userID)
+	}
+
+	return nil
+}
+
+// ReplacePasswordHash заменяет хэш, только если он не изменился с момента чтения,
+// чтобы пересчёт хэша при входе не затёр параллельную смену пароля.
+func (r *postgresUserRepository) ReplacePasswordHash(ctx context.Context, userID int, oldHash, newHash string) (bool, error) {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		UPDATE users
+		SET password_hash = $1
+		WHERE id = $2 AND password_hash = $3
+	`
+
+	result, err := pool.Exec(ctx, query, newHash, userID, oldHash)
+	if err != nil {
+		r.logger.Errorw("replacing a user password hash",
+			"error", err,
+			"userID", userID,
+		)
+		return false, fmt.Errorf("replace user password hash: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
 func (r *postgresUserRepository) UpdateBalance(ctx context.Context, userID int, newBalance int) error {
 	pool := r.conn.GetExecutor(ctx)
 
@@ -126,3 +392,29 @@ func (r *postgresUserRepository) UpdateBalance(ctx context.Context, userID int,
 
 	return nil
 }
+
+func (r *postgresUserRepository) SetUserRoles(ctx context.Context, userID int, roles []string) error {
+	pool := r.conn.GetExecutor(ctx)
+
+	query := `
+		UPDATE users
+		SET roles = $1
+		WHERE id = $2
+	`
+
+	result, err := pool.Exec(ctx, query, roles, userID)
+	if err != nil {
+		r.logger.Errorw("updating user roles",
+			"error", err,
+			"userID", userID,
+			"roles", roles,
+		)
+		return fmt.Errorf("update user roles: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user with ID %d not found", userID)
+	}
+
+	return nil
+}
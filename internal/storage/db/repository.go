@@ -5,29 +5,150 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"merch-store-grpc/internal/models"
+	"time"
 )
 
 type Repository interface {
 	UserRepository
 	PurchaseRepository
 	TransactionRepository
+	CoinLotRepository
+	LedgerRepository
+	GrantRepository
+	CoinRequestRepository
+	HistoryRepository
+	StatementRepository
+	BalanceRepository
+	AllowanceRepository
+	RefreshTokenRepository
+	APIKeyRepository
+	IdentityRepository
+	TOTPRepository
+	AuditRepository
 }
 
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *models.User) (int, error)
 	GetUserByID(ctx context.Context, userID int) (*models.User, error)
 	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	GetUsersByUsernames(ctx context.Context, usernames []string) ([]*models.User, error)
+	GetUsersByIDs(ctx context.Context, userIDs []int) ([]*models.User, error)
+	LockUsersByIDs(ctx context.Context, userIDs []int) ([]*models.User, error)
+	ListUsers(ctx context.Context, afterID, limit int) ([]*models.User, error)
+	SetUserRoles(ctx context.Context, userID int, roles []string) error
+	UpdatePassword(ctx context.Context, userID int, passwordHash string) error
+	ReplacePasswordHash(ctx context.Context, userID int, oldHash, newHash string) (bool, error)
 	UpdateBalance(ctx context.Context, userID int, newBalance int) error
 }
 
 type PurchaseRepository interface {
 	CreatePurchase(ctx context.Context, purchase *models.Purchase) (int, error)
 	GetPurchaseByUserID(ctx context.Context, userID int) ([]*models.Purchase, error)
+	GetInventoryByUserID(ctx context.Context, userID int) ([]*models.InventoryItem, error)
+	ListPurchases(ctx context.Context, filter *models.PurchaseFilter) ([]*models.Purchase, error)
 }
 
 type TransactionRepository interface {
 	CreateTransaction(ctx context.Context, transaction *models.Transaction) (int, error)
 	GetTransactionByUserID(ctx context.Context, userID int) ([]*models.Transaction, error)
+	GetReceivedByCounterparty(ctx context.Context, userID int) ([]*models.CoinMovement, error)
+	GetSentByCounterparty(ctx context.Context, userID int) ([]*models.CoinMovement, error)
+	ListTransactions(ctx context.Context, filter *models.TransactionFilter) ([]*models.Transaction, error)
+	GetTransactionForUpdate(ctx context.Context, transactionID int) (*models.Transaction, error)
+	GetReversalOf(ctx context.Context, transactionID int) (*models.Transaction, error)
+	CreateTransactionReversal(ctx context.Context, reversal *models.TransactionReversal) (int, error)
+}
+
+type CoinLotRepository interface {
+	CreateCoinLot(ctx context.Context, lot *models.CoinLot) (int, error)
+	ConsumeCoinLots(ctx context.Context, userID int, amount int) error
+	GetOpenCoinLots(ctx context.Context, userID int) ([]*models.CoinLot, error)
+	GetExpiredCoinLots(ctx context.Context, now time.Time, limit int) ([]*models.CoinLot, error)
+	MarkCoinLotExpired(ctx context.Context, lotID int, expiredAt time.Time) error
+}
+
+type BalanceRepository interface {
+	GetBalancesByUserID(ctx context.Context, userID int) (map[string]int, error)
+	GetBalancesByUserIDs(ctx context.Context, userIDs []int, currency string) (map[int]int, error)
+	GetBalanceForUpdate(ctx context.Context, userID int, currency string) (int, error)
+	AddBalance(ctx context.Context, userID int, currency string, delta int) error
+}
+
+type AllowanceRepository interface {
+	GetAllowance(ctx context.Context, userID int) (*models.GiftAllowance, error)
+	GetAllowanceForUpdate(ctx context.Context, userID int) (*models.GiftAllowance, error)
+	SetAllowance(ctx context.Context, userID, balance int, period time.Time) error
+	ResetAllowances(ctx context.Context, amount int, period time.Time) (int, error)
+}
+
+type RefreshTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) (int, error)
+	GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, tokenID int, replacedBy *int, revokedAt time.Time) error
+	RevokeSession(ctx context.Context, sessionID string, revokedAt time.Time) ([]*models.RefreshToken, error)
+	RevokeUserSessions(ctx context.Context, userID int, revokedAt time.Time) ([]*models.RefreshToken, error)
+	CreateSession(ctx context.Context, session *models.Session) error
+	GetSession(ctx context.Context, sessionID string) (*models.Session, error)
+	ListActiveSessions(ctx context.Context, userID int, now time.Time) ([]*models.Session, error)
+	ExtendSession(ctx context.Context, sessionID string, seenAt, expiresAt time.Time) error
+	TouchSession(ctx context.Context, sessionID string, seenAt time.Time, ip, userAgent string) error
+}
+
+type LedgerRepository interface {
+	CreateLedgerEntry(ctx context.Context, entry *models.LedgerEntry) (int, error)
+}
+
+type GrantRepository interface {
+	CreateGrantBatch(ctx context.Context, batch *models.GrantBatch) (int, error)
+	CreateCoinGrant(ctx context.Context, grant *models.CoinGrant) (int, error)
+}
+
+type CoinRequestRepository interface {
+	CreateCoinRequest(ctx context.Context, req *models.CoinRequest) (int, error)
+	GetCoinRequestForUpdate(ctx context.Context, requestID int) (*models.CoinRequest, error)
+	ListCoinRequests(ctx context.Context, userID int, direction, status string) ([]*models.CoinRequest, error)
+	ResolveCoinRequest(ctx context.Context, requestID int, status string, transactionID *int, resolvedAt time.Time) error
+	ExpireCoinRequests(ctx context.Context, now time.Time) (int, error)
+}
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *models.APIKey) (int, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	ListAPIKeys(ctx context.Context, userID int) ([]*models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID int, revokedAt time.Time) (int, bool, error)
+	TouchAPIKey(ctx context.Context, keyID int, usedAt time.Time) error
+}
+
+type IdentityRepository interface {
+	GetUserIdentity(ctx context.Context, issuer, subject string) (*models.UserIdentity, error)
+	CreateUserIdentity(ctx context.Context, identity *models.UserIdentity) error
+	TouchUserIdentity(ctx context.Context, issuer, subject, email string, at time.Time) error
+}
+
+type TOTPRepository interface {
+	GetTOTP(ctx context.Context, userID int) (*models.TOTP, error)
+	SaveTOTPSecret(ctx context.Context, userID int, secret string, at time.Time) (bool, error)
+	UseTOTPCounter(ctx context.Context, userID int, counter int64) (bool, error)
+	EnableTOTP(ctx context.Context, userID int, at time.Time) error
+	DeleteTOTP(ctx context.Context, userID int) error
+	ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string, at time.Time) error
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string, at time.Time) (bool, error)
+}
+
+type AuditRepository interface {
+	CreateAuditEvent(ctx context.Context, event *models.AuditEvent) (int, error)
+	ListAuditEvents(ctx context.Context, filter *models.AuditFilter) ([]*models.AuditEvent, error)
+}
+
+type HistoryRepository interface {
+	StreamHistory(ctx context.Context, userID int, from, to *time.Time, fn func(*models.HistoryRecord) error) error
+}
+
+type StatementRepository interface {
+	GetMovements(ctx context.Context, userID int, from, to time.Time) ([]*models.StatementMovement, error)
+	GetNetMovementSince(ctx context.Context, userID int, since time.Time) (int, error)
+	GetStatement(ctx context.Context, userID int, period time.Time) (*models.Statement, error)
+	SaveStatement(ctx context.Context, statement *models.Statement) error
 }
 
 type Executor interface {
@@ -45,16 +166,55 @@ type postgresRepository struct {
 	UserRepository
 	PurchaseRepository
 	TransactionRepository
+	CoinLotRepository
+	LedgerRepository
+	GrantRepository
+	CoinRequestRepository
+	HistoryRepository
+	StatementRepository
+	BalanceRepository
+	AllowanceRepository
+	RefreshTokenRepository
+	APIKeyRepository
+	IdentityRepository
+	TOTPRepository
+	AuditRepository
 }
 
 func NewRepository(
 	userRepo UserRepository,
 	purchaseRepo PurchaseRepository,
 	transactionRepo TransactionRepository,
+	coinLotRepo CoinLotRepository,
+	ledgerRepo LedgerRepository,
+	grantRepo GrantRepository,
+	coinRequestRepo CoinRequestRepository,
+	historyRepo HistoryRepository,
+	statementRepo StatementRepository,
+	balanceRepo BalanceRepository,
+	allowanceRepo AllowanceRepository,
+	refreshTokenRepo RefreshTokenRepository,
+	apiKeyRepo APIKeyRepository,
+	identityRepo IdentityRepository,
+	totpRepo TOTPRepository,
+	auditRepo AuditRepository,
 ) Repository {
 	return &postgresRepository{
-		UserRepository:        userRepo,
-		PurchaseRepository:    purchaseRepo,
-		TransactionRepository: transactionRepo,
+		UserRepository:         userRepo,
+		PurchaseRepository:     purchaseRepo,
+		TransactionRepository:  transactionRepo,
+		CoinLotRepository:      coinLotRepo,
+		LedgerRepository:       ledgerRepo,
+		GrantRepository:        grantRepo,
+		CoinRequestRepository:  coinRequestRepo,
+		HistoryRepository:      historyRepo,
+		StatementRepository:    statementRepo,
+		BalanceRepository:      balanceRepo,
+		AllowanceRepository:    allowanceRepo,
+		RefreshTokenRepository: refreshTokenRepo,
+		APIKeyRepository:       apiKeyRepo,
+		IdentityRepository:     identityRepo,
+		TOTPRepository:         totpRepo,
+		AuditRepository:        auditRepo,
 	}
 }
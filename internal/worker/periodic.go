@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"context"
+	"merch-store-grpc/pkg/logger"
+	"time"
+)
+
+type Job func(ctx context.Context) error
+
+const defaultInterval = time.Minute
+
+// Periodic запускает задачу сразу после старта и далее с заданным интервалом,
+// пока не будет отменён контекст.
+type Periodic struct {
+	name     string
+	interval time.Duration
+	job      Job
+	logger   logger.Logger
+}
+
+// NewPeriodic создаёт задачу. Неположительный интервал заменяется минутой: с ним
+// time.NewTicker паникует.
+func NewPeriodic(name string, interval time.Duration, job Job, log logger.Logger) *Periodic {
+	if interval <= 0 {
+		log.Warnw("Non-positive interval for periodic job, using default",
+			"job", name,
+			"interval", interval.String(),
+			"default", defaultInterval.String(),
+		)
+		interval = defaultInterval
+	}
+	return &Periodic{name: name, interval: interval, job: job, logger: log}
+}
+
+func (p *Periodic) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.logger.Infow("Starting periodic job", "job", p.name, "interval", p.interval.String())
+
+	for {
+		p.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			p.logger.Infow("Periodic job stopped", "job", p.name)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Periodic) runOnce(ctx context.Context) {
+	start := time.Now()
+	if err := p.job(ctx); err != nil {
+		p.logger.Errorw("periodic job failed",
+			"job", p.name,
+			"error", err,
+		)
+		return
+	}
+	p.logger.Debugw("periodic job finished",
+		"job", p.name,
+		"duration", time.Since(start).String(),
+	)
+}
@@ -0,0 +1,35 @@
+-- +goose Up
+CREATE TABLE coin_lots (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source TEXT NOT NULL,
+    amount INT NOT NULL CHECK (amount > 0),
+    remaining INT NOT NULL CHECK (remaining >= 0 AND remaining <= amount),
+    received_at TIMESTAMP NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP NOT NULL,
+    expired_at TIMESTAMP
+);
+
+CREATE INDEX idx_coin_lots_user_open ON coin_lots (user_id, received_at, id) WHERE remaining > 0;
+CREATE INDEX idx_coin_lots_expires_open ON coin_lots (expires_at) WHERE remaining > 0;
+
+CREATE TABLE ledger_entries (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    entry_type TEXT NOT NULL,
+    amount INT NOT NULL,
+    lot_id INT REFERENCES coin_lots(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX idx_ledger_entries_user ON ledger_entries (user_id, created_at);
+
+-- Существующие балансы переносим в лоты, чтобы сумма remaining совпадала с users.balance
+INSERT INTO coin_lots (user_id, source, amount, remaining, received_at, expires_at)
+SELECT id, 'initial', balance, balance, now(), now() + INTERVAL '12 months'
+FROM users
+WHERE balance > 0;
+
+-- +goose Down
+DROP TABLE ledger_entries;
+DROP TABLE coin_lots;
@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE grant_batches (
+    id SERIAL PRIMARY KEY,
+    granted_by INT REFERENCES users(id) ON DELETE SET NULL,
+    row_count INT NOT NULL,
+    total_amount INT NOT NULL,
+    created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE TABLE coin_grants (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount INT NOT NULL CHECK (amount > 0),
+    reason TEXT NOT NULL,
+    granted_by INT REFERENCES users(id) ON DELETE SET NULL,
+    batch_id INT REFERENCES grant_batches(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT now()
+);
+
+CREATE INDEX idx_coin_grants_user ON coin_grants (user_id, created_at);
+
+-- +goose Down
+DROP TABLE coin_grants;
+DROP TABLE grant_batches;
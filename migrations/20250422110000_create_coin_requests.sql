@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE coin_requests (
+    id SERIAL PRIMARY KEY,
+    requester_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    payer_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount INT NOT NULL CHECK (amount > 0),
+    note TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending',
+    transaction_id INT REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP,
+    CHECK (requester_id <> payer_id)
+);
+
+CREATE INDEX idx_coin_requests_payer ON coin_requests (payer_id, status, created_at);
+CREATE INDEX idx_coin_requests_requester ON coin_requests (requester_id, status, created_at);
+CREATE INDEX idx_coin_requests_pending_expiry ON coin_requests (expires_at) WHERE status = 'pending';
+
+-- +goose Down
+DROP TABLE coin_requests;
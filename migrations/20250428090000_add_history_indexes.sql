@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE INDEX idx_transactions_sender_created ON transactions (sender_id, created_at DESC, id DESC);
+CREATE INDEX idx_transactions_receiver_created ON transactions (receiver_id, created_at DESC, id DESC);
+CREATE INDEX idx_purchases_user_created ON purchases (user_id, created_at DESC, id DESC);
+
+-- +goose Down
+DROP INDEX idx_purchases_user_created;
+DROP INDEX idx_transactions_receiver_created;
+DROP INDEX idx_transactions_sender_created;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE statements (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    period DATE NOT NULL,
+    opening_balance INT NOT NULL,
+    closing_balance INT NOT NULL,
+    totals JSONB NOT NULL,
+    movements JSONB NOT NULL,
+    generated_at TIMESTAMP NOT NULL DEFAULT now(),
+    UNIQUE (user_id, period)
+);
+
+-- +goose Down
+DROP TABLE statements;
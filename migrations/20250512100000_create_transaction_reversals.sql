@@ -0,0 +1,20 @@
+-- +goose Up
+ALTER TABLE transactions ADD COLUMN reversal_of INT REFERENCES transactions(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX idx_transactions_reversal_of ON transactions (reversal_of) WHERE reversal_of IS NOT NULL;
+
+CREATE TABLE transaction_reversals (
+    id SERIAL PRIMARY KEY,
+    original_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    reversal_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    requested_amount INT NOT NULL,
+    reversed_amount INT NOT NULL,
+    reason TEXT NOT NULL,
+    reversed_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE transaction_reversals;
+DROP INDEX idx_transactions_reversal_of;
+ALTER TABLE transactions DROP COLUMN reversal_of;
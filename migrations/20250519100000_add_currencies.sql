@@ -0,0 +1,17 @@
+-- +goose Up
+-- Баланс основной валюты (coin) по-прежнему хранится в users.balance: на него завязаны
+-- лоты сгорания, выписки и сверка с Redis. Остальные валюты — в user_balances.
+CREATE TABLE user_balances (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    currency TEXT NOT NULL,
+    balance INT NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    PRIMARY KEY (user_id, currency)
+);
+
+ALTER TABLE transactions ADD COLUMN currency TEXT NOT NULL DEFAULT 'coin';
+ALTER TABLE purchases ADD COLUMN currency TEXT NOT NULL DEFAULT 'coin';
+
+-- +goose Down
+ALTER TABLE purchases DROP COLUMN currency;
+ALTER TABLE transactions DROP COLUMN currency;
+DROP TABLE user_balances;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Ежемесячный лимит на подарки: монеты можно только передать коллегам, в конце месяца остаток сгорает.
+CREATE TABLE gift_allowances (
+    user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    balance INT NOT NULL CHECK (balance >= 0),
+    period DATE NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_gift_allowances_period ON gift_allowances (period);
+
+-- Часть amount, пришедшая из лимита на подарки отправителя (для отката — вернувшаяся в лимит
+-- получателя отката). На users.balance эта часть не влияет.
+ALTER TABLE transactions ADD COLUMN allowance_amount INT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE transactions DROP COLUMN allowance_amount;
+DROP TABLE gift_allowances;
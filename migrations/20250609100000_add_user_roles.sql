@@ -0,0 +1,11 @@
+-- +goose Up
+-- Роли пользователя: user | store_admin | finance | support. Попадают в JWT,
+-- доступ к методам проверяет interceptor по правилам из proto (access_policy).
+ALTER TABLE users ADD COLUMN roles TEXT[] NOT NULL DEFAULT '{user}'
+    CHECK (roles <@ ARRAY['user', 'store_admin', 'finance', 'support']::TEXT[]);
+
+-- Раньше администраторы задавались в конфиге (admin.usernames, по умолчанию admin).
+UPDATE users SET roles = ARRAY['user', 'store_admin', 'finance']::TEXT[] WHERE username = 'admin';
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN roles;
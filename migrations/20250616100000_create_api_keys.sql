@@ -0,0 +1,25 @@
+-- +goose Up
+-- Сервисные аккаунты (боты, интеграции) — обычные пользователи без пароля.
+ALTER TABLE users ADD COLUMN is_service_account BOOLEAN NOT NULL DEFAULT false;
+
+-- API-ключи сервисных аккаунтов. Сам ключ хранится только как SHA-256, prefix —
+-- открытая часть ключа для поиска и опознания в логах и списках.
+CREATE TABLE api_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL UNIQUE,
+    key_hash TEXT NOT NULL,
+    scopes TEXT[] NOT NULL,
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_api_keys_user ON api_keys (user_id);
+
+-- +goose Down
+DROP TABLE api_keys;
+ALTER TABLE users DROP COLUMN is_service_account;
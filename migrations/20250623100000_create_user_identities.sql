@@ -0,0 +1,17 @@
+-- +goose Up
+-- Внешние учётные записи (OIDC), через которые входит пользователь. Пользователь
+-- определяется парой issuer + sub: email у провайдера может смениться, sub — нет.
+CREATE TABLE user_identities (
+    issuer TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    last_login_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (issuer, subject)
+);
+
+CREATE INDEX idx_user_identities_user ON user_identities (user_id);
+
+-- +goose Down
+DROP TABLE user_identities;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- TOTP второго фактора. enabled_at пуст, пока пользователь не подтвердил подключение
+-- кодом; last_counter — последний принятый шаг, чтобы код нельзя было использовать дважды.
+CREATE TABLE user_totp (
+    user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    enabled_at TIMESTAMP,
+    last_counter BIGINT NOT NULL DEFAULT 0
+);
+
+-- Одноразовые коды восстановления, только SHA-256.
+CREATE TABLE totp_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    used_at TIMESTAMP
+);
+
+CREATE INDEX idx_totp_recovery_codes_user ON totp_recovery_codes (user_id);
+
+-- +goose Down
+DROP TABLE totp_recovery_codes;
+DROP TABLE user_totp;
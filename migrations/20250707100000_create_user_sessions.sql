@@ -0,0 +1,26 @@
+-- +goose Up
+-- Сессии входа: устройство и адрес, с которых выполнен вход, и время последней
+-- активности (обновляется при выдаче токенов). id совпадает с refresh_tokens.session_id
+-- и claim sid в access-токене.
+CREATE TABLE user_sessions (
+    id TEXT PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    last_seen_at TIMESTAMP NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX idx_user_sessions_user ON user_sessions (user_id) WHERE revoked_at IS NULL;
+
+-- Сессии, открытые до появления таблицы, — без устройства и адреса.
+INSERT INTO user_sessions (id, user_id, created_at, last_seen_at, expires_at, revoked_at)
+SELECT session_id, user_id, MIN(created_at), MAX(created_at), MAX(expires_at),
+       CASE WHEN bool_and(revoked_at IS NOT NULL) THEN MAX(revoked_at) END
+FROM refresh_tokens
+GROUP BY session_id, user_id;
+
+-- +goose Down
+DROP TABLE user_sessions;
@@ -0,0 +1,44 @@
+-- +goose Up
+-- Журнал аудита: входы, смена пароля и движения монет. Записи только добавляются —
+-- изменить или удалить их не дают триггеры. Ссылок на users нет, чтобы запись
+-- пережила удаление пользователя.
+CREATE TABLE audit_log (
+    id SERIAL PRIMARY KEY,
+    event_type TEXT NOT NULL,
+    actor_id INT,
+    target_id INT,
+    ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT '',
+    currency TEXT,
+    amount INT,
+    balance_before INT,
+    balance_after INT,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_audit_log_created ON audit_log (created_at DESC, id DESC);
+CREATE INDEX idx_audit_log_actor ON audit_log (actor_id, created_at DESC);
+CREATE INDEX idx_audit_log_target ON audit_log (target_id, created_at DESC);
+CREATE INDEX idx_audit_log_request ON audit_log (request_id) WHERE request_id <> '';
+
+-- +goose StatementBegin
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER audit_log_no_update
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
+
+-- +goose Down
+DROP TABLE audit_log;
+DROP FUNCTION audit_log_append_only();
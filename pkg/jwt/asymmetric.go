@@ -0,0 +1,92 @@
+package jwt
+
+import (
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"sort"
+)
+
+// asymmetricTokenService подписывает токены закрытым ключом signingKey (RS256 или EdDSA)
+// и проверяет подпись любым из keys по заголовку kid. Старые ключи можно оставить
+// в keys только с публичной частью, пока не истекут выданные ими токены.
+type asymmetricTokenService struct {
+	signingKey          *Key
+	keys                map[string]*Key
+	algorithms          []string
+	tokenExpirationTime int
+}
+
+func NewAsymmetricTokenService(keys []*Key, signingKeyID string, tokenExpTime int) (TokenService, error) {
+	s := &asymmetricTokenService{
+		keys:                make(map[string]*Key, len(keys)),
+		tokenExpirationTime: tokenExpTime,
+	}
+
+	algorithms := make(map[string]struct{})
+	for _, k := range keys {
+		if _, ok := s.keys[k.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %s", k.ID)
+		}
+		s.keys[k.ID] = k
+		algorithms[k.Algorithm] = struct{}{}
+	}
+	for alg := range algorithms {
+		s.algorithms = append(s.algorithms, alg)
+	}
+	sort.Strings(s.algorithms)
+
+	signingKey, ok := s.keys[signingKeyID]
+	if !ok {
+		return nil, fmt.Errorf("signing key %q not found", signingKeyID)
+	}
+	if signingKey.Private == nil {
+		return nil, fmt.Errorf("signing key %q has no private key", signingKeyID)
+	}
+	s.signingKey = signingKey
+
+	return s, nil
+}
+
+func (s *asymmetricTokenService) GenerateToken(userID int, sessionID string, roles []string) (string, *Claims, error) {
+	mapClaims, claims, err := newClaims(userID, sessionID, roles, s.tokenExpirationTime)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(s.signingKey.Algorithm), mapClaims)
+	token.Header["kid"] = s.signingKey.ID
+
+	signed, err := token.SignedString(s.signingKey.Private)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
+}
+
+func (s *asymmetricTokenService) ParseJWTToken(tokenString string) (*Claims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := s.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("algorithm %s does not match key %s", token.Method.Alg(), kid)
+		}
+		return key.Public, nil
+	}, jwt.WithValidMethods(s.algorithms))
+
+	if err != nil {
+		return nil, err
+	}
+	return claimsFromToken(token)
+}
+
+func (s *asymmetricTokenService) JWKS() *JWKSet {
+	set := &JWKSet{Keys: make([]JWK, 0, len(s.keys))}
+	for _, k := range s.keys {
+		set.Keys = append(set.Keys, k.JWK())
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
@@ -1,14 +1,27 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"time"
 )
 
+// Claims — данные access-токена.
+type Claims struct {
+	UserID    int
+	ID        string
+	SessionID string
+	Roles     []string
+	ExpiresAt time.Time
+}
+
 type TokenService interface {
-	GenerateToken(userID int) (string, error)
-	ParseJWTToken(tokenString string) (int, error)
+	GenerateToken(userID int, sessionID string, roles []string) (string, *Claims, error)
+	ParseJWTToken(tokenString string) (*Claims, error)
+	// JWKS возвращает публичные ключи проверки подписи; для HS256 набор пуст.
+	JWKS() *JWKSet
 }
 
 type TokenServiceImpl struct {
@@ -20,34 +33,98 @@ func NewTokenService(secretKey string, tokenExpTime int) TokenService {
 	return &TokenServiceImpl{secretKey: secretKey, tokenExpirationTime: tokenExpTime}
 }
 
-func (t *TokenServiceImpl) GenerateToken(userID int) (string, error) {
+func (t *TokenServiceImpl) GenerateToken(userID int, sessionID string, roles []string) (string, *Claims, error) {
+	mapClaims, claims, err := newClaims(userID, sessionID, roles, t.tokenExpirationTime)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, mapClaims)
+
+	signed, err := token.SignedString([]byte(t.secretKey))
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
+}
+
+func (t *TokenServiceImpl) ParseJWTToken(tokenString string) (*Claims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return []byte(t.secretKey), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+	if err != nil {
+		return nil, err
+	}
+	return claimsFromToken(token)
+}
+
+func (t *TokenServiceImpl) JWKS() *JWKSet {
+	return &JWKSet{Keys: []JWK{}}
+}
+
+func newClaims(userID int, sessionID string, roles []string, expirationTime int) (jwt.MapClaims, *Claims, error) {
 	now := time.Now()
 
-	expiration := now.Add(time.Duration(t.tokenExpirationTime) * time.Second)
+	expiration := now.Add(time.Duration(expirationTime) * time.Second)
+
+	id, err := NewTokenID()
+	if err != nil {
+		return nil, nil, err
+	}
 
-	claims := jwt.MapClaims{
+	mapClaims := jwt.MapClaims{
 		"user_id": userID,
+		"jti":     id,
+		"iat":     now.Unix(),
 		"exp":     expiration.Unix(),
+		"roles":   roles,
+	}
+	if sessionID != "" {
+		mapClaims["sid"] = sessionID
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	return token.SignedString([]byte(t.secretKey))
+	return mapClaims, &Claims{
+		UserID:    userID,
+		ID:        id,
+		SessionID: sessionID,
+		Roles:     roles,
+		ExpiresAt: time.Unix(expiration.Unix(), 0),
+	}, nil
 }
 
-func (t *TokenServiceImpl) ParseJWTToken(tokenString string) (int, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(t.secretKey), nil
-	})
+func claimsFromToken(token *jwt.Token) (*Claims, error) {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
 
-	if err != nil {
-		return 0, err
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("invalid token: missing user_id")
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID := int(claims["user_id"].(float64))
-		return userID, nil
+	result := &Claims{UserID: int(userID)}
+	result.ID, _ = claims["jti"].(string)
+	result.SessionID, _ = claims["sid"].(string)
+	if roles, ok := claims["roles"].([]interface{}); ok {
+		for _, role := range roles {
+			if r, ok := role.(string); ok {
+				result.Roles = append(result.Roles, r)
+			}
+		}
 	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		result.ExpiresAt = exp.Time
+	}
+	return result, nil
+}
 
-	return 0, fmt.Errorf("invalid token")
+// NewTokenID возвращает случайный идентификатор для jti и сессий.
+func NewTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate token id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
 }
@@ -8,6 +8,8 @@ import (
 type PasswordHasher interface {
 	Hash(password string) (string, error)
 	Check(hashedPassword, password string) bool
+	// NeedsRehash сообщает, что хэш надо пересчитать текущим алгоритмом или параметрами.
+	NeedsRehash(hashedPassword string) bool
 }
 
 type BCryptHasher struct {
@@ -33,3 +35,11 @@ func (h *BCryptHasher) Check(hashedPassword, password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 	return err == nil
 }
+
+func (h *BCryptHasher) NeedsRehash(hashedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	if err != nil {
+		return true
+	}
+	return cost != h.cost
+}
@@ -0,0 +1,58 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+// Algorithm определяет алгоритм по формату сохранённого хэша; "" — формат неизвестен.
+func Algorithm(hashedPassword string) string {
+	switch {
+	case strings.HasPrefix(hashedPassword, "$argon2id$"):
+		return AlgorithmArgon2id
+	case strings.HasPrefix(hashedPassword, "$2a$"), strings.HasPrefix(hashedPassword, "$2b$"),
+		strings.HasPrefix(hashedPassword, "$2y$"):
+		return AlgorithmBcrypt
+	default:
+		return ""
+	}
+}
+
+// MultiHasher хэширует новые пароли текущим алгоритмом, а проверяет хэши любого
+// из известных. NeedsRehash сообщает, что хэш сделан другим алгоритмом или
+// с устаревшими параметрами.
+type MultiHasher struct {
+	current string
+	hashers map[string]PasswordHasher
+}
+
+func NewMultiHasher(current string, hashers map[string]PasswordHasher) (PasswordHasher, error) {
+	if _, ok := hashers[current]; !ok {
+		return nil, fmt.Errorf("no hasher for algorithm %q", current)
+	}
+	return &MultiHasher{current: current, hashers: hashers}, nil
+}
+
+func (h *MultiHasher) Hash(password string) (string, error) {
+	return h.hashers[h.current].Hash(password)
+}
+
+func (h *MultiHasher) Check(hashedPassword, password string) bool {
+	hasher, ok := h.hashers[Algorithm(hashedPassword)]
+	if !ok {
+		return false
+	}
+	return hasher.Check(hashedPassword, password)
+}
+
+func (h *MultiHasher) NeedsRehash(hashedPassword string) bool {
+	if Algorithm(hashedPassword) != h.current {
+		return true
+	}
+	return h.hashers[h.current].NeedsRehash(hashedPassword)
+}
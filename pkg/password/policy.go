@@ -0,0 +1,107 @@
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed common_passwords.txt
+var commonPasswords string
+
+const defaultMinLength = 8
+
+// Policy — требования к новому паролю: длина, число классов символов
+// (строчные, заглавные, цифры, прочие) и запрет распространённых паролей.
+type Policy struct {
+	minLength  int
+	minClasses int
+	denyList   map[string]struct{}
+}
+
+// NewPolicy строит политику. К встроенному списку распространённых паролей
+// добавляются строки из denyListFile, если он задан.
+func NewPolicy(minLength, minClasses int, denyListFile string) (*Policy, error) {
+	if minLength <= 0 {
+		minLength = defaultMinLength
+	}
+	if minClasses > 4 {
+		minClasses = 4
+	}
+
+	p := &Policy{minLength: minLength, minClasses: minClasses, denyList: make(map[string]struct{})}
+	p.addDenied(strings.NewReader(commonPasswords))
+
+	if denyListFile != "" {
+		f, err := os.Open(denyListFile)
+		if err != nil {
+			return nil, fmt.Errorf("open password deny list: %w", err)
+		}
+		defer f.Close()
+		if err := p.addDenied(f); err != nil {
+			return nil, fmt.Errorf("read password deny list: %w", err)
+		}
+	}
+
+	return p, nil
+}
+
+func (p *Policy) addDenied(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			p.denyList[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return scanner.Err()
+}
+
+// Validate возвращает описание первого нарушенного требования или nil.
+func (p *Policy) Validate(password, username string) error {
+	if utf8.RuneCountInString(password) < p.minLength {
+		return fmt.Errorf("password must be at least %d characters", p.minLength)
+	}
+
+	if classes := characterClasses(password); classes < p.minClasses {
+		return fmt.Errorf("password must contain at least %d of: lowercase letters, uppercase letters, digits, symbols", p.minClasses)
+	}
+
+	lower := strings.ToLower(password)
+	if _, ok := p.denyList[lower]; ok {
+		return fmt.Errorf("password is too common")
+	}
+	if username != "" && strings.Contains(lower, strings.ToLower(username)) {
+		return fmt.Errorf("password must not contain the username")
+	}
+
+	return nil
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	n := 0
+	for _, ok := range []bool{lower, upper, digit, other} {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
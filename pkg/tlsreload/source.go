@@ -0,0 +1,159 @@
+package tlsreload
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"merch-store-grpc/pkg/logger"
+	"os"
+	"sync"
+	"time"
+)
+
+// Source — сертификат, ключ и CA из файлов, которые перечитываются при изменении.
+// Новые соединения сразу получают новый сертификат, установленные не разрываются.
+// Если обновлённые файлы не читаются (например, ключ ещё не дописан), остаётся прежний сертификат.
+type Source struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	pool     *x509.CertPool
+	modTimes [3]time.Time
+}
+
+// New загружает файлы. certFile и keyFile задаются вместе; caFile необязателен.
+func New(certFile, keyFile, caFile string) (*Source, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("cert_file and key_file must be set together")
+	}
+	s := &Source{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload перечитывает файлы, если время их изменения поменялось, и сообщает, было ли обновление.
+func (s *Source) Reload() (bool, error) {
+	modTimes, err := s.statFiles()
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.RLock()
+	unchanged := s.modTimes == modTimes && (s.cert != nil || s.certFile == "") && (s.pool != nil || s.caFile == "")
+	s.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	var cert *tls.Certificate
+	if s.certFile != "" {
+		loaded, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+		if err != nil {
+			return false, fmt.Errorf("load certificate: %w", err)
+		}
+		cert = &loaded
+	}
+
+	var pool *x509.CertPool
+	if s.caFile != "" {
+		pem, err := os.ReadFile(s.caFile)
+		if err != nil {
+			return false, fmt.Errorf("read ca file: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return false, fmt.Errorf("no certificates in %s", s.caFile)
+		}
+	}
+
+	s.mu.Lock()
+	s.cert = cert
+	s.pool = pool
+	s.modTimes = modTimes
+	s.mu.Unlock()
+	return true, nil
+}
+
+// Watch проверяет файлы каждые interval до отмены ctx.
+func (s *Source) Watch(ctx context.Context, interval time.Duration, log logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := s.Reload()
+			if err != nil {
+				log.Errorw("reload TLS certificate", "certFile", s.certFile, "error", err)
+				continue
+			}
+			if reloaded {
+				log.Infow("TLS certificate reloaded", "certFile", s.certFile)
+			}
+		}
+	}
+}
+
+// ServerConfig — конфигурация слушателя. При заданном CA сертификаты клиентов проверяются
+// по нему с режимом clientAuth.
+func (s *Source) ServerConfig(clientAuth tls.ClientAuthType) *tls.Config {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+
+		c := cfg.Clone()
+		c.GetConfigForClient = nil
+		c.Certificates = []tls.Certificate{*s.cert}
+		if s.pool != nil {
+			c.ClientCAs = s.pool
+			c.ClientAuth = clientAuth
+		}
+		return c, nil
+	}
+	return cfg
+}
+
+// ClientConfig — конфигурация исходящего соединения: CA проверяет сервер, сертификат
+// (если задан) предъявляется для mTLS и обновляется на лету. CA фиксируется при вызове.
+func (s *Source) ClientConfig(serverName string) *tls.Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		RootCAs:    s.pool,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			s.mu.RLock()
+			defer s.mu.RUnlock()
+			if s.cert == nil {
+				return &tls.Certificate{}, nil
+			}
+			return s.cert, nil
+		},
+	}
+}
+
+func (s *Source) statFiles() ([3]time.Time, error) {
+	var modTimes [3]time.Time
+	for i, name := range []string{s.certFile, s.keyFile, s.caFile} {
+		if name == "" {
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return modTimes, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}